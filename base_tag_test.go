@@ -256,6 +256,18 @@ func TestBaseTag_WriteNDEF(t *testing.T) {
 	assert.Equal(t, ErrNotImplemented, err)
 }
 
+func TestReadOnlyMaker(t *testing.T) {
+	t.Parallel()
+
+	tags := []Tag{&NTAGTag{}, &MIFARETag{}, &FeliCaTag{}}
+	for _, tag := range tags {
+		_, ok := tag.(ReadOnlyMaker)
+		assert.True(t, ok, "%T", tag)
+	}
+	_, ok := any(&BaseTag{}).(ReadOnlyMaker)
+	assert.False(t, ok)
+}
+
 func TestBaseTag_ReadText(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,83 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package pn532
+
+import (
+	"context"
+	"time"
+)
+
+// Serial baud rates supported by the PN532 SetSerialBaudRate command
+const (
+	SerialBaudRate9600    = 9600
+	SerialBaudRate19200   = 19200
+	SerialBaudRate38400   = 38400
+	SerialBaudRate57600   = 57600
+	SerialBaudRate115200  = 115200
+	SerialBaudRate230400  = 230400
+	SerialBaudRate460800  = 460800
+	SerialBaudRate921600  = 921600
+	SerialBaudRate1288000 = 1288000
+
+	// DefaultSerialBaudRate is the rate the PN532 HSU uses after power-on or reset
+	DefaultSerialBaudRate = SerialBaudRate115200
+)
+
+// baudRateSwitchDelay is how long to wait before talking at a new baud rate
+const baudRateSwitchDelay = 10 * time.Millisecond
+
+// baudRateVerifyAttempts is how many times the link is checked at a new baud
+// rate before the switch is considered failed
+const baudRateVerifyAttempts = 3
+
+// serialBaudRateCodes maps baud rates to the BR parameter of SetSerialBaudRate
+var serialBaudRateCodes = map[int]byte{
+	SerialBaudRate9600:    0x00,
+	SerialBaudRate19200:   0x01,
+	SerialBaudRate38400:   0x02,
+	SerialBaudRate57600:   0x03,
+	SerialBaudRate115200:  0x04,
+	SerialBaudRate230400:  0x05,
+	SerialBaudRate460800:  0x06,
+	SerialBaudRate921600:  0x07,
+	SerialBaudRate1288000: 0x08,
+}
+
+// IsValidSerialBaudRate returns true if the PN532 can switch its HSU to the given rate
+func IsValidSerialBaudRate(baudRate int) bool {
+	_, ok := serialBaudRateCodes[baudRate]
+	return ok
+}
+
+// BaudRateConfigurer is implemented by serial transports whose host-side line
+// speed can be changed to follow a SetSerialBaudRate command
+type BaudRateConfigurer interface {
+	// BaudRate returns the current host-side baud rate
+	BaudRate() int
+
+	// SetBaudRate switches the host-side port to the given baud rate
+	SetBaudRate(baudRate int) error
+}
+
+// SetSerialBaudRate negotiates a new HSU baud rate with the PN532
+func (d *Device) SetSerialBaudRate(baudRate int) error {
+	return d.SetSerialBaudRateContext(context.Background(), baudRate)
+}
@@ -0,0 +1,259 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package pn532
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	testutil "github.com/ZaparooProject/go-pn532/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// baudRateMockTransport is a MockTransport that also tracks a host baud rate.
+// GetFirmwareVersion only succeeds while the host rate matches deviceRate,
+// simulating a PN532 that is (or is not) listening at the host's rate. The
+// first deafChecks firmware checks fail regardless, like a slow or lost PN532.
+type baudRateMockTransport struct {
+	*MockTransport
+	setErr     error
+	history    []int
+	hostRate   int
+	deviceRate int
+	deafChecks int
+	mu         sync.Mutex
+}
+
+func newBaudRateMockTransport() *baudRateMockTransport {
+	mock := NewMockTransport()
+	mock.SetResponse(cmdGetFirmwareVersion, testutil.BuildFirmwareVersionResponse())
+	mock.SetResponse(cmdSetSerialBaudRate, []byte{cmdSetSerialBaudRate + 1})
+	return &baudRateMockTransport{
+		MockTransport: mock,
+		hostRate:      DefaultSerialBaudRate,
+		deviceRate:    DefaultSerialBaudRate,
+	}
+}
+
+func (m *baudRateMockTransport) SendCommandWithContext(ctx context.Context, cmd byte, args []byte) ([]byte, error) {
+	m.mu.Lock()
+	listening := m.hostRate == m.deviceRate
+	if cmd == cmdGetFirmwareVersion && m.deafChecks > 0 {
+		m.deafChecks--
+		listening = false
+	}
+	m.mu.Unlock()
+	if cmd == cmdGetFirmwareVersion && !listening {
+		return nil, NewTimeoutError("GetFirmwareVersion", "mock")
+	}
+	return m.MockTransport.SendCommandWithContext(ctx, cmd, args)
+}
+
+func (m *baudRateMockTransport) BaudRate() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.hostRate
+}
+
+func (m *baudRateMockTransport) SetBaudRate(baudRate int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.setErr != nil {
+		return m.setErr
+	}
+	m.hostRate = baudRate
+	m.history = append(m.history, baudRate)
+	return nil
+}
+
+func TestIsValidSerialBaudRate(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, IsValidSerialBaudRate(SerialBaudRate9600))
+	assert.True(t, IsValidSerialBaudRate(SerialBaudRate115200))
+	assert.True(t, IsValidSerialBaudRate(SerialBaudRate1288000))
+	assert.False(t, IsValidSerialBaudRate(0))
+	assert.False(t, IsValidSerialBaudRate(1228800))
+}
+
+func TestDevice_SetSerialBaudRate_Success(t *testing.T) {
+	t.Parallel()
+
+	mock := newBaudRateMockTransport()
+	mock.deviceRate = SerialBaudRate921600 // PN532 follows the command
+	device, err := New(mock)
+	require.NoError(t, err)
+
+	err = device.SetSerialBaudRateContext(context.Background(), SerialBaudRate921600)
+	require.NoError(t, err)
+
+	assert.Equal(t, SerialBaudRate921600, mock.BaudRate())
+	assert.Equal(t, []int{SerialBaudRate921600}, mock.history)
+	assert.Equal(t, 1, mock.GetCallCount(cmdSetSerialBaudRate))
+}
+
+func TestDevice_SetSerialBaudRate_ThroughRetryWrapper(t *testing.T) {
+	t.Parallel()
+
+	mock := newBaudRateMockTransport()
+	mock.deviceRate = SerialBaudRate460800
+	device, err := New(NewTransportWithRetry(mock, nil))
+	require.NoError(t, err)
+
+	require.NoError(t, device.SetSerialBaudRate(SerialBaudRate460800))
+	assert.Equal(t, SerialBaudRate460800, mock.BaudRate())
+}
+
+func TestDevice_SetSerialBaudRate_SameRateIsNoop(t *testing.T) {
+	t.Parallel()
+
+	mock := newBaudRateMockTransport()
+	device, err := New(mock)
+	require.NoError(t, err)
+
+	require.NoError(t, device.SetSerialBaudRate(DefaultSerialBaudRate))
+	assert.Equal(t, 0, mock.GetCallCount(cmdSetSerialBaudRate))
+	assert.Empty(t, mock.history)
+}
+
+func TestDevice_SetSerialBaudRate_RevertsOnVerifyFailure(t *testing.T) {
+	t.Parallel()
+
+	// PN532 ignores the switch and stays at 115200
+	mock := newBaudRateMockTransport()
+	device, err := New(mock)
+	require.NoError(t, err)
+
+	err = device.SetSerialBaudRate(SerialBaudRate921600)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "reverted to 115200")
+
+	assert.Equal(t, DefaultSerialBaudRate, mock.BaudRate())
+	assert.Equal(t, []int{SerialBaudRate921600, DefaultSerialBaudRate}, mock.history)
+}
+
+func TestDevice_SetSerialBaudRate_RetriesCheckAtNewRate(t *testing.T) {
+	t.Parallel()
+
+	// PN532 switched but misses the first check at the new rate
+	mock := newBaudRateMockTransport()
+	mock.deviceRate = SerialBaudRate921600
+	mock.deafChecks = baudRateVerifyAttempts - 1
+	device, err := New(mock)
+	require.NoError(t, err)
+
+	require.NoError(t, device.SetSerialBaudRate(SerialBaudRate921600))
+	assert.Equal(t, SerialBaudRate921600, mock.BaudRate())
+	assert.Equal(t, []int{SerialBaudRate921600}, mock.history)
+}
+
+func TestDevice_SetSerialBaudRate_MismatchReportsNewRate(t *testing.T) {
+	t.Parallel()
+
+	// PN532 switched but never answers, at either rate
+	mock := newBaudRateMockTransport()
+	mock.deviceRate = SerialBaudRate921600
+	mock.deafChecks = baudRateVerifyAttempts + 1
+	device, err := New(mock)
+	require.NoError(t, err)
+
+	err = device.SetSerialBaudRate(SerialBaudRate921600)
+	require.ErrorIs(t, err, ErrBaudRateMismatch)
+	assert.Contains(t, err.Error(), "may be at 921600")
+	assert.Zero(t, mock.deafChecks, "checked at both rates")
+}
+
+func TestDevice_SetSerialBaudRate_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		setup     func(*baudRateMockTransport)
+		wantErrIs error
+		name      string
+		wantSub   string
+		baudRate  int
+	}{
+		{
+			name:      "Unsupported_Rate",
+			baudRate:  1228800,
+			wantErrIs: ErrInvalidParameter,
+		},
+		{
+			name:     "Command_Fails",
+			baudRate: SerialBaudRate230400,
+			setup: func(m *baudRateMockTransport) {
+				m.SetError(cmdSetSerialBaudRate, errors.New("no ACK"))
+			},
+			wantSub: "SetSerialBaudRate command failed",
+		},
+		{
+			name:     "Unexpected_Response",
+			baudRate: SerialBaudRate230400,
+			setup: func(m *baudRateMockTransport) {
+				m.SetResponse(cmdSetSerialBaudRate, []byte{0x7F})
+			},
+			wantSub: "unexpected SetSerialBaudRate response",
+		},
+		{
+			name:     "Host_Switch_Fails",
+			baudRate: SerialBaudRate230400,
+			setup: func(m *baudRateMockTransport) {
+				m.setErr = errors.New("ioctl failed")
+			},
+			wantSub: "failed to switch host baud rate",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := newBaudRateMockTransport()
+			if tt.setup != nil {
+				tt.setup(mock)
+			}
+			device, err := New(mock)
+			require.NoError(t, err)
+
+			err = device.SetSerialBaudRate(tt.baudRate)
+			require.Error(t, err)
+			if tt.wantErrIs != nil {
+				require.ErrorIs(t, err, tt.wantErrIs)
+			}
+			if tt.wantSub != "" {
+				assert.Contains(t, err.Error(), tt.wantSub)
+			}
+			assert.Equal(t, DefaultSerialBaudRate, mock.BaudRate())
+		})
+	}
+}
+
+func TestDevice_SetSerialBaudRate_UnsupportedTransport(t *testing.T) {
+	t.Parallel()
+
+	device, _ := createMockDeviceWithTransport(t)
+
+	err := device.SetSerialBaudRate(SerialBaudRate921600)
+	require.ErrorIs(t, err, ErrDeviceNotSupported)
+}
@@ -0,0 +1,155 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package main
+
+import (
+	"encoding/hex"
+	"sync"
+	"time"
+
+	pn532 "github.com/ZaparooProject/go-pn532"
+	"github.com/ZaparooProject/go-pn532/polling"
+)
+
+// subscriberBuffer is how many events a stream client may fall behind
+// before events are dropped for it
+const subscriberBuffer = 32
+
+// eventView is the JSON form of a session event
+type eventView struct {
+	Time    time.Time    `json:"time"`
+	Event   string       `json:"event"`
+	Reader  string       `json:"reader,omitempty"`
+	UID     string       `json:"uid,omitempty"`
+	TagType string       `json:"tag_type,omitempty"`
+	Error   string       `json:"error,omitempty"`
+	Health  string       `json:"health,omitempty"`
+	Records []recordView `json:"records,omitempty"`
+}
+
+func newEventView(event *polling.Event) *eventView {
+	view := &eventView{
+		Time:   event.Time,
+		Event:  event.Type.String(),
+		Reader: event.Reader,
+		UID:    event.UID,
+	}
+	if event.Tag != nil {
+		view.TagType = string(event.Tag.Type)
+		if view.UID == "" {
+			view.UID = event.Tag.UID
+		}
+	}
+	if event.Err != nil {
+		view.Error = event.Err.Error()
+	}
+	if event.Type == polling.EventHealth {
+		view.Health = event.Health.String()
+	}
+	if event.Message != nil {
+		view.Records = newRecordViews(event.Message)
+	}
+	return view
+}
+
+// recordView is the JSON form of an NDEF record, also accepted by the write
+// endpoint. Payloads of records without a decoded form are hex encoded.
+type recordView struct {
+	Type    string `json:"type"`
+	Text    string `json:"text,omitempty"`
+	URI     string `json:"uri,omitempty"`
+	Payload string `json:"payload,omitempty"`
+}
+
+func newRecordViews(msg *pn532.NDEFMessage) []recordView {
+	views := make([]recordView, 0, len(msg.Records))
+	for i := range msg.Records {
+		rec := &msg.Records[i]
+		view := recordView{Type: string(rec.Type)}
+		switch rec.Type {
+		case pn532.NDEFTypeText:
+			view.Text = rec.Text
+		case pn532.NDEFTypeURI:
+			view.URI = rec.URI
+		default:
+			view.Payload = hex.EncodeToString(rec.Payload)
+		}
+		views = append(views, view)
+	}
+	return views
+}
+
+// tagView is the last tag the reader saw
+type tagView struct {
+	DetectedAt time.Time    `json:"detected_at"`
+	RemovedAt  *time.Time   `json:"removed_at,omitempty"`
+	UID        string       `json:"uid"`
+	Type       string       `json:"type"`
+	ReadError  string       `json:"read_error,omitempty"`
+	Records    []recordView `json:"records"`
+	Present    bool         `json:"present"`
+}
+
+// broker fans events out to the stream clients. A client that doesn't keep
+// up loses events instead of stalling the others.
+type broker struct {
+	subscribers map[chan *eventView]struct{}
+	mu          sync.Mutex
+	dropped     uint64
+}
+
+func newBroker() *broker {
+	return &broker{subscribers: make(map[chan *eventView]struct{})}
+}
+
+// subscribe registers a client, the returned function unregisters it
+func (b *broker) subscribe() (events <-chan *eventView, unsubscribe func()) {
+	ch := make(chan *eventView, subscriberBuffer)
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	return ch, func() {
+		b.mu.Lock()
+		delete(b.subscribers, ch)
+		b.mu.Unlock()
+	}
+}
+
+// publish sends an event to every client
+func (b *broker) publish(event *eventView) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+			b.dropped++
+		}
+	}
+}
+
+// stats returns the number of clients and of events dropped for slow ones
+func (b *broker) stats() (clients int, dropped uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subscribers), b.dropped
+}
@@ -0,0 +1,314 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+// Command pn532d bridges a PN532 reader to web front-ends. It polls the reader
+// and serves a local HTTP API:
+//
+//	GET  /api/status  reader, health and the tag currently on the reader
+//	GET  /api/tag     the last tag seen and its NDEF records
+//	POST /api/write   write text or URI records to the next tag
+//	GET  /api/events  server-sent event stream of tag events
+//
+// With a shared secret set, clients send it as a bearer token, or as the
+// token query parameter from an EventSource. Web pages may only call the API
+// from the origins given with -allow-origin.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	pn532 "github.com/ZaparooProject/go-pn532"
+	"github.com/ZaparooProject/go-pn532/detection"
+	_ "github.com/ZaparooProject/go-pn532/detection/i2c"
+	_ "github.com/ZaparooProject/go-pn532/detection/spi"
+	_ "github.com/ZaparooProject/go-pn532/detection/uart"
+	"github.com/ZaparooProject/go-pn532/polling"
+	"github.com/ZaparooProject/go-pn532/transport/i2c"
+	"github.com/ZaparooProject/go-pn532/transport/spi"
+	"github.com/ZaparooProject/go-pn532/transport/uart"
+)
+
+// tokenEnv names the environment variable holding the shared secret, which
+// unlike a flag doesn't show up in the process list
+const tokenEnv = "PN532D_TOKEN"
+
+// errBadFlags is returned for flags the flag package already reported
+var errBadFlags = errors.New("invalid flags")
+
+// Daemon timing
+const (
+	connectTimeout    = 5 * time.Second
+	readHeaderTimeout = 10 * time.Second
+	shutdownTimeout   = 5 * time.Second
+)
+
+type config struct {
+	listen     string
+	devicePath string
+	token      string
+	origins    []string
+	debug      bool
+}
+
+func parseConfig(args []string) (*config, error) {
+	cfg := &config{}
+
+	fs := flag.NewFlagSet("pn532d", flag.ContinueOnError)
+	fs.StringVar(&cfg.listen, "listen", "127.0.0.1:8532", "Address the HTTP API listens on")
+	fs.StringVar(&cfg.devicePath, "device", "", "Device path (auto-detect if empty)")
+	fs.StringVar(&cfg.token, "token", os.Getenv(tokenEnv),
+		"Shared secret clients must send, defaults to $"+tokenEnv+" (no authentication if empty)")
+	fs.Func("allow-origin", "Web origin allowed to call the API from a browser, such as "+
+		"https://venue.example (repeatable, * allows any)", func(origin string) error {
+		cfg.origins = append(cfg.origins, origin)
+		return nil
+	})
+	fs.BoolVar(&cfg.debug, "debug", false, "Enable debug output")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil, err
+		}
+		return nil, errBadFlags
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+	return cfg, nil
+}
+
+// openTransport opens a transport of the given type
+func openTransport(transportType, path string) (pn532.Transport, error) {
+	switch strings.ToLower(transportType) {
+	case "uart":
+		transport, err := uart.New(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open UART transport %s: %w", path, err)
+		}
+		return transport, nil
+	case "i2c":
+		transport, err := i2c.New(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open I2C transport %s: %w", path, err)
+		}
+		return transport, nil
+	case "spi":
+		transport, err := spi.New(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open SPI transport %s: %w", path, err)
+		}
+		return transport, nil
+	default:
+		return nil, fmt.Errorf("unsupported transport type: %s", transportType)
+	}
+}
+
+// transportForPath guesses the transport type of a device path, serial ports
+// are the default
+func transportForPath(path string) string {
+	lower := strings.ToLower(path)
+	switch {
+	case strings.Contains(lower, "i2c"):
+		return "i2c"
+	case strings.Contains(lower, "spi"):
+		return "spi"
+	default:
+		return "uart"
+	}
+}
+
+// detectPath looks up the reader at path without talking to it, for the
+// stable ID of its hardware identity
+func detectPath(
+	path string,
+	detect func(*detection.Options) ([]detection.DeviceInfo, error),
+) (detection.DeviceInfo, bool) {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	opts := detection.DefaultOptions()
+	opts.Mode = detection.Passive
+	devices, err := detect(&opts)
+	if err != nil {
+		return detection.DeviceInfo{}, false
+	}
+	return detection.FindByPath(devices, path)
+}
+
+// connect opens the reader and returns it with its path, which is the
+// detected one when auto-detecting. A reader given by path is connected
+// under its stable ID if detection finds it.
+func connect(cfg *config) (device *pn532.Device, path string, err error) {
+	path = cfg.devicePath
+	opts := []pn532.ConnectOption{pn532.WithConnectTimeout(connectTimeout)}
+	fromDetection := pn532.WithTransportFromDeviceFactory(func(info detection.DeviceInfo) (pn532.Transport, error) {
+		path = info.Path
+		return openTransport(info.Transport, info.Path)
+	})
+	if path == "" {
+		opts = append(opts, pn532.WithAutoDetection(), fromDetection)
+	} else if info, ok := detectPath(path, detection.DetectAll); ok {
+		opts = append(opts, pn532.WithStableID(info.StableID), fromDetection,
+			pn532.WithDeviceDetector(func(*detection.Options) ([]detection.DeviceInfo, error) {
+				return []detection.DeviceInfo{info}, nil
+			}))
+	} else {
+		opts = append(opts, pn532.WithTransportFactory(func(path string) (pn532.Transport, error) {
+			return openTransport(transportForPath(path), path)
+		}))
+	}
+
+	device, err = pn532.ConnectDevice(cfg.devicePath, opts...)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to connect to PN532 device: %w", err)
+	}
+	return device, path, nil
+}
+
+// isLoopback reports whether a listen address only accepts local clients
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func run(ctx context.Context, cfg *config) error {
+	if cfg.token == "" && !isLoopback(cfg.listen) {
+		log.Printf("Warning: %s accepts remote clients without authentication, set -token", cfg.listen)
+	}
+
+	device, path, err := connect(cfg)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := device.Close(); closeErr != nil {
+			log.Printf("Failed to close device: %v", closeErr)
+		}
+	}()
+
+	reader := readerInfo{Path: path, ID: device.StableID()}
+	if version, versionErr := device.GetFirmwareVersionContext(ctx); versionErr == nil {
+		reader.Firmware = version.Version
+	}
+	log.Printf("Connected to PN532 %s at %s", reader.Firmware, reader.Path)
+
+	sessionConfig := polling.DefaultConfig()
+	sessionConfig.ReadNDEF = true
+	session := polling.NewSession(device, sessionConfig)
+	srv := newServer(session, reader, cfg.token, cfg.origins)
+	go srv.consume(session.Events())
+	defer func() { _ = session.Close() }()
+
+	listener, err := net.Listen("tcp", cfg.listen)
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+	return serve(ctx, listener, srv)
+}
+
+// serve polls the reader and serves the API until the context is cancelled
+// or either of them fails
+func serve(ctx context.Context, listener net.Listener, srv *server) error {
+	// Cancelling also ends the event streams, which Shutdown would wait for
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	httpServer := &http.Server{
+		Handler:           srv.handler(),
+		ReadHeaderTimeout: readHeaderTimeout,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+
+	errs := make(chan error, 2)
+	go func() {
+		if err := srv.session.Start(ctx); err != nil {
+			errs <- fmt.Errorf("polling stopped: %w", err)
+			return
+		}
+		errs <- nil
+	}()
+	go func() {
+		errs <- httpServer.Serve(listener)
+	}()
+	log.Printf("Serving the API on http://%s", listener.Addr())
+
+	var err error
+	select {
+	case err = <-errs:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	cancel()
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancelShutdown()
+	if shutdownErr := httpServer.Shutdown(shutdownCtx); shutdownErr != nil {
+		log.Printf("Failed to shut down the API: %v", shutdownErr)
+	}
+	return err
+}
+
+func main() {
+	os.Exit(mainWithExitCode())
+}
+
+func mainWithExitCode() int {
+	cfg, err := parseConfig(os.Args[1:])
+	switch {
+	case errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.Is(err, errBadFlags):
+		return 2
+	case err != nil:
+		log.Printf("Error: %v", err)
+		return 2
+	}
+	if cfg.debug {
+		pn532.SetDebugEnabled(true)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	err = run(ctx, cfg)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		log.Printf("Error: %v", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,423 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	pn532 "github.com/ZaparooProject/go-pn532"
+	"github.com/ZaparooProject/go-pn532/polling"
+)
+
+// Request limits and stream timing
+const (
+	defaultWriteTimeout = 30 * time.Second
+	maxWriteTimeout     = 5 * time.Minute
+	maxRequestBody      = 64 << 10
+	keepAliveInterval   = 15 * time.Second
+	// preflightMaxAge is how long browsers may cache a CORS preflight, in
+	// seconds
+	preflightMaxAge = "600"
+)
+
+// anyOrigin allows browser requests from every origin
+const anyOrigin = "*"
+
+var (
+	errUnauthorized = errors.New("invalid or missing token")
+	errOrigin       = errors.New("origin not allowed")
+	errContentType  = errors.New("request body must be application/json")
+	errNoTag        = errors.New("no tag seen yet")
+	errWriteBusy    = errors.New("another write is waiting for a tag")
+	errWriteTimeout = errors.New("timeout waiting for tag")
+	errNoRecords    = errors.New("no records to write")
+)
+
+// readerInfo describes the connected reader
+type readerInfo struct {
+	Path     string `json:"path,omitempty"`
+	ID       string `json:"id,omitempty"`
+	Firmware string `json:"firmware,omitempty"`
+}
+
+// statusView is the response of GET /api/status
+type statusView struct {
+	Reader        readerInfo `json:"reader"`
+	Health        string     `json:"health"`
+	Error         string     `json:"error,omitempty"`
+	UID           string     `json:"uid,omitempty"`
+	TagType       string     `json:"tag_type,omitempty"`
+	Clients       int        `json:"clients"`
+	DroppedEvents uint64     `json:"dropped_events"`
+	TagPresent    bool       `json:"tag_present"`
+	Writing       bool       `json:"writing"`
+}
+
+// writeRequest is the body of POST /api/write
+type writeRequest struct {
+	Records   []recordView `json:"records"`
+	TimeoutMS int          `json:"timeout_ms"`
+}
+
+// writeResponse describes the tag a write went to
+type writeResponse struct {
+	UID     string `json:"uid"`
+	TagType string `json:"tag_type"`
+	Records int    `json:"records"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// server serves the HTTP API of a polling session. The session events are
+// fed in through consume.
+type server struct {
+	session *polling.Session
+	broker  *broker
+	lastTag *tagView
+	lostErr error
+	origins map[string]struct{}
+	reader  readerInfo
+	token   string
+	mu      sync.Mutex
+	writing atomic.Bool
+}
+
+// newServer creates the API server. An empty token disables authentication,
+// origins lists the web origins allowed to call the API from a browser.
+func newServer(session *polling.Session, reader readerInfo, token string, origins []string) *server {
+	s := &server{
+		session: session,
+		broker:  newBroker(),
+		origins: make(map[string]struct{}, len(origins)),
+		reader:  reader,
+		token:   token,
+	}
+	for _, origin := range origins {
+		s.origins[origin] = struct{}{}
+	}
+	return s
+}
+
+// handler returns the HTTP handler of the API
+func (s *server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/status", s.handleStatus)
+	mux.HandleFunc("GET /api/tag", s.handleTag)
+	mux.HandleFunc("POST /api/write", s.handleWrite)
+	mux.HandleFunc("GET /api/events", s.handleEvents)
+	return s.checkOrigin(s.authenticate(mux))
+}
+
+// checkOrigin rejects browser requests from origins that aren't allowed and
+// adds the CORS headers for allowed ones. Requests without an Origin header
+// don't come from a web page and pass. Preflights are answered here, before
+// authentication, as browsers send them without credentials.
+func (s *server) checkOrigin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !s.originAllowed(origin) {
+			writeError(w, http.StatusForbidden, errOrigin)
+			return
+		}
+
+		header := w.Header()
+		header.Set("Access-Control-Allow-Origin", origin)
+		header.Add("Vary", "Origin")
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			header.Set("Access-Control-Allow-Methods", "GET, POST")
+			header.Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+			header.Set("Access-Control-Max-Age", preflightMaxAge)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *server) originAllowed(origin string) bool {
+	if _, ok := s.origins[anyOrigin]; ok {
+		return true
+	}
+	_, ok := s.origins[origin]
+	return ok
+}
+
+// authenticate requires the shared secret as a bearer token, or as the token
+// query parameter for EventSource clients which can't set headers
+func (s *server) authenticate(next http.Handler) http.Handler {
+	if s.token == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("token")
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			token, _ = strings.CutPrefix(auth, "Bearer ")
+		}
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="pn532d"`)
+			writeError(w, http.StatusUnauthorized, errUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// consume records and publishes session events until the channel is closed
+func (s *server) consume(events <-chan polling.Event) {
+	for event := range events {
+		view := newEventView(&event)
+		s.record(&event, view)
+		s.broker.publish(view)
+	}
+}
+
+// record updates the last tag and reader state from an event
+func (s *server) record(event *polling.Event, view *eventView) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch event.Type {
+	case polling.EventDetected, polling.EventChanged:
+		s.lastTag = &tagView{
+			DetectedAt: event.Time,
+			UID:        view.UID,
+			Type:       view.TagType,
+			Records:    []recordView{},
+			Present:    true,
+		}
+	case polling.EventRead:
+		if s.lastTag == nil || s.lastTag.UID != view.UID {
+			return
+		}
+		if view.Records != nil {
+			s.lastTag.Records = view.Records
+		}
+		s.lastTag.ReadError = view.Error
+	case polling.EventRemoved:
+		if s.lastTag != nil && s.lastTag.Present {
+			removedAt := event.Time
+			s.lastTag.RemovedAt = &removedAt
+			s.lastTag.Present = false
+		}
+	case polling.EventReaderLost:
+		s.lostErr = event.Err
+	case polling.EventError, polling.EventRestart, polling.EventHealth, polling.EventRecovery:
+	}
+}
+
+func (s *server) handleStatus(w http.ResponseWriter, _ *http.Request) {
+	state := s.session.GetState()
+	status := &statusView{
+		Reader:     s.reader,
+		Health:     s.session.HealthState().String(),
+		TagPresent: state.Present,
+		Writing:    s.writing.Load(),
+	}
+	if state.Present {
+		status.UID, status.TagType = state.LastUID, state.LastType
+	}
+	status.Clients, status.DroppedEvents = s.broker.stats()
+
+	s.mu.Lock()
+	if s.lostErr != nil {
+		status.Error = s.lostErr.Error()
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, status)
+}
+
+func (s *server) handleTag(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	var tag *tagView
+	if s.lastTag != nil {
+		copied := *s.lastTag
+		tag = &copied
+	}
+	s.mu.Unlock()
+
+	if tag == nil {
+		writeError(w, http.StatusNotFound, errNoTag)
+		return
+	}
+	writeJSON(w, http.StatusOK, tag)
+}
+
+// handleWrite writes NDEF records to the next tag on the reader, the request
+// completes once the tag is written
+func (s *server) handleWrite(w http.ResponseWriter, r *http.Request) {
+	msg, timeout, err := decodeWriteRequest(w, r)
+	if errors.Is(err, errContentType) {
+		writeError(w, http.StatusUnsupportedMediaType, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if !s.writing.CompareAndSwap(false, true) {
+		writeError(w, http.StatusConflict, errWriteBusy)
+		return
+	}
+	defer s.writing.Store(false)
+
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	response := &writeResponse{Records: len(msg.Records)}
+	err = s.session.WriteToNextTag(ctx, ctx, timeout, func(_ context.Context, tag pn532.Tag) error {
+		response.UID, response.TagType = tag.UID(), string(tag.Type())
+		if writeErr := tag.WriteNDEF(msg); writeErr != nil {
+			return fmt.Errorf("failed to write NDEF message: %w", writeErr)
+		}
+		return nil
+	})
+	switch {
+	case err == nil:
+		writeJSON(w, http.StatusOK, response)
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		writeError(w, http.StatusRequestTimeout, errWriteTimeout)
+	case r.Context().Err() != nil:
+		// The client went away, nobody is left to answer
+	default:
+		writeError(w, http.StatusInternalServerError, err)
+	}
+}
+
+// decodeWriteRequest parses a write request into the message to write and
+// how long to wait for a tag. Only JSON bodies are accepted: a page can send
+// a form or text/plain body cross-site without a CORS preflight, so the
+// content type keeps other sites from writing tags.
+func decodeWriteRequest(w http.ResponseWriter, r *http.Request) (*pn532.NDEFMessage, time.Duration, error) {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "application/json" {
+		return nil, 0, errContentType
+	}
+
+	var req writeRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		return nil, 0, fmt.Errorf("invalid request body: %w", err)
+	}
+	if len(req.Records) == 0 {
+		return nil, 0, errNoRecords
+	}
+
+	msg := &pn532.NDEFMessage{Records: make([]pn532.NDEFRecord, 0, len(req.Records))}
+	for i, rec := range req.Records {
+		switch {
+		case rec.Type == string(pn532.NDEFTypeText) && rec.Text != "":
+			msg.Records = append(msg.Records, pn532.NDEFRecord{Type: pn532.NDEFTypeText, Text: rec.Text})
+		case rec.Type == string(pn532.NDEFTypeURI) && rec.URI != "":
+			msg.Records = append(msg.Records, pn532.NDEFRecord{Type: pn532.NDEFTypeURI, URI: rec.URI})
+		default:
+			return nil, 0, fmt.Errorf("record %d: want a text record with text or a uri record with uri", i)
+		}
+	}
+
+	timeout := time.Duration(req.TimeoutMS) * time.Millisecond
+	switch {
+	case req.TimeoutMS < 0:
+		return nil, 0, fmt.Errorf("invalid timeout_ms %d", req.TimeoutMS)
+	case timeout == 0:
+		timeout = defaultWriteTimeout
+	case timeout > maxWriteTimeout:
+		timeout = maxWriteTimeout
+	}
+	return msg, timeout, nil
+}
+
+// handleEvents streams the session events as server-sent events named after
+// the event type
+func (s *server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, errors.New("streaming is not supported"))
+		return
+	}
+	events, unsubscribe := s.broker.subscribe()
+	defer unsubscribe()
+
+	header := w.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	// Stops reverse proxies from buffering the stream
+	header.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+	for {
+		var err error
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			_, err = fmt.Fprint(w, ": keep-alive\n\n")
+		case event := <-events:
+			err = writeSSE(w, event)
+		}
+		if err != nil {
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+// writeSSE writes one server-sent event
+func writeSSE(w http.ResponseWriter, event *eventView) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	if _, writeErr := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Event, data); writeErr != nil {
+		return fmt.Errorf("failed to write event: %w", writeErr)
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, &errorResponse{Error: err.Error()})
+}
@@ -0,0 +1,488 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	pn532 "github.com/ZaparooProject/go-pn532"
+	"github.com/ZaparooProject/go-pn532/detection"
+	"github.com/ZaparooProject/go-pn532/pn532sim"
+	"github.com/ZaparooProject/go-pn532/polling"
+	"github.com/ZaparooProject/go-pn532/tagops"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testUID7 = []byte{0x04, 0xA1, 0xA2, 0xA3, 0xA4, 0xA5, 0xA6}
+
+const testUIDHex = "04a1a2a3a4a5a6"
+
+// testDaemon is pn532d serving a simulated reader
+type testDaemon struct {
+	sim   *pn532sim.Simulator
+	url   string
+	token string
+}
+
+func startTestDaemon(t *testing.T, token string, origins ...string) *testDaemon {
+	t.Helper()
+
+	sim, err := pn532sim.New()
+	require.NoError(t, err)
+	device, err := pn532.New(sim)
+	require.NoError(t, err)
+	require.NoError(t, device.Init())
+
+	sessionConfig := polling.DefaultConfig()
+	sessionConfig.PollInterval = 20 * time.Millisecond
+	sessionConfig.ReadNDEF = true
+	session := polling.NewSession(device, sessionConfig)
+	srv := newServer(session, readerInfo{Path: "sim"}, token, origins)
+	go srv.consume(session.Events())
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- serve(ctx, listener, srv)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		assert.ErrorIs(t, <-done, context.Canceled)
+		_ = session.Close()
+		_ = device.Close()
+	})
+
+	return &testDaemon{sim: sim, url: "http://" + listener.Addr().String(), token: token}
+}
+
+// formattedNTAG returns an NDEF formatted NTAG213, holding text if not empty
+func formattedNTAG(t *testing.T, text string) *pn532sim.NTAG {
+	t.Helper()
+
+	tag := pn532sim.NewNTAG213(testUID7)
+	sim, err := pn532sim.New(pn532sim.WithTags(tag))
+	require.NoError(t, err)
+	device, err := pn532.New(sim)
+	require.NoError(t, err)
+	require.NoError(t, device.Init())
+	defer func() { _ = device.Close() }()
+
+	ops := tagops.New(device)
+	require.NoError(t, ops.DetectTag(context.Background()))
+	_, err = ops.Format(context.Background())
+	require.NoError(t, err)
+	if text != "" {
+		ntag, ok := ops.Tag().(*pn532.NTAGTag)
+		require.True(t, ok)
+		require.NoError(t, ntag.WriteText(text))
+	}
+	return tag
+}
+
+// request sends an API request and decodes the JSON response into v
+func (d *testDaemon) request(t *testing.T, method, path, body string, v any) int {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(context.Background(), method, d.url+path, strings.NewReader(body))
+	require.NoError(t, err)
+	if d.token != "" {
+		req.Header.Set("Authorization", "Bearer "+d.token)
+	}
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer func() { _ = res.Body.Close() }()
+	if v != nil {
+		require.NoError(t, json.NewDecoder(res.Body).Decode(v))
+	}
+	return res.StatusCode
+}
+
+// eventStream reads the server-sent events of /api/events
+type eventStream struct {
+	lines *bufio.Scanner
+}
+
+func (d *testDaemon) events(t *testing.T) *eventStream {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	t.Cleanup(cancel)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.url+"/api/events?token="+d.token, http.NoBody)
+	require.NoError(t, err)
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = res.Body.Close() })
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+	return &eventStream{lines: bufio.NewScanner(res.Body)}
+}
+
+// waitFor skips events until one of the given type arrives
+func (s *eventStream) waitFor(t *testing.T, name string) *eventView {
+	t.Helper()
+
+	var eventName string
+	for s.lines.Scan() {
+		line := s.lines.Text()
+		if value, ok := strings.CutPrefix(line, "event: "); ok {
+			eventName = value
+			continue
+		}
+		data, ok := strings.CutPrefix(line, "data: ")
+		if !ok || eventName != name {
+			continue
+		}
+		var event eventView
+		require.NoError(t, json.Unmarshal([]byte(data), &event))
+		assert.Equal(t, name, event.Event)
+		return &event
+	}
+	require.FailNow(t, "event stream ended", "waiting for %s: %v", name, s.lines.Err())
+	return nil
+}
+
+func TestDaemon_EventsAndTag(t *testing.T) {
+	t.Parallel()
+
+	daemon := startTestDaemon(t, "")
+	events := daemon.events(t)
+
+	var apiErr errorResponse
+	assert.Equal(t, http.StatusNotFound, daemon.request(t, http.MethodGet, "/api/tag", "", &apiErr))
+
+	tag := formattedNTAG(t, "hello")
+	daemon.sim.PlaceTag(tag)
+	detected := events.waitFor(t, "detected")
+	assert.Equal(t, testUIDHex, detected.UID)
+	assert.Equal(t, string(pn532.TagTypeNTAG), detected.TagType)
+	read := events.waitFor(t, "read")
+	require.Len(t, read.Records, 1)
+	assert.Equal(t, "hello", read.Records[0].Text)
+
+	var status statusView
+	require.Equal(t, http.StatusOK, daemon.request(t, http.MethodGet, "/api/status", "", &status))
+	assert.Equal(t, "sim", status.Reader.Path)
+	assert.Equal(t, "ok", status.Health)
+	assert.True(t, status.TagPresent)
+	assert.Equal(t, testUIDHex, status.UID)
+	assert.Equal(t, 1, status.Clients)
+
+	var last tagView
+	require.Equal(t, http.StatusOK, daemon.request(t, http.MethodGet, "/api/tag", "", &last))
+	assert.True(t, last.Present)
+	assert.Equal(t, testUIDHex, last.UID)
+	require.Len(t, last.Records, 1)
+	assert.Equal(t, "hello", last.Records[0].Text)
+
+	daemon.sim.RemoveTag(tag)
+	removed := events.waitFor(t, "removed")
+	assert.Equal(t, testUIDHex, removed.UID)
+
+	require.Equal(t, http.StatusOK, daemon.request(t, http.MethodGet, "/api/tag", "", &last))
+	assert.False(t, last.Present)
+	assert.NotNil(t, last.RemovedAt)
+}
+
+func TestDaemon_Write(t *testing.T) {
+	t.Parallel()
+
+	daemon := startTestDaemon(t, "")
+	events := daemon.events(t)
+	tag := formattedNTAG(t, "")
+	daemon.sim.PlaceTag(tag)
+	events.waitFor(t, "read")
+
+	body := `{"records":[{"type":"text","text":"written"},{"type":"uri","uri":"https://zaparoo.org"}]}`
+	var written writeResponse
+	require.Equal(t, http.StatusOK, daemon.request(t, http.MethodPost, "/api/write", body, &written))
+	assert.Equal(t, writeResponse{UID: testUIDHex, TagType: string(pn532.TagTypeNTAG), Records: 2}, written)
+
+	// Presenting the tag again reads what was written
+	daemon.sim.RemoveTag(tag)
+	events.waitFor(t, "removed")
+	daemon.sim.PlaceTag(tag)
+	read := events.waitFor(t, "read")
+	require.Len(t, read.Records, 2)
+	assert.Equal(t, "written", read.Records[0].Text)
+	assert.Equal(t, "https://zaparoo.org", read.Records[1].URI)
+
+	daemon.sim.RemoveTag(tag)
+	var apiErr errorResponse
+	status := daemon.request(t, http.MethodPost, "/api/write", `{"records":[{"type":"text","text":"x"}],"timeout_ms":100}`,
+		&apiErr)
+	assert.Equal(t, http.StatusRequestTimeout, status)
+	assert.Equal(t, errWriteTimeout.Error(), apiErr.Error)
+}
+
+func TestDaemon_WriteInvalid(t *testing.T) {
+	t.Parallel()
+
+	daemon := startTestDaemon(t, "")
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "not JSON", body: "text"},
+		{name: "no records", body: `{"records":[]}`},
+		{name: "unknown field", body: `{"records":[{"type":"text","text":"x"}],"wait":1}`},
+		{name: "unsupported record", body: `{"records":[{"type":"smartposter"}]}`},
+		{name: "text record without text", body: `{"records":[{"type":"text","uri":"x"}]}`},
+		{name: "negative timeout", body: `{"records":[{"type":"text","text":"x"}],"timeout_ms":-1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var apiErr errorResponse
+			assert.Equal(t, http.StatusBadRequest, daemon.request(t, http.MethodPost, "/api/write", tt.body, &apiErr))
+			assert.NotEmpty(t, apiErr.Error)
+		})
+	}
+}
+
+func TestDaemon_WriteContentType(t *testing.T) {
+	t.Parallel()
+
+	daemon := startTestDaemon(t, "")
+	tests := []struct {
+		name        string
+		contentType string
+		want        int
+	}{
+		// What a cross-site page can send without a CORS preflight
+		{name: "text/plain", contentType: "text/plain", want: http.StatusUnsupportedMediaType},
+		{name: "form", contentType: "application/x-www-form-urlencoded", want: http.StatusUnsupportedMediaType},
+		{name: "missing", want: http.StatusUnsupportedMediaType},
+		// Passes the content type check and fails on the empty record list
+		{name: "JSON with charset", contentType: "application/json; charset=utf-8", want: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, daemon.url+"/api/write",
+				strings.NewReader(`{"records":[]}`))
+			require.NoError(t, err)
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			res, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			_ = res.Body.Close()
+			assert.Equal(t, tt.want, res.StatusCode)
+		})
+	}
+}
+
+func TestDaemon_CORS(t *testing.T) {
+	t.Parallel()
+
+	const venue = "https://venue.example"
+	daemon := startTestDaemon(t, "s3cret", venue)
+	tests := []struct {
+		name       string
+		method     string
+		origin     string
+		auth       string
+		wantOrigin string
+		want       int
+	}{
+		{name: "no origin", method: http.MethodGet, auth: "Bearer s3cret", want: http.StatusOK},
+		{
+			name: "allowed origin", method: http.MethodGet, origin: venue, auth: "Bearer s3cret",
+			want: http.StatusOK, wantOrigin: venue,
+		},
+		{
+			name: "other origin", method: http.MethodGet, origin: "https://evil.example", auth: "Bearer s3cret",
+			want: http.StatusForbidden,
+		},
+		{
+			name: "preflight", method: http.MethodOptions, origin: venue,
+			want: http.StatusNoContent, wantOrigin: venue,
+		},
+		{
+			name: "preflight from other origin", method: http.MethodOptions, origin: "https://evil.example",
+			want: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequestWithContext(context.Background(), tt.method, daemon.url+"/api/status", http.NoBody)
+			require.NoError(t, err)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			if tt.method == http.MethodOptions {
+				req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+				req.Header.Set("Access-Control-Request-Headers", "authorization, content-type")
+			}
+			res, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			_ = res.Body.Close()
+
+			assert.Equal(t, tt.want, res.StatusCode)
+			assert.Equal(t, tt.wantOrigin, res.Header.Get("Access-Control-Allow-Origin"))
+			if tt.method == http.MethodOptions && tt.want == http.StatusNoContent {
+				assert.Equal(t, "GET, POST", res.Header.Get("Access-Control-Allow-Methods"))
+				assert.Equal(t, "Authorization, Content-Type", res.Header.Get("Access-Control-Allow-Headers"))
+			}
+		})
+	}
+}
+
+func TestServer_AnyOrigin(t *testing.T) {
+	t.Parallel()
+
+	srv := newServer(nil, readerInfo{}, "", []string{anyOrigin})
+	assert.True(t, srv.originAllowed("https://anything.example"))
+	assert.False(t, newServer(nil, readerInfo{}, "", nil).originAllowed("https://anything.example"))
+}
+
+func TestDaemon_Auth(t *testing.T) {
+	t.Parallel()
+
+	daemon := startTestDaemon(t, "s3cret")
+	tests := []struct {
+		name   string
+		path   string
+		header string
+		want   int
+	}{
+		{name: "no token", path: "/api/status", want: http.StatusUnauthorized},
+		{name: "wrong token", path: "/api/status", header: "Bearer nope", want: http.StatusUnauthorized},
+		{name: "not a bearer token", path: "/api/status", header: "Basic s3cret", want: http.StatusUnauthorized},
+		{name: "bearer token", path: "/api/status", header: "Bearer s3cret", want: http.StatusOK},
+		{name: "query token", path: "/api/status?token=s3cret", want: http.StatusOK},
+		{name: "wrong query token", path: "/api/status?token=nope", want: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, daemon.url+tt.path, http.NoBody)
+			require.NoError(t, err)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			res, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			_ = res.Body.Close()
+			assert.Equal(t, tt.want, res.StatusCode)
+		})
+	}
+
+	// The stream accepts the query token
+	events := daemon.events(t)
+	tag := formattedNTAG(t, "")
+	daemon.sim.PlaceTag(tag)
+	events.waitFor(t, "detected")
+}
+
+func TestParseConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := parseConfig([]string{
+		"-listen", ":9000", "-device", "/dev/ttyUSB0", "-token", "x",
+		"-allow-origin", "https://a.example", "-allow-origin", "https://b.example",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &config{
+		listen: ":9000", devicePath: "/dev/ttyUSB0", token: "x",
+		origins: []string{"https://a.example", "https://b.example"},
+	}, cfg)
+
+	_, err = parseConfig([]string{"extra"})
+	require.Error(t, err)
+	assert.False(t, errors.Is(err, errBadFlags))
+}
+
+func TestDetectPath(t *testing.T) {
+	t.Parallel()
+
+	var mode detection.Mode
+	detect := func(opts *detection.Options) ([]detection.DeviceInfo, error) {
+		mode = opts.Mode
+		return []detection.DeviceInfo{
+			{Transport: "uart", Path: "/dev/ttyUSB0", StableID: "uart:usb-serial:LEFT"},
+			{Transport: "uart", Path: "/dev/ttyUSB1", StableID: "uart:usb-serial:RIGHT"},
+		}, nil
+	}
+
+	info, ok := detectPath("/dev/ttyUSB1", detect)
+	require.True(t, ok)
+	assert.Equal(t, "uart:usb-serial:RIGHT", info.StableID)
+	assert.Equal(t, detection.Passive, mode, "the reader is not probed")
+
+	_, ok = detectPath("/dev/ttyACM0", detect)
+	assert.False(t, ok)
+	_, ok = detectPath("/dev/ttyUSB0", func(*detection.Options) ([]detection.DeviceInfo, error) {
+		return nil, detection.ErrNoDevicesFound
+	})
+	assert.False(t, ok)
+}
+
+func TestIsLoopback(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{addr: "127.0.0.1:8532", want: true},
+		{addr: "localhost:8532", want: true},
+		{addr: "[::1]:8532", want: true},
+		{addr: ":8532", want: false},
+		{addr: "0.0.0.0:8532", want: false},
+		{addr: "192.168.1.10:8532", want: false},
+		{addr: "invalid", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, isLoopback(tt.addr))
+		})
+	}
+}
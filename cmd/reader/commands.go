@@ -0,0 +1,424 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	pn532 "github.com/ZaparooProject/go-pn532"
+	"github.com/ZaparooProject/go-pn532/detection"
+	"github.com/ZaparooProject/go-pn532/tagops"
+)
+
+var (
+	// errUsage marks invalid command lines, they exit with status 2
+	errUsage = errors.New("invalid usage")
+	// errBadFlags is returned for flags the flag package already reported
+	errBadFlags = fmt.Errorf("%w: invalid flags", errUsage)
+	// errDiagnoseFailed is returned when a self test fails
+	errDiagnoseFailed = errors.New("self test failed")
+)
+
+// Tag wait defaults
+const (
+	defaultTagTimeout = 30 * time.Second
+	tagPollInterval   = 250 * time.Millisecond
+)
+
+// runFunc runs a command after its flags were parsed
+type runFunc func(ctx context.Context, env *environment) error
+
+// command is a reader subcommand
+type command struct {
+	// setup registers the command flags and returns the function running it
+	setup   func(fs *flag.FlagSet) runFunc
+	name    string
+	summary string
+}
+
+// commands lists the subcommands in the order of the usage message
+var commands = []*command{
+	{name: "detect", summary: "List connected PN532 readers", setup: setupDetect},
+	{name: "info", summary: "Show firmware, reader status and the tag on the reader", setup: setupInfo},
+	{name: "read", summary: "Read and decode the NDEF message of a tag", setup: setupRead},
+	{name: "watch", summary: "Print tag events and run hook commands until interrupted", setup: setupWatch},
+	{name: "write", summary: "Write text, URI, WiFi, vCard or raw NDEF records", setup: setupWrite},
+	{name: "dump", summary: "Dump the tag memory", setup: setupDump},
+	{name: "restore", summary: "Write a dump back to the data area of a tag", setup: setupRestore},
+	{name: "format", summary: "Format a tag for NDEF and leave an empty message", setup: setupFormat},
+	{name: "lock", summary: "Make a tag permanently read-only", setup: setupLock},
+	{name: "password", summary: "Manage NTAG password protection", setup: setupPassword},
+	{name: "diagnose", summary: "Run the PN532 self tests", setup: setupDiagnose},
+}
+
+// environment is what commands run with. The reader is connected on first use, so
+// commands that don't need one, or fail on their flags, never open it.
+type environment struct {
+	device  *pn532.Device
+	connect func(ctx context.Context) (*pn532.Device, error)
+	out     *printer
+	// mifareConfig overrides the MIFARE Classic retry timing when set
+	mifareConfig *pn532.MIFAREConfig
+	// readerPath is the path of the connected reader, if known
+	readerPath string
+}
+
+// reader returns the connected reader
+func (env *environment) reader(ctx context.Context) (*pn532.Device, error) {
+	if env.device == nil {
+		device, err := env.connect(ctx)
+		if err != nil {
+			return nil, err
+		}
+		env.device = device
+	}
+	return env.device, nil
+}
+
+// waitForTag polls until a tag is detected or the timeout expires. A zero
+// timeout makes a single attempt.
+func (env *environment) waitForTag(ctx context.Context, timeout time.Duration) (*tagops.TagOperations, error) {
+	device, err := env.reader(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ops := tagops.New(device)
+	if env.mifareConfig != nil {
+		ops.SetMIFAREConfig(env.mifareConfig)
+	}
+	deadline := time.Now().Add(timeout)
+	for prompted := false; ; prompted = true {
+		err := ops.DetectTag(ctx)
+		if err == nil {
+			return ops, nil
+		}
+		if !errors.Is(err, pn532.ErrNoTagDetected) || !time.Now().Before(deadline) {
+			return nil, fmt.Errorf("failed to wait for tag: %w", err)
+		}
+		if !prompted {
+			env.out.statusf("Place a tag on the reader...")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(tagPollInterval):
+		}
+	}
+}
+
+// detectTag waits for a tag and describes it
+func (env *environment) detectTag(ctx context.Context, timeout time.Duration) (
+	*tagops.TagOperations, *tagView, error,
+) {
+	ops, err := env.waitForTag(ctx, timeout)
+	if err != nil {
+		return nil, nil, err
+	}
+	view, err := newTagView(ops)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ops, view, nil
+}
+
+// findCommand looks up a subcommand by name
+func findCommand(name string) (*command, error) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: unknown command %q", errUsage, name)
+}
+
+// parseCommand parses the flags of a subcommand. The global flags are
+// accepted after the command name too.
+func parseCommand(cfg *config, name string, args []string, output io.Writer) (runFunc, error) {
+	cmd, err := findCommand(name)
+	if err != nil {
+		return nil, err
+	}
+
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	fs.SetOutput(output)
+	cfg.addGlobalFlags(fs)
+	run := cmd.setup(fs)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(output, "Usage: reader %s [flags]\n\n%s.\n\nFlags:\n", cmd.name, cmd.summary)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil, err
+		}
+		return nil, errBadFlags
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("%w: unexpected argument %q", errUsage, fs.Arg(0))
+	}
+	return run, nil
+}
+
+// printUsage writes the top level usage message
+func printUsage(output io.Writer, fs *flag.FlagSet) {
+	_, _ = fmt.Fprint(output, "Usage: reader [flags] [command] [command flags]\n\n"+
+		"Without a command the reader prints every tag it sees, as one JSON\n"+
+		"event per line with -json, or with -write writes text to the next tag.\n\nCommands:\n")
+	for _, cmd := range commands {
+		_, _ = fmt.Fprintf(output, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	_, _ = fmt.Fprint(output, "\nFlags:\n")
+	fs.PrintDefaults()
+}
+
+// detectResult lists the readers found by detect
+type detectResult struct {
+	Readers []readerView `json:"readers"`
+}
+
+type readerView struct {
+	Metadata   map[string]string `json:"metadata,omitempty"`
+	Transport  string            `json:"transport"`
+	Path       string            `json:"path"`
+	Name       string            `json:"name,omitempty"`
+	StableID   string            `json:"stable_id,omitempty"`
+	Confidence string            `json:"confidence"`
+}
+
+func (r *detectResult) writeText(out io.Writer) {
+	if len(r.Readers) == 0 {
+		_, _ = fmt.Fprintln(out, "No PN532 readers found")
+		return
+	}
+	for _, reader := range r.Readers {
+		_, _ = fmt.Fprintf(out, "%-5s %s (%s confidence)", reader.Transport, reader.Path, reader.Confidence)
+		if reader.Name != "" {
+			_, _ = fmt.Fprintf(out, " %s", reader.Name)
+		}
+		_, _ = fmt.Fprintln(out)
+	}
+}
+
+func setupDetect(fs *flag.FlagSet) runFunc {
+	mode := fs.String("mode", "safe", "Detection mode: passive, safe or full")
+	timeout := fs.Duration("timeout", 5*time.Second, "Detection timeout")
+
+	return func(ctx context.Context, env *environment) error {
+		opts := detection.DefaultOptions()
+		opts.Timeout = *timeout
+		opts.EnableCache = false
+		switch *mode {
+		case "passive":
+			opts.Mode = detection.Passive
+		case "safe":
+			opts.Mode = detection.Safe
+		case "full":
+			opts.Mode = detection.Full
+		default:
+			return fmt.Errorf("%w: unknown detection mode %q", errUsage, *mode)
+		}
+
+		devices, err := detection.DetectAllContext(ctx, &opts)
+		if err != nil && !errors.Is(err, detection.ErrNoDevicesFound) {
+			return fmt.Errorf("failed to detect readers: %w", err)
+		}
+
+		res := &detectResult{Readers: make([]readerView, 0, len(devices))}
+		for _, device := range devices {
+			res.Readers = append(res.Readers, readerView{
+				Metadata:   device.Metadata,
+				Transport:  device.Transport,
+				Path:       device.Path,
+				Name:       device.Name,
+				StableID:   device.StableID,
+				Confidence: confidenceName(device.Confidence),
+			})
+		}
+		return env.out.print(res)
+	}
+}
+
+func confidenceName(confidence detection.Confidence) string {
+	switch confidence {
+	case detection.Low:
+		return "low"
+	case detection.Medium:
+		return "medium"
+	case detection.High:
+		return "high"
+	default:
+		return "unknown"
+	}
+}
+
+// infoResult describes the reader and the tag on it, if any
+type infoResult struct {
+	Firmware *firmwareView `json:"firmware"`
+	Status   *statusView   `json:"status"`
+	Tag      *tagView      `json:"tag"`
+}
+
+type firmwareView struct {
+	Version   string `json:"version"`
+	ISO14443A bool   `json:"iso14443a"`
+	ISO14443B bool   `json:"iso14443b"`
+	ISO18092  bool   `json:"iso18092"`
+}
+
+type statusView struct {
+	LastError    byte `json:"last_error"`
+	FieldPresent bool `json:"field_present"`
+	Targets      byte `json:"targets"`
+}
+
+func (r *infoResult) writeText(out io.Writer) {
+	_, _ = fmt.Fprintf(out, "Firmware:     %s\n", r.Firmware.Version)
+	_, _ = fmt.Fprintf(out, "Protocols:    ISO14443A %s, ISO14443B %s, ISO18092 %s\n",
+		yesNo(r.Firmware.ISO14443A), yesNo(r.Firmware.ISO14443B), yesNo(r.Firmware.ISO18092))
+	_, _ = fmt.Fprintf(out, "RF field:     %s\n", yesNo(r.Status.FieldPresent))
+	_, _ = fmt.Fprintf(out, "Targets:      %d\n", r.Status.Targets)
+	_, _ = fmt.Fprintf(out, "Last error:   0x%02X\n", r.Status.LastError)
+	if r.Tag == nil {
+		_, _ = fmt.Fprintln(out, "Tag:          none")
+		return
+	}
+	r.Tag.writeText(out)
+}
+
+func setupInfo(fs *flag.FlagSet) runFunc {
+	wait := fs.Duration("wait", 0, "How long to wait for a tag, 0 checks once")
+
+	return func(ctx context.Context, env *environment) error {
+		device, err := env.reader(ctx)
+		if err != nil {
+			return err
+		}
+
+		version, err := device.GetFirmwareVersionContext(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get firmware version: %w", err)
+		}
+		status, err := device.GetGeneralStatusContext(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get general status: %w", err)
+		}
+		res := &infoResult{
+			Firmware: &firmwareView{
+				Version:   version.Version,
+				ISO14443A: version.SupportIso14443a,
+				ISO14443B: version.SupportIso14443b,
+				ISO18092:  version.SupportIso18092,
+			},
+			Status: &statusView{
+				LastError:    status.LastError,
+				FieldPresent: status.FieldPresent,
+				Targets:      status.Targets,
+			},
+		}
+
+		ops, err := env.waitForTag(ctx, *wait)
+		switch {
+		case errors.Is(err, pn532.ErrNoTagDetected):
+		case err != nil:
+			return err
+		default:
+			if res.Tag, err = newTagView(ops); err != nil {
+				return err
+			}
+		}
+		return env.out.print(res)
+	}
+}
+
+// diagnoseResult lists the self test outcomes
+type diagnoseResult struct {
+	Tests  []testView `json:"tests"`
+	Passed bool       `json:"passed"`
+}
+
+type testView struct {
+	Name   string `json:"name"`
+	Error  string `json:"error,omitempty"`
+	Passed bool   `json:"passed"`
+}
+
+func (r *diagnoseResult) writeText(out io.Writer) {
+	for _, test := range r.Tests {
+		outcome := "ok"
+		if !test.Passed {
+			outcome = "FAILED"
+		}
+		if test.Error != "" {
+			outcome += ": " + test.Error
+		}
+		_, _ = fmt.Fprintf(out, "%-14s %s\n", test.Name, outcome)
+	}
+}
+
+// diagnoseTests are the self tests diagnose runs. The polling and antenna
+// tests need a target or thresholds and are left out.
+var diagnoseTests = []struct {
+	name   string
+	data   []byte
+	number byte
+}{
+	{name: "communication", number: pn532.DiagnoseCommunicationTest, data: []byte("go-pn532")},
+	{name: "ROM", number: pn532.DiagnoseROMTest},
+	{name: "RAM", number: pn532.DiagnoseRAMTest},
+}
+
+func setupDiagnose(*flag.FlagSet) runFunc {
+	return func(ctx context.Context, env *environment) error {
+		device, err := env.reader(ctx)
+		if err != nil {
+			return err
+		}
+
+		res := &diagnoseResult{Passed: true}
+		for _, test := range diagnoseTests {
+			view := testView{Name: test.name}
+			result, err := device.DiagnoseContext(ctx, test.number, test.data)
+			if err != nil {
+				view.Error = err.Error()
+			} else {
+				view.Passed = result.Success
+			}
+			res.Passed = res.Passed && view.Passed
+			res.Tests = append(res.Tests, view)
+		}
+
+		if err := env.out.print(res); err != nil {
+			return err
+		}
+		if !res.Passed {
+			return errDiagnoseFailed
+		}
+		return nil
+	}
+}
@@ -0,0 +1,307 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pn532 "github.com/ZaparooProject/go-pn532"
+	"github.com/ZaparooProject/go-pn532/pn532sim"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testUID7 = []byte{0x04, 0xA1, 0xA2, 0xA3, 0xA4, 0xA5, 0xA6}
+	testUID4 = []byte{0xC1, 0xC2, 0xC3, 0xC4}
+	testIDm  = []byte{0x01, 0x2E, 0x4C, 0x7A, 0x12, 0x34, 0x56, 0x78}
+)
+
+// newTestEnv returns a JSON mode environment on a simulated reader
+func newTestEnv(t *testing.T, tags ...pn532sim.Tag) (*environment, *bytes.Buffer) {
+	t.Helper()
+
+	sim, err := pn532sim.New(pn532sim.WithTags(tags...))
+	require.NoError(t, err)
+	return newSimEnv(t, sim)
+}
+
+// newSimEnv returns a JSON mode environment on the given simulator
+func newSimEnv(t *testing.T, sim *pn532sim.Simulator) (*environment, *bytes.Buffer) {
+	t.Helper()
+
+	device, err := pn532.New(sim)
+	require.NoError(t, err)
+	require.NoError(t, device.Init())
+	t.Cleanup(func() { _ = device.Close() })
+
+	out := &bytes.Buffer{}
+	env := &environment{
+		device: device,
+		out:    &printer{out: out, status: io.Discard, json: true},
+		// Quick retries, the third re-selects a tag halted by a wrong key
+		mifareConfig: &pn532.MIFAREConfig{
+			RetryConfig: &pn532.RetryConfig{
+				MaxAttempts:       3,
+				InitialBackoff:    time.Microsecond,
+				MaxBackoff:        10 * time.Microsecond,
+				BackoffMultiplier: 2.0,
+				RetryTimeout:      time.Second,
+			},
+		},
+	}
+	return env, out
+}
+
+// runJSON runs a command and decodes its JSON result into v
+func runJSON(t *testing.T, env *environment, out *bytes.Buffer, v any, name string, args ...string) {
+	t.Helper()
+
+	out.Reset()
+	require.NoError(t, runTestCommand(env, name, args...))
+	require.NoError(t, json.Unmarshal(out.Bytes(), v), out.String())
+}
+
+func runTestCommand(env *environment, name string, args ...string) error {
+	run, err := parseCommand(&config{}, name, args, io.Discard)
+	if err != nil {
+		return err
+	}
+	return run(context.Background(), env)
+}
+
+func TestParseCommand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		wantErr error
+		name    string
+		command string
+		args    []string
+	}{
+		{name: "read", command: "read", args: []string{"-timeout", "1s"}},
+		{name: "global flags after the command", command: "info", args: []string{"-json", "-debug"}},
+		{name: "unknown command", command: "erase", wantErr: errUsage},
+		{name: "unknown flag", command: "read", args: []string{"-bogus"}, wantErr: errBadFlags},
+		{name: "extra argument", command: "dump", args: []string{"tag.bin"}, wantErr: errUsage},
+		{name: "help", command: "lock", args: []string{"-h"}, wantErr: flag.ErrHelp},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := parseCommand(&config{}, tt.command, tt.args, io.Discard)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := parseConfig([]string{"-json", "-device", "/dev/ttyUSB0", "read", "-timeout", "5s"}, io.Discard)
+	require.NoError(t, err)
+	assert.True(t, cfg.jsonOutput)
+	assert.Equal(t, "/dev/ttyUSB0", cfg.devicePath)
+	assert.Equal(t, "read", cfg.command)
+	assert.Equal(t, []string{"-timeout", "5s"}, cfg.args)
+
+	// Without a command the legacy read and write modes run
+	cfg, err = parseConfig([]string{"-write", "hello"}, io.Discard)
+	require.NoError(t, err)
+	assert.Empty(t, cfg.command)
+	assert.Equal(t, "hello", cfg.writeText)
+}
+
+func TestInfoAndDiagnose(t *testing.T) {
+	t.Parallel()
+
+	env, out := newTestEnv(t, pn532sim.NewNTAG215(testUID7))
+
+	var info infoResult
+	runJSON(t, env, out, &info, "info")
+	assert.NotEmpty(t, info.Firmware.Version)
+	assert.True(t, info.Firmware.ISO14443A)
+	require.NotNil(t, info.Tag)
+	assert.Equal(t, "NTAG215", info.Tag.Variant)
+	assert.Equal(t, "04a1a2a3a4a5a6", info.Tag.UID)
+
+	var diag diagnoseResult
+	runJSON(t, env, out, &diag, "diagnose")
+	assert.True(t, diag.Passed)
+	assert.Len(t, diag.Tests, len(diagnoseTests))
+
+	// No tag is not an error for info
+	env, out = newTestEnv(t)
+	info = infoResult{}
+	runJSON(t, env, out, &info, "info")
+	assert.Nil(t, info.Tag)
+}
+
+func TestWriteAndRead(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		tag  pn532sim.Tag
+		name string
+	}{
+		{name: "NTAG", tag: pn532sim.NewNTAG216(testUID7)},
+		{name: "MIFARE Classic", tag: pn532sim.NewClassic1K(testUID4)},
+		{name: "FeliCa", tag: pn532sim.NewFeliCa(testIDm)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			env, out := newTestEnv(t, tt.tag)
+			require.NoError(t, runTestCommand(env, "format"))
+
+			require.NoError(t, runTestCommand(env, "write", "-text", "hello", "-uri", "https://zaparoo.org",
+				"-wifi-ssid", "venue", "-wifi-key", "secret123"))
+			var read readResult
+			runJSON(t, env, out, &read, "read")
+			require.Len(t, read.Records, 3)
+			assert.Equal(t, "hello", read.Records[0].Text)
+			assert.Equal(t, "https://zaparoo.org", read.Records[1].URI)
+			require.NotNil(t, read.Records[2].WiFi)
+			assert.Equal(t, "venue", read.Records[2].WiFi.SSID)
+			assert.Equal(t, "wpa2-psk", read.Records[2].WiFi.Auth)
+		})
+	}
+}
+
+func TestWriteAndRead_TextOutput(t *testing.T) {
+	t.Parallel()
+
+	env, out := newTestEnv(t, pn532sim.NewNTAG213(testUID7))
+	env.out.json = false
+	require.NoError(t, runTestCommand(env, "write", "-vcard-name", "Jo Tech", "-vcard-phone", "+15550100"))
+
+	out.Reset()
+	require.NoError(t, runTestCommand(env, "read"))
+	assert.Contains(t, out.String(), "NTAG213")
+	assert.Contains(t, out.String(), "[0] vcard: Jo Tech")
+}
+
+func TestWrite_RawFile(t *testing.T) {
+	t.Parallel()
+
+	tlv, err := pn532.BuildNDEFMessageEx([]pn532.NDEFRecord{
+		{Type: pn532.NDEFTypeURI, URI: "https://zaparoo.org"},
+		{Type: "media:application/json", Payload: []byte(`{"id":1}`)},
+	})
+	require.NoError(t, err)
+	// A bare message without the TLV header and terminator
+	dir := t.TempDir()
+	bare := filepath.Join(dir, "bare.ndef")
+	require.NoError(t, os.WriteFile(bare, tlv[2:len(tlv)-1], 0o600))
+	wrapped := filepath.Join(dir, "tlv.ndef")
+	require.NoError(t, os.WriteFile(wrapped, tlv, 0o600))
+
+	for _, file := range []string{bare, wrapped} {
+		env, out := newTestEnv(t, pn532sim.NewNTAG215(testUID7))
+		require.NoError(t, runTestCommand(env, "write", "-file", file))
+
+		var read readResult
+		runJSON(t, env, out, &read, "read")
+		require.Len(t, read.Records, 2)
+		assert.Equal(t, "https://zaparoo.org", read.Records[0].URI)
+		assert.Equal(t, "media:application/json", read.Records[1].Type)
+		assert.Equal(t, "7b226964223a317d", read.Records[1].Payload)
+	}
+}
+
+func TestReadNDEFFile_Invalid(t *testing.T) {
+	t.Parallel()
+
+	// A short text record "hi" followed by a record of unknown TNF
+	text := []byte{0x91, 0x01, 0x05, 'T', 0x02, 'e', 'n', 'h', 'i'}
+	unknown := []byte{0x55, 0x00, 0x01, 0x00}
+
+	tests := []struct {
+		name    string
+		wantErr string
+		data    []byte
+	}{
+		{name: "empty", data: []byte{}, wantErr: "empty"},
+		{name: "unsupported record", data: append(text, unknown...), wantErr: "2 records but only 1"},
+		{name: "truncated TLV", data: []byte{0x03, 0xFF, 0x01}, wantErr: "truncated"},
+		{name: "TLV longer than file", data: []byte{0x03, 0x20, 0xD0, 0x00, 0x00}, wantErr: "exceeds"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			file := filepath.Join(t.TempDir(), "msg.ndef")
+			require.NoError(t, os.WriteFile(file, tt.data, 0o600))
+			_, err := readNDEFFile(file)
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestRecordOptions_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		opts recordOptions
+	}{
+		{name: "nothing to write"},
+		{name: "file with records", opts: recordOptions{text: "a", file: "msg.ndef"}},
+		{name: "WPA2 without key", opts: recordOptions{wifiSSID: "venue", wifiAuth: "wpa2-psk"}},
+		{name: "unknown auth", opts: recordOptions{wifiSSID: "venue", wifiAuth: "wep"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := tt.opts.records()
+			require.ErrorIs(t, err, errUsage)
+		})
+	}
+}
+
+func TestWrapNDEFTLV(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []byte{0x03, 0x02, 0xAA, 0xBB, 0xFE}, wrapNDEFTLV([]byte{0xAA, 0xBB}))
+
+	long := wrapNDEFTLV(make([]byte, 300))
+	assert.Equal(t, []byte{0x03, 0xFF, 0x01, 0x2C}, long[:4])
+	assert.Len(t, long, 4+300+1)
+}
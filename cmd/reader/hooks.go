@@ -0,0 +1,271 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ZaparooProject/go-pn532/polling"
+)
+
+// Hook defaults
+const (
+	defaultHookTimeout = 10 * time.Second
+	// hookQueueSize is how many hooks may wait for a free slot before new
+	// ones are skipped
+	hookQueueSize = 64
+	// hookWaitDelay bounds how long output of a killed hook is awaited, a
+	// shell's children may keep its pipes open
+	hookWaitDelay = time.Second
+)
+
+// Hook names, also passed to the command as PN532_EVENT
+const (
+	hookDetect = "detect"
+	hookRemove = "remove"
+)
+
+// hookConfig configures the commands run on tag events
+type hookConfig struct {
+	onDetect    string
+	onRemove    string
+	timeout     time.Duration
+	concurrency int
+}
+
+func (c *hookConfig) addFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.onDetect, "on-detect", "",
+		"Shell command to run when a tag is detected, after its NDEF message was read")
+	fs.StringVar(&c.onRemove, "on-remove", "", "Shell command to run when a tag is removed")
+	fs.DurationVar(&c.timeout, "hook-timeout", defaultHookTimeout, "Time limit of a hook command")
+	fs.IntVar(&c.concurrency, "hook-concurrency", 1,
+		"How many hook commands may run at once, 1 runs them in event order")
+}
+
+func (c *hookConfig) validate() error {
+	if c.timeout <= 0 {
+		return fmt.Errorf("%w: -hook-timeout must be positive", errUsage)
+	}
+	if c.concurrency < 1 {
+		return fmt.Errorf("%w: -hook-concurrency must be at least 1", errUsage)
+	}
+	return nil
+}
+
+// command returns the command of a hook, empty if not configured
+func (c *hookConfig) command(hook string) string {
+	if hook == hookDetect {
+		return c.onDetect
+	}
+	return c.onRemove
+}
+
+// hookFor names the hook an event triggers, empty for none. With NDEF
+// reading the detect hook waits for the read event, which carries the records.
+func hookFor(eventType polling.EventType, readNDEF bool) string {
+	switch eventType {
+	case polling.EventDetected, polling.EventChanged:
+		if !readNDEF {
+			return hookDetect
+		}
+	case polling.EventRead:
+		return hookDetect
+	case polling.EventRemoved:
+		return hookRemove
+	case polling.EventError, polling.EventReaderLost, polling.EventRestart, polling.EventHealth,
+		polling.EventRecovery:
+	}
+	return ""
+}
+
+// hookJob is a hook command to run for an event
+type hookJob struct {
+	event   *eventView
+	hook    string
+	command string
+}
+
+// hookRunner runs hook commands on a fixed number of workers. A worker runs
+// one command at a time, so a single worker keeps the event order.
+type hookRunner struct {
+	ctx     context.Context
+	config  *hookConfig
+	jobs    chan hookJob
+	output  *syncWriter
+	workers sync.WaitGroup
+}
+
+// newHookRunner starts the hook workers, hook output and failures go to
+// output. Hooks still running when ctx is cancelled are killed.
+func newHookRunner(ctx context.Context, config *hookConfig, output io.Writer) *hookRunner {
+	runner := &hookRunner{
+		ctx:    ctx,
+		config: config,
+		output: &syncWriter{out: output},
+	}
+	if config.onDetect == "" && config.onRemove == "" {
+		return runner
+	}
+
+	runner.jobs = make(chan hookJob, hookQueueSize)
+	for range config.concurrency {
+		runner.workers.Add(1)
+		go runner.work()
+	}
+	return runner
+}
+
+// handle queues the hook an event triggers, if it is configured
+func (h *hookRunner) handle(eventType polling.EventType, event *eventView, readNDEF bool) {
+	hook := hookFor(eventType, readNDEF)
+	if h.jobs == nil || hook == "" {
+		return
+	}
+	command := h.config.command(hook)
+	if command == "" {
+		return
+	}
+
+	select {
+	case h.jobs <- hookJob{event: event, hook: hook, command: command}:
+	default:
+		h.output.printf("Skipped %s hook for %s: too many hooks waiting\n", hook, event.UID)
+	}
+}
+
+// close waits for the queued hooks to finish
+func (h *hookRunner) close() {
+	if h.jobs == nil {
+		return
+	}
+	close(h.jobs)
+	h.workers.Wait()
+}
+
+func (h *hookRunner) work() {
+	defer h.workers.Done()
+	for job := range h.jobs {
+		if h.ctx.Err() != nil {
+			continue
+		}
+		if err := h.run(&job); err != nil {
+			h.output.printf("Hook %s failed for %s: %v\n", job.hook, job.event.UID, err)
+		}
+	}
+}
+
+// run runs a hook command. The event is passed as JSON on stdin and as
+// PN532_* environment variables.
+func (h *hookRunner) run(job *hookJob) error {
+	input, err := json.Marshal(job.event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(h.ctx, h.config.timeout)
+	defer cancel()
+
+	cmd := shellCommand(ctx, job.command)
+	cmd.Env = append(os.Environ(), hookEnv(job)...)
+	cmd.Stdin = bytes.NewReader(append(input, '\n'))
+	cmd.Stdout = h.output
+	cmd.Stderr = h.output
+	cmd.WaitDelay = hookWaitDelay
+
+	err = cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s", h.config.timeout)
+	}
+	if err != nil {
+		return fmt.Errorf("command failed: %w", err)
+	}
+	return nil
+}
+
+// shellCommand runs a command line with the system shell
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "/bin/sh", "-c", command)
+}
+
+// hookEnv returns the environment variables describing a hook event. The text
+// and URI are those of the first record of each type.
+func hookEnv(job *hookJob) []string {
+	event := job.event
+	env := []string{
+		"PN532_EVENT=" + job.hook,
+		"PN532_TIME=" + event.Time.Format(time.RFC3339Nano),
+		"PN532_UID=" + event.UID,
+		"PN532_TAG_TYPE=" + event.TagType,
+		"PN532_READER=" + event.Reader,
+		"PN532_READER_ID=" + event.ReaderID,
+		"PN532_RECORDS=" + strconv.Itoa(len(event.Records)),
+	}
+
+	var text, uri string
+	for i := range event.Records {
+		if text == "" {
+			text = event.Records[i].Text
+		}
+		if uri == "" {
+			uri = event.Records[i].URI
+		}
+	}
+	env = append(env, "PN532_TEXT="+text, "PN532_URI="+uri)
+	if event.Error != "" {
+		env = append(env, "PN532_ERROR="+event.Error)
+	}
+	return env
+}
+
+// syncWriter serializes the output of concurrent hooks
+type syncWriter struct {
+	out io.Writer
+	mu  sync.Mutex
+}
+
+func (w *syncWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	n, err := w.out.Write(p)
+	if err != nil {
+		return n, fmt.Errorf("failed to write hook output: %w", err)
+	}
+	return n, nil
+}
+
+func (w *syncWriter) printf(format string, args ...any) {
+	_, _ = fmt.Fprintf(w, format, args...)
+}
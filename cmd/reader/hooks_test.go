@@ -0,0 +1,171 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ZaparooProject/go-pn532/pn532sim"
+	"github.com/ZaparooProject/go-pn532/polling"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func skipWithoutShell(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("hook tests use /bin/sh")
+	}
+}
+
+func TestHookFor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		want      string
+		eventType polling.EventType
+		readNDEF  bool
+	}{
+		{name: "detected while reading", eventType: polling.EventDetected, readNDEF: true, want: ""},
+		{name: "detected without reading", eventType: polling.EventDetected, want: hookDetect},
+		{name: "changed without reading", eventType: polling.EventChanged, want: hookDetect},
+		{name: "read", eventType: polling.EventRead, readNDEF: true, want: hookDetect},
+		{name: "removed", eventType: polling.EventRemoved, want: hookRemove},
+		{name: "error", eventType: polling.EventError, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, hookFor(tt.eventType, tt.readNDEF))
+		})
+	}
+}
+
+func TestWatch_Hooks(t *testing.T) {
+	t.Parallel()
+	skipWithoutShell(t)
+
+	tag := pn532sim.NewNTAG213(testUID7)
+	sim, err := pn532sim.New(pn532sim.WithTags(tag))
+	require.NoError(t, err)
+	env, _ := newSimEnv(t, sim)
+	require.NoError(t, runTestCommand(env, "format"))
+	require.NoError(t, runTestCommand(env, "write", "-text", "hello", "-uri", "https://zaparoo.org"))
+
+	dir := t.TempDir()
+	log := filepath.Join(dir, "hooks.log")
+	opts := &watchOptions{
+		readNDEF: true,
+		hooks: hookConfig{
+			onDetect: `echo "$PN532_EVENT $PN532_UID $PN532_TAG_TYPE $PN532_TEXT $PN532_URI" >> ` + log +
+				` && cat > ` + filepath.Join(dir, "detect.json"),
+			onRemove:    `echo "$PN532_EVENT $PN532_UID" >> ` + log,
+			timeout:     5 * time.Second,
+			concurrency: 1,
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- env.watch(ctx, opts)
+	}()
+
+	readLog := func() string {
+		data, _ := os.ReadFile(log) //nolint:gosec // test file in a temp dir
+		return string(data)
+	}
+	require.Eventually(t, func() bool { return strings.Contains(readLog(), "detect") },
+		5*time.Second, 20*time.Millisecond)
+	sim.RemoveTag(tag)
+	require.Eventually(t, func() bool { return strings.Contains(readLog(), "remove") },
+		5*time.Second, 20*time.Millisecond)
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+
+	assert.Equal(t, "detect 04a1a2a3a4a5a6 NTAG hello https://zaparoo.org\nremove 04a1a2a3a4a5a6\n", readLog())
+
+	data, err := os.ReadFile(filepath.Join(dir, "detect.json")) //nolint:gosec // test file in a temp dir
+	require.NoError(t, err)
+	var event eventView
+	require.NoError(t, json.Unmarshal(data, &event))
+	assert.Equal(t, "read", event.Event)
+	assert.Equal(t, "04a1a2a3a4a5a6", event.UID)
+	require.Len(t, event.Records, 2)
+	assert.Equal(t, "hello", event.Records[0].Text)
+}
+
+func TestHookRunner_Failures(t *testing.T) {
+	t.Parallel()
+	skipWithoutShell(t)
+
+	tests := []struct {
+		name    string
+		command string
+		want    string
+	}{
+		{name: "timeout", command: "sleep 5", want: "Hook remove failed for 0102: timed out after 100ms"},
+		{
+			name:    "exit status",
+			command: "echo oops; exit 3",
+			want:    "oops\nHook remove failed for 0102: command failed: exit status 3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			output := &bytes.Buffer{}
+			config := &hookConfig{onRemove: tt.command, timeout: 100 * time.Millisecond, concurrency: 2}
+			runner := newHookRunner(context.Background(), config, output)
+			start := time.Now()
+			runner.handle(polling.EventRemoved, &eventView{Event: "removed", UID: "0102"}, true)
+			// Detect hooks are not configured
+			runner.handle(polling.EventRead, &eventView{Event: "read", UID: "0102"}, true)
+			runner.close()
+
+			assert.Less(t, time.Since(start), 3*time.Second)
+			assert.Equal(t, tt.want+"\n", output.String())
+		})
+	}
+}
+
+func TestHookConfig_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, args := range [][]string{{"-hook-timeout", "0s"}, {"-hook-concurrency", "0"}} {
+		err := runTestCommand(&environment{}, "watch", args...)
+		require.ErrorIs(t, err, errUsage, args)
+	}
+}
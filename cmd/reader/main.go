@@ -25,6 +25,7 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"strings"
@@ -45,24 +46,41 @@ import (
 type config struct {
 	writeText  string
 	devicePath string
+	command    string
+	args       []string
 	debug      bool
+	jsonOutput bool
 }
 
-func parseConfig() *config {
-	cfg := &config{}
+// addGlobalFlags registers the flags shared by all commands
+func (cfg *config) addGlobalFlags(fs *flag.FlagSet) {
+	fs.StringVar(&cfg.devicePath, "device", cfg.devicePath, "Device path (auto-detect if empty)")
+	fs.BoolVar(&cfg.debug, "debug", cfg.debug, "Enable debug output")
+	fs.BoolVar(&cfg.jsonOutput, "json", cfg.jsonOutput, "Print results as JSON")
+}
 
-	flag.StringVar(&cfg.writeText, "write", "", "Text to write to the next scanned tag (exits after write)")
-	flag.StringVar(&cfg.devicePath, "device", "", "Device path (auto-detect if empty)")
-	flag.BoolVar(&cfg.debug, "debug", false, "Enable debug output")
+// parseConfig parses the global flags, the first argument after them names
+// the command
+func parseConfig(args []string, output io.Writer) (*config, error) {
+	cfg := &config{}
 
-	flag.Parse()
+	fs := flag.NewFlagSet("reader", flag.ContinueOnError)
+	fs.SetOutput(output)
+	fs.StringVar(&cfg.writeText, "write", "", "Text to write to the next scanned tag (exits after write)")
+	cfg.addGlobalFlags(fs)
+	fs.Usage = func() { printUsage(output, fs) }
 
-	// Enable debug output if --debug flag is set
-	if cfg.debug {
-		pn532.SetDebugEnabled(true)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil, err
+		}
+		return nil, errBadFlags
+	}
+	if fs.NArg() > 0 {
+		cfg.command, cfg.args = fs.Arg(0), fs.Args()[1:]
 	}
 
-	return cfg
+	return cfg, nil
 }
 
 // newTransportFromDevice creates a new transport from a detected device.
@@ -125,41 +143,47 @@ func newTransport(path string) (pn532.Transport, error) {
 	return transport, nil
 }
 
-func connectToDevice(ctx context.Context, cfg *config) (*pn532.Device, error) {
+// connectToDevice opens the reader and returns it with its path, which is
+// the detected one when auto-detecting
+func connectToDevice(ctx context.Context, cfg *config) (device *pn532.Device, path string, err error) {
 	var connectOpts []pn532.ConnectOption
 
+	path = cfg.devicePath
 	if cfg.devicePath == "" {
 		// Auto-detection case
 		connectOpts = append(connectOpts,
 			pn532.WithAutoDetection(),
-			pn532.WithTransportFromDeviceFactory(newTransportFromDevice))
+			pn532.WithTransportFromDeviceFactory(func(info detection.DeviceInfo) (pn532.Transport, error) {
+				path = info.Path
+				return newTransportFromDevice(info)
+			}))
 		if cfg.debug {
-			_, _ = fmt.Println("Auto-detecting PN532 devices...")
+			_, _ = fmt.Fprintln(os.Stderr, "Auto-detecting PN532 devices...")
 		}
 	} else {
 		// Specific device path
 		connectOpts = append(connectOpts, pn532.WithTransportFactory(newTransport))
 		if cfg.debug {
-			_, _ = fmt.Printf("Opening device: %s\n", cfg.devicePath)
+			_, _ = fmt.Fprintf(os.Stderr, "Opening device: %s\n", cfg.devicePath)
 		}
 	}
 
 	// Set reasonable timeout
 	connectOpts = append(connectOpts, pn532.WithConnectTimeout(5*time.Second))
 
-	device, err := pn532.ConnectDevice(cfg.devicePath, connectOpts...)
+	device, err = pn532.ConnectDevice(cfg.devicePath, connectOpts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to PN532 device: %w", err)
+		return nil, "", fmt.Errorf("failed to connect to PN532 device: %w", err)
 	}
 
 	// Show firmware version if debug enabled - use context-aware method
 	if cfg.debug {
 		if version, versionErr := device.GetFirmwareVersionContext(ctx); versionErr == nil {
-			_, _ = fmt.Printf("PN532 Firmware: %s\n", version.Version)
+			_, _ = fmt.Fprintf(os.Stderr, "PN532 Firmware: %s\n", version.Version)
 		}
 	}
 
-	return device, nil
+	return device, path, nil
 }
 
 func runReadMode(ctx context.Context, device *pn532.Device, _ *config) error {
@@ -281,8 +305,16 @@ func runWriteMode(ctx context.Context, device *pn532.Device, cfg *config) error
 }
 
 func run(ctx context.Context, cfg *config) error {
+	// Continuous reading with -json streams events like the watch command
+	if cfg.command == "" && cfg.writeText == "" && cfg.jsonOutput {
+		cfg.command = "watch"
+	}
+	if cfg.command != "" {
+		return runCommand(ctx, cfg)
+	}
+
 	// Connect to device
-	device, err := connectToDevice(ctx, cfg)
+	device, _, err := connectToDevice(ctx, cfg)
 	if err != nil {
 		return err
 	}
@@ -301,13 +333,53 @@ func run(ctx context.Context, cfg *config) error {
 	return runReadMode(ctx, device, cfg)
 }
 
+// runCommand runs a subcommand, the reader is connected when the command
+// first needs it
+func runCommand(ctx context.Context, cfg *config) error {
+	runCmd, err := parseCommand(cfg, cfg.command, cfg.args, os.Stderr)
+	if err != nil {
+		return err
+	}
+	// -debug may follow the command name
+	if cfg.debug {
+		pn532.SetDebugEnabled(true)
+	}
+
+	env := &environment{
+		out: &printer{out: os.Stdout, status: os.Stderr, json: cfg.jsonOutput},
+	}
+	env.connect = func(ctx context.Context) (*pn532.Device, error) {
+		device, path, err := connectToDevice(ctx, cfg)
+		env.readerPath = path
+		return device, err
+	}
+	defer func() {
+		if env.device == nil {
+			return
+		}
+		if err := env.device.Close(); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Failed to close device: %v\n", err)
+		}
+	}()
+
+	return runCmd(ctx, env)
+}
+
 func main() {
 	os.Exit(mainWithExitCode())
 }
 
 func mainWithExitCode() int {
 	// Parse command-line flags
-	cfg := parseConfig()
+	cfg, err := parseConfig(os.Args[1:], os.Stderr)
+	if err != nil {
+		return exitCode(err)
+	}
+
+	// Enable debug output if --debug flag is set
+	if cfg.debug {
+		pn532.SetDebugEnabled(true)
+	}
 
 	// Setup signal handling for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
@@ -317,18 +389,31 @@ func mainWithExitCode() int {
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 	go func() {
 		<-sigChan
-		_, _ = fmt.Print("\nShutting down gracefully...\n")
+		_, _ = fmt.Fprint(os.Stderr, "\nShutting down gracefully...\n")
 		cancel()
 	}()
 
 	// Run the main application logic
-	if err := run(ctx, cfg); err != nil {
-		if errors.Is(err, context.Canceled) {
-			// User requested shutdown, exit cleanly
-			return 0
-		}
+	return exitCode(run(ctx, cfg))
+}
+
+// exitCode reports an error and maps it to the process exit status: 2 for
+// invalid command lines, 1 for failures
+func exitCode(err error) int {
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.Is(err, context.Canceled):
+		// User requested shutdown, exit cleanly
+		return 0
+	case errors.Is(err, errBadFlags):
+		// The flag package already reported the problem
+		return 2
+	case errors.Is(err, errUsage):
+		_, _ = fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	default:
 		_, _ = fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return 1
 	}
-	return 0
 }
@@ -0,0 +1,227 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	pn532 "github.com/ZaparooProject/go-pn532"
+	"github.com/ZaparooProject/go-pn532/tagops"
+)
+
+// result is the outcome of a command, printed as text or as JSON
+type result interface {
+	writeText(out io.Writer)
+}
+
+// printer writes command results to stdout and progress messages to stderr,
+// so JSON output stays parseable
+type printer struct {
+	out    io.Writer
+	status io.Writer
+	json   bool
+}
+
+// print writes a result, as a single line JSON object in JSON mode
+func (p *printer) print(r result) error {
+	if !p.json {
+		r.writeText(p.out)
+		return nil
+	}
+	if err := json.NewEncoder(p.out).Encode(r); err != nil {
+		return fmt.Errorf("failed to encode JSON output: %w", err)
+	}
+	return nil
+}
+
+// statusf writes a progress message
+func (p *printer) statusf(format string, args ...any) {
+	_, _ = fmt.Fprintf(p.status, format+"\n", args...)
+}
+
+// tagView is the printable form of a detected tag
+type tagView struct {
+	Type        string `json:"type"`
+	Variant     string `json:"variant,omitempty"`
+	UID         string `json:"uid"`
+	IDm         string `json:"idm,omitempty"`
+	PMm         string `json:"pmm,omitempty"`
+	TotalMemory int    `json:"total_memory"`
+	UserMemory  int    `json:"user_memory"`
+	Sectors     int    `json:"sectors,omitempty"`
+	SystemCode  uint16 `json:"system_code,omitempty"`
+	NDEF        bool   `json:"ndef"`
+}
+
+func newTagView(ops *tagops.TagOperations) (*tagView, error) {
+	info, err := ops.GetTagInfo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tag info: %w", err)
+	}
+
+	view := &tagView{
+		Type:        info.TypeName,
+		Variant:     info.NTAGType + info.MIFAREType,
+		UID:         hex.EncodeToString(info.UID),
+		IDm:         hex.EncodeToString(info.IDm),
+		PMm:         hex.EncodeToString(info.PMm),
+		TotalMemory: info.TotalMemory,
+		UserMemory:  info.UserMemory,
+		Sectors:     info.Sectors,
+		SystemCode:  info.SystemCode,
+		NDEF:        ops.IsNDEFCapable(),
+	}
+	return view, nil
+}
+
+func (v *tagView) writeText(out io.Writer) {
+	name := v.Type
+	if v.Variant != "" {
+		name = v.Variant
+	}
+	_, _ = fmt.Fprintf(out, "Tag:          %s\n", name)
+	_, _ = fmt.Fprintf(out, "UID:          %s\n", v.UID)
+	if v.IDm != "" {
+		_, _ = fmt.Fprintf(out, "PMm:          %s\n", v.PMm)
+		_, _ = fmt.Fprintf(out, "System code:  %04X\n", v.SystemCode)
+	}
+	if v.Sectors > 0 {
+		_, _ = fmt.Fprintf(out, "Sectors:      %d\n", v.Sectors)
+	}
+	_, _ = fmt.Fprintf(out, "Memory:       %d bytes (%d usable)\n", v.TotalMemory, v.UserMemory)
+	_, _ = fmt.Fprintf(out, "NDEF:         %s\n", yesNo(v.NDEF))
+}
+
+// recordView is the printable form of an NDEF record. Payloads of records
+// without a decoded form are hex encoded.
+type recordView struct {
+	WiFi    *wifiView  `json:"wifi,omitempty"`
+	VCard   *vcardView `json:"vcard,omitempty"`
+	Type    string     `json:"type"`
+	Text    string     `json:"text,omitempty"`
+	URI     string     `json:"uri,omitempty"`
+	Payload string     `json:"payload,omitempty"`
+}
+
+type wifiView struct {
+	SSID       string `json:"ssid"`
+	NetworkKey string `json:"network_key,omitempty"`
+	Auth       string `json:"auth"`
+	Hidden     bool   `json:"hidden,omitempty"`
+}
+
+type vcardView struct {
+	Phones       map[string]string `json:"phones,omitempty"`
+	Emails       map[string]string `json:"emails,omitempty"`
+	Name         string            `json:"name"`
+	Organization string            `json:"organization,omitempty"`
+	URL          string            `json:"url,omitempty"`
+}
+
+func newRecordViews(msg *pn532.NDEFMessage) []recordView {
+	if msg == nil {
+		return []recordView{}
+	}
+
+	views := make([]recordView, 0, len(msg.Records))
+	for i := range msg.Records {
+		rec := &msg.Records[i]
+		view := recordView{Type: string(rec.Type)}
+		switch {
+		case rec.Type == pn532.NDEFTypeText:
+			view.Text = rec.Text
+		case rec.Type == pn532.NDEFTypeURI:
+			view.URI = rec.URI
+		case rec.WiFi != nil:
+			view.WiFi = &wifiView{
+				SSID:       rec.WiFi.SSID,
+				NetworkKey: rec.WiFi.NetworkKey,
+				Auth:       wifiAuthName(rec.WiFi.AuthType),
+				Hidden:     rec.WiFi.Hidden,
+			}
+		case rec.VCard != nil:
+			view.VCard = &vcardView{
+				Name:         rec.VCard.FormattedName,
+				Organization: rec.VCard.Organization,
+				Phones:       rec.VCard.PhoneNumbers,
+				Emails:       rec.VCard.EmailAddresses,
+				URL:          rec.VCard.URL,
+			}
+		default:
+			view.Payload = hex.EncodeToString(rec.Payload)
+		}
+		views = append(views, view)
+	}
+	return views
+}
+
+// String returns a one line summary of the record
+func (v *recordView) String() string {
+	switch {
+	case v.WiFi != nil:
+		return fmt.Sprintf("wifi: %q (%s)", v.WiFi.SSID, v.WiFi.Auth)
+	case v.VCard != nil:
+		return fmt.Sprintf("vcard: %s", v.VCard.Name)
+	case v.Payload != "":
+		return fmt.Sprintf("%s: %d bytes %s", v.Type, len(v.Payload)/2, v.Payload)
+	default:
+		return fmt.Sprintf("%s: %s", v.Type, v.Text+v.URI)
+	}
+}
+
+// wifiAuthName names a WiFi Simple Configuration authentication type
+func wifiAuthName(authType uint16) string {
+	switch authType {
+	case pn532.AuthTypeOpen:
+		return "open"
+	case pn532.AuthTypeWPA:
+		return "wpa"
+	case pn532.AuthTypeWPAPSK:
+		return "wpa-psk"
+	case pn532.AuthTypeWPA2:
+		return "wpa2"
+	case pn532.AuthTypeWPA2PSK:
+		return "wpa2-psk"
+	default:
+		return fmt.Sprintf("0x%04X", authType)
+	}
+}
+
+// hexDump formats data as rows of blockSize bytes prefixed with their offset
+func hexDump(data []byte, blockSize int) string {
+	var sb strings.Builder
+	for i := 0; i < len(data); i += blockSize {
+		row := data[i:min(i+blockSize, len(data))]
+		_, _ = fmt.Fprintf(&sb, "%04X: % X\n", i, row)
+	}
+	return sb.String()
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,200 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	pn532 "github.com/ZaparooProject/go-pn532"
+	"github.com/hsanjuan/go-ndef"
+)
+
+// NDEF TLV framing of raw message files
+const (
+	ndefTLVType       = 0x03
+	ndefTLVTerminator = 0xFE
+	ndefTLVLongLength = 0xFF
+	ndefTLVShortMax   = 0xFE
+)
+
+// recordOptions are the write command flags describing the records to write
+type recordOptions struct {
+	text       string
+	uri        string
+	wifiSSID   string
+	wifiKey    string
+	wifiAuth   string
+	vcardName  string
+	vcardPhone string
+	vcardEmail string
+	vcardOrg   string
+	file       string
+	wifiHidden bool
+}
+
+// records builds the NDEF records in flag order: text, URI, WiFi and vCard.
+// A raw NDEF file can't be combined with other records.
+func (o *recordOptions) records() ([]pn532.NDEFRecord, error) {
+	var records []pn532.NDEFRecord
+	if o.text != "" {
+		records = append(records, pn532.NDEFRecord{Type: pn532.NDEFTypeText, Text: o.text})
+	}
+	if o.uri != "" {
+		records = append(records, pn532.NDEFRecord{Type: pn532.NDEFTypeURI, URI: o.uri})
+	}
+	if o.wifiSSID != "" {
+		cred, err := o.wifiCredential()
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, pn532.NDEFRecord{Type: pn532.NDEFTypeWiFi, WiFi: cred})
+	}
+	if o.vcardName != "" {
+		records = append(records, pn532.NDEFRecord{Type: pn532.NDEFTypeVCard, VCard: o.vcardContact()})
+	}
+
+	if o.file != "" {
+		if len(records) > 0 {
+			return nil, fmt.Errorf("%w: -file can't be combined with other records", errUsage)
+		}
+		return readNDEFFile(o.file)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%w: nothing to write, use -text, -uri, -wifi-ssid, -vcard-name or -file", errUsage)
+	}
+	return records, nil
+}
+
+// wifiCredential builds the WiFi record, open networks have no key
+func (o *recordOptions) wifiCredential() (*pn532.WiFiCredential, error) {
+	auth := o.wifiAuth
+	if auth == "" {
+		auth = "wpa2-psk"
+		if o.wifiKey == "" {
+			auth = "open"
+		}
+	}
+
+	cred := &pn532.WiFiCredential{
+		SSID:       o.wifiSSID,
+		NetworkKey: o.wifiKey,
+		Hidden:     o.wifiHidden,
+	}
+	switch auth {
+	case "open":
+		cred.AuthType, cred.EncryptionType = pn532.AuthTypeOpen, pn532.EncryptTypeNone
+	case "wpa-psk":
+		cred.AuthType, cred.EncryptionType = pn532.AuthTypeWPAPSK, pn532.EncryptTypeTKIP
+	case "wpa2-psk":
+		cred.AuthType, cred.EncryptionType = pn532.AuthTypeWPA2PSK, pn532.EncryptTypeAES
+	default:
+		return nil, fmt.Errorf("%w: unknown WiFi authentication %q, use open, wpa-psk or wpa2-psk", errUsage, auth)
+	}
+	if cred.AuthType != pn532.AuthTypeOpen && cred.NetworkKey == "" {
+		return nil, fmt.Errorf("%w: %s needs -wifi-key", errUsage, auth)
+	}
+	return cred, nil
+}
+
+func (o *recordOptions) vcardContact() *pn532.VCardContact {
+	contact := &pn532.VCardContact{
+		FormattedName: o.vcardName,
+		Organization:  o.vcardOrg,
+	}
+	if o.vcardPhone != "" {
+		contact.PhoneNumbers = map[string]string{"CELL": o.vcardPhone}
+	}
+	if o.vcardEmail != "" {
+		contact.EmailAddresses = map[string]string{"INTERNET": o.vcardEmail}
+	}
+	return contact
+}
+
+// readNDEFFile reads the records of a raw NDEF message file. The file holds
+// either a bare NDEF message or one wrapped in an NDEF TLV, as found on
+// Type 2 tags. Files with records this library cannot encode again are
+// rejected rather than written back partially.
+func readNDEFFile(path string) ([]pn532.NDEFRecord, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path is supplied by the user
+	if err != nil {
+		return nil, fmt.Errorf("failed to read NDEF file: %w", err)
+	}
+	if len(data) == 0 {
+		return nil, errors.New("NDEF file is empty")
+	}
+
+	bare := data
+	if data[0] == ndefTLVType {
+		if bare, err = unwrapNDEFTLV(data); err != nil {
+			return nil, err
+		}
+	} else {
+		data = wrapNDEFTLV(data)
+	}
+
+	raw := &ndef.Message{}
+	if _, err = raw.Unmarshal(bare); err != nil {
+		return nil, fmt.Errorf("failed to parse NDEF file: %w", err)
+	}
+	msg, err := pn532.ParseNDEFMessage(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse NDEF file: %w", err)
+	}
+	if len(msg.Records) != len(raw.Records) {
+		return nil, fmt.Errorf("NDEF file holds %d records but only %d are supported",
+			len(raw.Records), len(msg.Records))
+	}
+	return msg.Records, nil
+}
+
+// unwrapNDEFTLV returns the NDEF message held in an NDEF TLV
+func unwrapNDEFTLV(tlv []byte) ([]byte, error) {
+	start, length := 2, 0
+	switch {
+	case len(tlv) < 2:
+		return nil, errors.New("NDEF TLV is truncated")
+	case tlv[1] == ndefTLVLongLength:
+		if len(tlv) < 4 {
+			return nil, errors.New("NDEF TLV is truncated")
+		}
+		start, length = 4, int(tlv[2])<<8|int(tlv[3])
+	default:
+		length = int(tlv[1])
+	}
+	if start+length > len(tlv) {
+		return nil, fmt.Errorf("NDEF TLV length %d exceeds the file", length)
+	}
+	return tlv[start : start+length], nil
+}
+
+// wrapNDEFTLV wraps a bare NDEF message in an NDEF TLV with a terminator
+func wrapNDEFTLV(msg []byte) []byte {
+	tlv := make([]byte, 0, len(msg)+5)
+	if len(msg) < ndefTLVShortMax {
+		tlv = append(tlv, ndefTLVType, byte(len(msg)))
+	} else {
+		tlv = append(tlv, ndefTLVType, ndefTLVLongLength, byte(len(msg)>>8), byte(len(msg)))
+	}
+	tlv = append(tlv, msg...)
+	return append(tlv, ndefTLVTerminator)
+}
@@ -0,0 +1,475 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	pn532 "github.com/ZaparooProject/go-pn532"
+	"github.com/ZaparooProject/go-pn532/tagops"
+)
+
+// Tag memory layout used by dump and restore
+const (
+	ntagPageBytes  = 4
+	ntagUserStart  = 4
+	blockBytes     = 16
+	mifareDataFrom = 4 // first block after sector 0, which holds the MAD
+	// ntagConfigPages follow the user memory: dynamic lock, CFG0, CFG1, PWD and PACK
+	ntagConfigPages = 5
+	// feliCaServiceNDEFWrite is the NDEF read/write service used for the AIB
+	feliCaServiceNDEFWrite = 0x0009
+)
+
+// readResult is a tag and its decoded NDEF records
+type readResult struct {
+	Tag     *tagView     `json:"tag"`
+	Records []recordView `json:"records"`
+}
+
+func (r *readResult) writeText(out io.Writer) {
+	r.Tag.writeText(out)
+	_, _ = fmt.Fprintf(out, "Records:      %d\n", len(r.Records))
+	for i := range r.Records {
+		_, _ = fmt.Fprintf(out, "  [%d] %s\n", i, &r.Records[i])
+	}
+}
+
+func setupRead(fs *flag.FlagSet) runFunc {
+	timeout := fs.Duration("timeout", defaultTagTimeout, "How long to wait for a tag")
+
+	return func(ctx context.Context, env *environment) error {
+		ops, view, err := env.detectTag(ctx, *timeout)
+		if err != nil {
+			return err
+		}
+
+		msg, err := ops.Tag().ReadNDEF()
+		if err != nil && !errors.Is(err, pn532.ErrNoNDEF) {
+			return fmt.Errorf("failed to read NDEF: %w", err)
+		}
+		return env.out.print(&readResult{Tag: view, Records: newRecordViews(msg)})
+	}
+}
+
+func setupWrite(fs *flag.FlagSet) runFunc {
+	opts := &recordOptions{}
+	fs.StringVar(&opts.text, "text", "", "Text record")
+	fs.StringVar(&opts.uri, "uri", "", "URI record")
+	fs.StringVar(&opts.wifiSSID, "wifi-ssid", "", "WiFi record network name")
+	fs.StringVar(&opts.wifiKey, "wifi-key", "", "WiFi record network key")
+	fs.StringVar(&opts.wifiAuth, "wifi-auth", "", "WiFi record authentication: open, wpa-psk or wpa2-psk "+
+		"(default wpa2-psk with a key, open without)")
+	fs.BoolVar(&opts.wifiHidden, "wifi-hidden", false, "WiFi record network is hidden")
+	fs.StringVar(&opts.vcardName, "vcard-name", "", "vCard record full name")
+	fs.StringVar(&opts.vcardPhone, "vcard-phone", "", "vCard record phone number")
+	fs.StringVar(&opts.vcardEmail, "vcard-email", "", "vCard record email address")
+	fs.StringVar(&opts.vcardOrg, "vcard-org", "", "vCard record organization")
+	fs.StringVar(&opts.file, "file", "", "Raw NDEF message file, bare or wrapped in an NDEF TLV")
+	timeout := fs.Duration("timeout", defaultTagTimeout, "How long to wait for a tag")
+
+	return func(ctx context.Context, env *environment) error {
+		records, err := opts.records()
+		if err != nil {
+			return err
+		}
+
+		ops, view, err := env.detectTag(ctx, *timeout)
+		if err != nil {
+			return err
+		}
+
+		msg := &pn532.NDEFMessage{Records: records}
+		if err := ops.Tag().WriteNDEF(msg); err != nil {
+			return fmt.Errorf("failed to write NDEF: %w", err)
+		}
+		return env.out.print(&writeResult{Tag: view, Records: newRecordViews(msg)})
+	}
+}
+
+// writeResult is a tag and the records written to it
+type writeResult readResult
+
+func (r *writeResult) writeText(out io.Writer) {
+	_, _ = fmt.Fprintf(out, "Wrote %d record(s) to tag %s\n", len(r.Records), r.Tag.UID)
+	for i := range r.Records {
+		_, _ = fmt.Fprintf(out, "  [%d] %s\n", i, &r.Records[i])
+	}
+}
+
+// dumpResult is the memory of a tag, hex encoded in JSON
+type dumpResult struct {
+	Tag       *tagView `json:"tag"`
+	File      string   `json:"file,omitempty"`
+	Data      string   `json:"data"`
+	raw       []byte
+	blockSize int
+}
+
+func (r *dumpResult) writeText(out io.Writer) {
+	if r.File != "" {
+		_, _ = fmt.Fprintf(out, "Wrote %d bytes of tag %s to %s\n", len(r.raw), r.Tag.UID, r.File)
+		return
+	}
+	r.Tag.writeText(out)
+	_, _ = fmt.Fprint(out, hexDump(r.raw, r.blockSize))
+}
+
+func setupDump(fs *flag.FlagSet) runFunc {
+	file := fs.String("o", "", "Write the dump to this file instead of printing it")
+	timeout := fs.Duration("timeout", defaultTagTimeout, "How long to wait for a tag")
+
+	return func(ctx context.Context, env *environment) error {
+		ops, view, err := env.detectTag(ctx, *timeout)
+		if err != nil {
+			return err
+		}
+
+		// MIFARE Classic sector trailers are left out, they hold the keys
+		data, err := ops.ReadAll(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to read tag: %w", err)
+		}
+		if *file != "" {
+			if err := os.WriteFile(*file, data, 0o600); err != nil {
+				return fmt.Errorf("failed to write dump: %w", err)
+			}
+		}
+
+		res := &dumpResult{Tag: view, File: *file, Data: hex.EncodeToString(data), raw: data, blockSize: blockBytes}
+		if ops.TagType() == tagops.TagTypeNTAG {
+			res.blockSize = ntagPageBytes
+		}
+		return env.out.print(res)
+	}
+}
+
+// restoreResult reports how much of a dump was written back
+type restoreResult struct {
+	Tag   *tagView `json:"tag"`
+	Bytes int      `json:"bytes"`
+}
+
+func (r *restoreResult) writeText(out io.Writer) {
+	_, _ = fmt.Fprintf(out, "Restored %d bytes to tag %s\n", r.Bytes, r.Tag.UID)
+}
+
+func setupRestore(fs *flag.FlagSet) runFunc {
+	file := fs.String("i", "", "Dump file written by the dump command")
+	timeout := fs.Duration("timeout", defaultTagTimeout, "How long to wait for a tag")
+
+	return func(ctx context.Context, env *environment) error {
+		if *file == "" {
+			return fmt.Errorf("%w: -i is required", errUsage)
+		}
+		data, err := os.ReadFile(*file)
+		if err != nil {
+			return fmt.Errorf("failed to read dump: %w", err)
+		}
+
+		ops, view, err := env.detectTag(ctx, *timeout)
+		if err != nil {
+			return err
+		}
+
+		written, err := restoreData(ctx, ops, view, data)
+		if err != nil {
+			return err
+		}
+		return env.out.print(&restoreResult{Tag: view, Bytes: written})
+	}
+}
+
+// restoreData writes the data area of a dump back to a tag of the same size.
+// Areas a tag can't take back are skipped: the UID, lock and capability
+// container pages and configuration pages of an NTAG, and sector 0 of a
+// MIFARE Classic, which holds the MAD under its own key. It returns the
+// number of bytes written.
+func restoreData(ctx context.Context, ops *tagops.TagOperations, view *tagView, data []byte) (int, error) {
+	size := view.TotalMemory
+	if ops.TagType() == tagops.TagTypeMIFARE {
+		// Dumps of MIFARE Classic tags leave out the sector trailers
+		size = view.UserMemory
+	}
+	if len(data) != size {
+		return 0, fmt.Errorf("dump holds %d bytes, the %s tag %d", len(data), view.Type, size)
+	}
+
+	var start byte
+	switch ops.TagType() {
+	case tagops.TagTypeNTAG:
+		dynLockPage := len(data)/ntagPageBytes - ntagConfigPages
+		start, data = ntagUserStart, data[ntagUserStart*ntagPageBytes:dynLockPage*ntagPageBytes]
+	case tagops.TagTypeMIFARE:
+		// The dump starts with the three data blocks of sector 0
+		start, data = mifareDataFrom, data[(mifareDataFrom-1)*blockBytes:]
+	case tagops.TagTypeFeliCa:
+		// The data blocks go first, the AIB holding their length last
+		if err := ops.WriteBlocks(ctx, 1, data[blockBytes:]); err != nil {
+			return 0, fmt.Errorf("failed to restore data blocks: %w", err)
+		}
+		if err := writeFeliCaAIB(ops, data[:blockBytes]); err != nil {
+			return 0, err
+		}
+		return len(data), nil
+	case tagops.TagTypeUnknown:
+		return 0, tagops.ErrUnsupportedTag
+	default:
+		return 0, tagops.ErrUnsupportedTag
+	}
+
+	if err := ops.WriteBlocks(ctx, start, data); err != nil {
+		return 0, fmt.Errorf("failed to restore data: %w", err)
+	}
+	return len(data), nil
+}
+
+// writeFeliCaAIB writes block 0 of a FeliCa tag through the NDEF write service
+func writeFeliCaAIB(ops *tagops.TagOperations, aib []byte) error {
+	felica, ok := ops.Tag().(*pn532.FeliCaTag)
+	if !ok {
+		return tagops.ErrUnsupportedTag
+	}
+
+	service := felica.GetServiceCode()
+	felica.SetServiceCode(feliCaServiceNDEFWrite)
+	defer felica.SetServiceCode(service)
+	if err := felica.WriteBlock(0, aib); err != nil {
+		return fmt.Errorf("failed to restore attribute information block: %w", err)
+	}
+	return nil
+}
+
+// formatResult lists the steps format ran
+type formatResult struct {
+	Tag   *tagView `json:"tag"`
+	Steps []string `json:"steps"`
+}
+
+func (r *formatResult) writeText(out io.Writer) {
+	_, _ = fmt.Fprintf(out, "Formatted tag %s\n", r.Tag.UID)
+	for _, step := range r.Steps {
+		_, _ = fmt.Fprintf(out, "  wrote %s\n", step)
+	}
+}
+
+func setupFormat(fs *flag.FlagSet) runFunc {
+	timeout := fs.Duration("timeout", defaultTagTimeout, "How long to wait for a tag")
+
+	return func(ctx context.Context, env *environment) error {
+		ops, view, err := env.detectTag(ctx, *timeout)
+		if err != nil {
+			return err
+		}
+
+		formatted, err := ops.Format(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to format tag: %w", err)
+		}
+		res := &formatResult{Tag: view, Steps: make([]string, 0, len(formatted.Steps))}
+		for _, step := range formatted.Steps {
+			res.Steps = append(res.Steps, string(step))
+		}
+		return env.out.print(res)
+	}
+}
+
+// lockResult lists the writes that make a tag read-only
+type lockResult struct {
+	Tag     *tagView         `json:"tag"`
+	Changes []lockChangeView `json:"changes"`
+	DryRun  bool             `json:"dry_run"`
+}
+
+type lockChangeView struct {
+	Description string `json:"description"`
+	Data        string `json:"data"`
+	Address     uint16 `json:"address"`
+}
+
+func (r *lockResult) writeText(out io.Writer) {
+	switch {
+	case len(r.Changes) == 0:
+		_, _ = fmt.Fprintf(out, "Tag %s is already read-only\n", r.Tag.UID)
+		return
+	case r.DryRun:
+		_, _ = fmt.Fprintf(out, "Locking tag %s would write:\n", r.Tag.UID)
+	default:
+		_, _ = fmt.Fprintf(out, "Locked tag %s, wrote:\n", r.Tag.UID)
+	}
+	for _, change := range r.Changes {
+		_, _ = fmt.Fprintf(out, "  %3d: %s  %s\n", change.Address, change.Data, change.Description)
+	}
+}
+
+func setupLock(fs *flag.FlagSet) runFunc {
+	dryRun := fs.Bool("dry-run", false, "List the writes without locking the tag")
+	confirm := fs.Bool("yes", false, "Confirm locking, it can't be undone")
+	timeout := fs.Duration("timeout", defaultTagTimeout, "How long to wait for a tag")
+
+	return func(ctx context.Context, env *environment) error {
+		if !*dryRun && !*confirm {
+			return fmt.Errorf("%w: locking can't be undone, check with -dry-run and lock with -yes", errUsage)
+		}
+
+		ops, view, err := env.detectTag(ctx, *timeout)
+		if err != nil {
+			return err
+		}
+
+		changes, err := ops.MakeReadOnly(ctx, *dryRun)
+		if err != nil {
+			return fmt.Errorf("failed to lock tag: %w", err)
+		}
+		res := &lockResult{Tag: view, DryRun: *dryRun, Changes: make([]lockChangeView, 0, len(changes))}
+		for _, change := range changes {
+			res.Changes = append(res.Changes, lockChangeView{
+				Description: change.Description,
+				Data:        hex.EncodeToString(change.Data),
+				Address:     change.Address,
+			})
+		}
+		return env.out.print(res)
+	}
+}
+
+// passwordResult reports a password operation on an NTAG
+type passwordResult struct {
+	Tag    *tagView `json:"tag"`
+	Action string   `json:"action"`
+	PACK   string   `json:"pack,omitempty"`
+}
+
+func (r *passwordResult) writeText(out io.Writer) {
+	_, _ = fmt.Fprintf(out, "Tag %s: %s\n", r.Tag.UID, r.Action)
+	if r.PACK != "" {
+		_, _ = fmt.Fprintf(out, "PACK:         %s\n", r.PACK)
+	}
+}
+
+// passwordOptions are the password command flags
+type passwordOptions struct {
+	auth    string
+	set     string
+	pack    string
+	authPWD []byte
+	newPWD  []byte
+	newPACK []byte
+	from    uint
+	timeout time.Duration
+	remove  bool
+}
+
+// decode checks the flag combination and decodes the hex values
+func (o *passwordOptions) decode() error {
+	var err error
+	if o.auth != "" {
+		if o.authPWD, err = hexFlag("auth", o.auth, 4); err != nil {
+			return err
+		}
+	}
+	if o.set != "" {
+		if o.newPWD, err = hexFlag("set", o.set, 4); err != nil {
+			return err
+		}
+	}
+	if o.newPACK, err = hexFlag("pack", o.pack, 2); err != nil {
+		return err
+	}
+
+	switch {
+	case o.set != "" && o.remove:
+		return fmt.Errorf("%w: -set and -remove can't be combined", errUsage)
+	case o.auth == "" && o.set == "" && !o.remove:
+		return fmt.Errorf("%w: use -auth, -set or -remove", errUsage)
+	case o.from > 0xFF:
+		return fmt.Errorf("%w: -from must be a page number up to 255", errUsage)
+	}
+	return nil
+}
+
+func setupPassword(fs *flag.FlagSet) runFunc {
+	opts := &passwordOptions{}
+	fs.StringVar(&opts.auth, "auth", "", "Current password to authenticate with, 8 hex digits")
+	fs.StringVar(&opts.set, "set", "", "New password, 8 hex digits")
+	fs.StringVar(&opts.pack, "pack", "0000", "Password acknowledge the tag answers with, 4 hex digits")
+	fs.UintVar(&opts.from, "from", ntagUserStart, "First page protected by the new password")
+	fs.BoolVar(&opts.remove, "remove", false, "Disable password protection")
+	fs.DurationVar(&opts.timeout, "timeout", defaultTagTimeout, "How long to wait for a tag")
+
+	return func(ctx context.Context, env *environment) error {
+		return runPassword(ctx, env, opts)
+	}
+}
+
+func runPassword(ctx context.Context, env *environment, opts *passwordOptions) error {
+	if err := opts.decode(); err != nil {
+		return err
+	}
+
+	ops, view, err := env.detectTag(ctx, opts.timeout)
+	if err != nil {
+		return err
+	}
+	ntag, ok := ops.Tag().(*pn532.NTAGTag)
+	if !ok {
+		return fmt.Errorf("%w: password protection needs an NTAG", tagops.ErrUnsupportedTag)
+	}
+	res := &passwordResult{Tag: view, Action: "authenticated"}
+
+	if opts.authPWD != nil {
+		ack, err := ntag.PwdAuth(opts.authPWD)
+		if err != nil {
+			return fmt.Errorf("failed to authenticate: %w", err)
+		}
+		res.PACK = hex.EncodeToString(ack)
+	}
+	switch {
+	case opts.remove:
+		if err := ntag.DisablePasswordProtection(); err != nil {
+			return fmt.Errorf("failed to remove password: %w", err)
+		}
+		res.Action = "password protection removed"
+	case opts.newPWD != nil:
+		auth0 := uint8(opts.from) //nolint:gosec // decode checked the range
+		if err := ntag.SetPasswordProtection(opts.newPWD, opts.newPACK, auth0); err != nil {
+			return fmt.Errorf("failed to set password: %w", err)
+		}
+		res.Action = fmt.Sprintf("password set, writes from page %d need it", opts.from)
+	}
+	return env.out.print(res)
+}
+
+// hexFlag decodes a hex flag value of size bytes
+func hexFlag(name, value string, size int) ([]byte, error) {
+	data, err := hex.DecodeString(value)
+	if err != nil || len(data) != size {
+		return nil, fmt.Errorf("%w: -%s must be %d hex digits", errUsage, name, 2*size)
+	}
+	return data, nil
+}
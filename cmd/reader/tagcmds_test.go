@@ -0,0 +1,159 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package main
+
+import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ZaparooProject/go-pn532/pn532sim"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDumpAndRestore(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		tag       pn532sim.Tag
+		name      string
+		dumpBytes int
+	}{
+		{name: "NTAG", tag: pn532sim.NewNTAG213(testUID7), dumpBytes: 45 * 4},
+		{name: "MIFARE Classic", tag: pn532sim.NewClassic1K(testUID4), dumpBytes: (64 - 16) * 16},
+		{name: "FeliCa", tag: pn532sim.NewFeliCa(testIDm), dumpBytes: 14 * 16},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			env, out := newTestEnv(t, tt.tag)
+			require.NoError(t, runTestCommand(env, "format"))
+			require.NoError(t, runTestCommand(env, "write", "-text", "before the dump"))
+
+			file := filepath.Join(t.TempDir(), "tag.bin")
+			var dump dumpResult
+			runJSON(t, env, out, &dump, "dump", "-o", file)
+			saved, err := os.ReadFile(file)
+			require.NoError(t, err)
+			assert.Len(t, saved, tt.dumpBytes)
+			assert.Equal(t, hex.EncodeToString(saved), dump.Data)
+
+			require.NoError(t, runTestCommand(env, "write", "-text", "overwritten"))
+			var restored restoreResult
+			runJSON(t, env, out, &restored, "restore", "-i", file)
+			assert.Positive(t, restored.Bytes)
+
+			var read readResult
+			runJSON(t, env, out, &read, "read")
+			require.Len(t, read.Records, 1)
+			assert.Equal(t, "before the dump", read.Records[0].Text)
+		})
+	}
+}
+
+func TestRestore_WrongSize(t *testing.T) {
+	t.Parallel()
+
+	env, _ := newTestEnv(t, pn532sim.NewNTAG215(testUID7))
+	file := filepath.Join(t.TempDir(), "ntag213.bin")
+	require.NoError(t, os.WriteFile(file, make([]byte, 45*4), 0o600))
+	require.ErrorContains(t, runTestCommand(env, "restore", "-i", file), "dump holds 180 bytes")
+	require.ErrorIs(t, runTestCommand(env, "restore"), errUsage)
+}
+
+func TestLock(t *testing.T) {
+	t.Parallel()
+
+	tag := pn532sim.NewNTAG213(testUID7)
+	env, out := newTestEnv(t, tag)
+	require.NoError(t, runTestCommand(env, "write", "-text", "final"))
+	require.ErrorIs(t, runTestCommand(env, "lock"), errUsage, "locking needs -yes")
+
+	before := tag.Memory()
+	var planned lockResult
+	runJSON(t, env, out, &planned, "lock", "-dry-run")
+	assert.True(t, planned.DryRun)
+	require.NotEmpty(t, planned.Changes)
+	assert.Equal(t, uint16(3), planned.Changes[0].Address, "capability container first")
+	assert.Equal(t, before, tag.Memory())
+
+	var locked lockResult
+	runJSON(t, env, out, &locked, "lock", "-yes")
+	assert.False(t, locked.DryRun)
+	assert.Equal(t, planned.Changes, locked.Changes)
+	require.Error(t, runTestCommand(env, "write", "-text", "rejected"))
+
+	var read readResult
+	runJSON(t, env, out, &read, "read")
+	require.Len(t, read.Records, 1)
+	assert.Equal(t, "final", read.Records[0].Text)
+}
+
+func TestPassword(t *testing.T) {
+	t.Parallel()
+
+	tag := pn532sim.NewNTAG213(testUID7)
+	env, out := newTestEnv(t, tag)
+
+	var res passwordResult
+	runJSON(t, env, out, &res, "password", "-set", "12345678", "-pack", "abcd")
+	assert.Contains(t, res.Action, "page 4")
+	require.Error(t, runTestCommand(env, "write", "-text", "no password"))
+
+	res = passwordResult{}
+	runJSON(t, env, out, &res, "password", "-auth", "12345678", "-remove")
+	assert.Equal(t, "abcd", res.PACK)
+	assert.Equal(t, "password protection removed", res.Action)
+	require.NoError(t, runTestCommand(env, "write", "-text", "open again"))
+
+	require.Error(t, runTestCommand(env, "password", "-auth", "00000000"), "wrong password")
+}
+
+func TestPassword_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "no action"},
+		{name: "set and remove", args: []string{"-set", "12345678", "-remove"}},
+		{name: "short password", args: []string{"-set", "1234"}},
+		{name: "not hex", args: []string{"-auth", "password"}},
+		{name: "page out of range", args: []string{"-set", "12345678", "-from", "256"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			env, _ := newTestEnv(t, pn532sim.NewNTAG213(testUID7))
+			require.ErrorIs(t, runTestCommand(env, "password", tt.args...), errUsage)
+		})
+	}
+
+	env, _ := newTestEnv(t, pn532sim.NewClassic1K(testUID4))
+	require.ErrorContains(t, runTestCommand(env, "password", "-remove"), "needs an NTAG")
+}
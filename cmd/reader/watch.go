@@ -0,0 +1,193 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ZaparooProject/go-pn532/polling"
+)
+
+// eventView is one line of the watch output, in JSON mode an NDJSON object
+type eventView struct {
+	Time     time.Time    `json:"time"`
+	Event    string       `json:"event"`
+	Reader   string       `json:"reader,omitempty"`
+	ReaderID string       `json:"reader_id,omitempty"`
+	UID      string       `json:"uid,omitempty"`
+	TagType  string       `json:"tag_type,omitempty"`
+	Error    string       `json:"error,omitempty"`
+	Health   string       `json:"health,omitempty"`
+	Recovery string       `json:"recovery,omitempty"`
+	Records  []recordView `json:"records,omitempty"`
+}
+
+func newEventView(event *polling.Event, readerPath string) *eventView {
+	view := &eventView{
+		Time:     event.Time,
+		Event:    event.Type.String(),
+		Reader:   readerPath,
+		ReaderID: event.Reader,
+		UID:      event.UID,
+	}
+	if event.Tag != nil {
+		view.TagType = string(event.Tag.Type)
+		if view.UID == "" {
+			view.UID = event.Tag.UID
+		}
+	}
+	if event.Err != nil {
+		view.Error = event.Err.Error()
+	}
+	if event.Message != nil {
+		view.Records = newRecordViews(event.Message)
+	}
+	switch event.Type {
+	case polling.EventHealth:
+		view.Health = event.Health.String()
+	case polling.EventRecovery:
+		view.Recovery = event.Recovery.String()
+	case polling.EventDetected, polling.EventRemoved, polling.EventChanged, polling.EventError,
+		polling.EventReaderLost, polling.EventRead, polling.EventRestart:
+	}
+	return view
+}
+
+func (v *eventView) writeText(out io.Writer) {
+	line := v.Time.Format("15:04:05.000") + " " + v.Event
+	if v.UID != "" {
+		line += " uid=" + v.UID
+	}
+	if v.TagType != "" {
+		line += " type=" + v.TagType
+	}
+	if v.Health != "" {
+		line += " health=" + v.Health
+	}
+	if v.Recovery != "" {
+		line += " step=" + v.Recovery
+	}
+	if v.Error != "" {
+		line += " error=" + v.Error
+	}
+	_, _ = fmt.Fprintln(out, line)
+	for i := range v.Records {
+		_, _ = fmt.Fprintf(out, "  %s\n", v.Records[i].String())
+	}
+}
+
+// watchOptions configures the watch command
+type watchOptions struct {
+	hooks    hookConfig
+	readNDEF bool
+}
+
+func setupWatch(fs *flag.FlagSet) runFunc {
+	opts := &watchOptions{}
+	noRead := fs.Bool("no-read", false, "Don't read the NDEF message of new tags")
+	opts.hooks.addFlags(fs)
+	return func(ctx context.Context, env *environment) error {
+		opts.readNDEF = !*noRead
+		if err := opts.hooks.validate(); err != nil {
+			return err
+		}
+		return env.watch(ctx, opts)
+	}
+}
+
+// watch prints the events of a polling session and runs the hooks until the
+// context is cancelled or polling stops
+func (env *environment) watch(ctx context.Context, opts *watchOptions) error {
+	device, err := env.reader(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Stops polling if printing fails
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	hooks := newHookRunner(ctx, &opts.hooks, env.out.status)
+	defer hooks.close()
+	handle := func(event *polling.Event) error {
+		view := newEventView(event, env.readerPath)
+		if err := env.out.print(view); err != nil {
+			return err
+		}
+		hooks.handle(event.Type, view, opts.readNDEF)
+		return nil
+	}
+
+	sessionConfig := polling.DefaultConfig()
+	sessionConfig.ReadNDEF = opts.readNDEF
+	// Every event is printed, polling waits for a slow consumer such as a
+	// full pipe instead of dropping events
+	sessionConfig.EventPolicy = polling.EventPolicyBlock
+	session := polling.NewSession(device, sessionConfig)
+	events := session.Events()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- session.Start(ctx)
+	}()
+	defer func() {
+		cancel()
+		<-done
+		_ = session.Close()
+	}()
+
+	env.out.statusf("Watching for tags. Press Ctrl+C to stop...")
+	for {
+		select {
+		case event := <-events:
+			if err := handle(&event); err != nil {
+				return err
+			}
+		case err := <-done:
+			// Start returned, give the deferred wait its result back
+			done <- err
+			return flushEvents(events, handle, err)
+		}
+	}
+}
+
+// flushEvents handles the events emitted before polling stopped and reports
+// why it stopped
+func flushEvents(events <-chan polling.Event, handle func(*polling.Event) error, stopErr error) error {
+	for {
+		select {
+		case event := <-events:
+			if err := handle(&event); err != nil {
+				return err
+			}
+		default:
+			if stopErr == nil || errors.Is(stopErr, context.Canceled) {
+				return stopErr
+			}
+			return fmt.Errorf("polling stopped: %w", stopErr)
+		}
+	}
+}
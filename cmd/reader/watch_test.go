@@ -0,0 +1,129 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	pn532 "github.com/ZaparooProject/go-pn532"
+	"github.com/ZaparooProject/go-pn532/pn532sim"
+	"github.com/ZaparooProject/go-pn532/polling"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatch(t *testing.T) {
+	t.Parallel()
+
+	tag := pn532sim.NewNTAG213(testUID7)
+	sim, err := pn532sim.New(pn532sim.WithTags(tag))
+	require.NoError(t, err)
+	env, _ := newSimEnv(t, sim)
+	require.NoError(t, runTestCommand(env, "format"))
+	require.NoError(t, runTestCommand(env, "write", "-text", "hello"))
+
+	reader, writer := io.Pipe()
+	env.out.out = writer
+	env.readerPath = "/dev/ttyUSB0"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- env.watch(ctx, &watchOptions{readNDEF: true})
+		_ = writer.Close()
+	}()
+
+	lines := bufio.NewScanner(reader)
+	next := func() *eventView {
+		t.Helper()
+		require.True(t, lines.Scan(), "event stream ended")
+		var event eventView
+		require.NoError(t, json.Unmarshal(lines.Bytes(), &event), lines.Text())
+		return &event
+	}
+
+	detected := next()
+	assert.Equal(t, "detected", detected.Event)
+	assert.Equal(t, "04a1a2a3a4a5a6", detected.UID)
+	assert.Equal(t, string(pn532.TagTypeNTAG), detected.TagType)
+	assert.Equal(t, "/dev/ttyUSB0", detected.Reader)
+	assert.False(t, detected.Time.IsZero())
+
+	read := next()
+	assert.Equal(t, "read", read.Event)
+	assert.Empty(t, read.Error)
+	require.Len(t, read.Records, 1)
+	assert.Equal(t, "hello", read.Records[0].Text)
+
+	sim.RemoveTag(tag)
+	removed := next()
+	assert.Equal(t, "removed", removed.Event)
+	assert.Equal(t, "04a1a2a3a4a5a6", removed.UID)
+
+	cancel()
+	// Drain events racing the cancellation
+	_, _ = io.Copy(io.Discard, reader)
+	require.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestNewEventView(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		want  *eventView
+		name  string
+		event polling.Event
+	}{
+		{
+			name:  "error",
+			event: polling.Event{Type: polling.EventError, Err: errors.New("timeout"), Reader: "usb-1"},
+			want:  &eventView{Event: "error", Error: "timeout", ReaderID: "usb-1", Reader: "/dev/pn532"},
+		},
+		{
+			name:  "health",
+			event: polling.Event{Type: polling.EventHealth, Health: polling.HealthDegraded},
+			want:  &eventView{Event: "health", Health: polling.HealthDegraded.String(), Reader: "/dev/pn532"},
+		},
+		{
+			name: "changed",
+			event: polling.Event{
+				Type: polling.EventChanged, UID: "0102",
+				Tag: &pn532.DetectedTag{UID: "0102", Type: pn532.TagTypeMIFARE},
+			},
+			want: &eventView{Event: "changed", UID: "0102", TagType: "MIFARE", Reader: "/dev/pn532"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, newEventView(&tt.event, "/dev/pn532"))
+		})
+	}
+}
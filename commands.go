@@ -26,6 +26,7 @@ const (
 	cmdSamConfiguration    = 0x14
 	cmdGetFirmwareVersion  = 0x02
 	cmdGetGeneralStatus    = 0x04
+	cmdSetSerialBaudRate   = 0x10
 	cmdInListPassiveTarget = 0x4A
 	cmdInDataExchange      = 0x40
 	cmdInRelease           = 0x52
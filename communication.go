@@ -51,3 +51,14 @@ func (d *Device) SendRawCommand(data []byte) ([]byte, error) {
 func (d *Device) PowerDown(wakeupEnable, irqEnable byte) error {
 	return d.PowerDownContext(context.Background(), wakeupEnable, irqEnable)
 }
+
+// SetRFField switches the RF field on or off. Switching it off releases all
+// targets, tags in the field start over once it is switched on again.
+func (d *Device) SetRFField(on bool) error {
+	return d.SetRFFieldContext(context.Background(), on)
+}
+
+// ResetRFField switches the RF field off and on again
+func (d *Device) ResetRFField() error {
+	return d.ResetRFFieldContext(context.Background())
+}
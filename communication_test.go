@@ -442,6 +442,16 @@ func getPowerDownSuccessCases() []struct {
 			expectError:  false,
 			description:  "GPIO wake-up sources enabled",
 		},
+		{
+			name: "Successful_PowerDown_With_Status",
+			setupMock: func(mock *MockTransport) {
+				mock.SetResponse(cmdPowerDown, []byte{0x17, 0x00})
+			},
+			wakeupEnable: 0x01,
+			irqEnable:    0x00,
+			expectError:  false,
+			description:  "Response with status byte as sent by the PN532",
+		},
 		{
 			name: "PowerDown_No_Wake_Sources",
 			setupMock: func(mock *MockTransport) {
@@ -508,6 +518,17 @@ func getPowerDownErrorCases() []struct {
 			errorSubstring: "unexpected PowerDown response",
 			description:    "Empty response",
 		},
+		{
+			name: "PowerDown_Error_Status",
+			setupMock: func(mock *MockTransport) {
+				mock.SetResponse(cmdPowerDown, []byte{0x17, 0x01})
+			},
+			wakeupEnable:   0x01,
+			irqEnable:      0x01,
+			expectError:    true,
+			errorSubstring: "PowerDown error: 0x01",
+			description:    "Status byte reports an error",
+		},
 		{
 			name: "Long_PowerDown_Response",
 			setupMock: func(mock *MockTransport) {
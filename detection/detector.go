@@ -61,6 +61,8 @@ type DeviceInfo struct {
 	Path string
 	// Human-readable device name
 	Name string
+	// Identifier that survives reboots and re-enumeration, see StableID
+	StableID string
 	// Detection confidence level
 	Confidence Confidence
 }
@@ -238,6 +240,7 @@ func collectDetectionResults(
 func processDetectionResults(allDevices []DeviceInfo, errs []error) ([]DeviceInfo, error) {
 	// Return devices even if some detectors failed
 	if len(allDevices) > 0 {
+		AssignStableIDs(allDevices)
 		return allDevices, nil
 	}
 
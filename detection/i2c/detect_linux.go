@@ -174,8 +174,8 @@ func createDeviceInfo(ctx context.Context, busPath string, addr uint8, opts *det
 		Path:      devicePath,
 		Name:      fmt.Sprintf("I2C device at %s address 0x%02X", busPath, addr),
 		Metadata: map[string]string{
-			"bus":     busPath,
-			"address": fmt.Sprintf("0x%02X", addr),
+			detection.MetadataBus:     busPath,
+			detection.MetadataAddress: fmt.Sprintf("0x%02X", addr),
 		},
 	}
 
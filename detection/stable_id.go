@@ -0,0 +1,114 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package detection
+
+import "strings"
+
+// Metadata keys detectors use for the values stable IDs are derived from
+const (
+	// MetadataSerial is the USB serial number of the adapter
+	MetadataSerial = "serial"
+	// MetadataVIDPID is the USB vendor and product ID as "VID:PID"
+	MetadataVIDPID = "vidpid"
+	// MetadataUSBPort is the physical USB port path, e.g. "1-1.2" from sysfs
+	MetadataUSBPort = "usb_port"
+	// MetadataBus is the I2C bus device the reader is attached to
+	MetadataBus = "bus"
+	// MetadataAddress is the I2C address of the reader
+	MetadataAddress = "address"
+)
+
+// StableID derives an identifier for a reader that, unlike Path, does not
+// change when devices are enumerated in a different order. In order of
+// preference it is built from the USB serial number, the physical USB port
+// the reader is plugged into or the I2C bus and address. Readers without any
+// of these fall back to their transport and path.
+//
+// A serial number follows the reader to another port, a port path pins a
+// position on the host regardless of which reader is plugged in.
+func StableID(info DeviceInfo) string {
+	if serial := info.Metadata[MetadataSerial]; serial != "" {
+		return stableIDFromParts(info.Transport, "usb-serial", info.Metadata[MetadataVIDPID], serial)
+	}
+	return stableIDWithoutSerial(info)
+}
+
+// stableIDWithoutSerial derives the ID from where the reader is attached
+func stableIDWithoutSerial(info DeviceInfo) string {
+	if port := info.Metadata[MetadataUSBPort]; port != "" {
+		return stableIDFromParts(info.Transport, "usb-port", port)
+	}
+	bus, address := info.Metadata[MetadataBus], info.Metadata[MetadataAddress]
+	if bus != "" && address != "" {
+		return stableIDFromParts(info.Transport, bus, address)
+	}
+	return stableIDFromParts(info.Transport, info.Path)
+}
+
+// stableIDFromParts joins the non-empty parts of an ID
+func stableIDFromParts(parts ...string) string {
+	kept := parts[:0:0]
+	for _, part := range parts {
+		if part != "" {
+			kept = append(kept, part)
+		}
+	}
+	return strings.Join(kept, ":")
+}
+
+// AssignStableIDs fills in DeviceInfo.StableID where a detector left it
+// empty. Cheap adapters sometimes share a serial number, readers that would
+// end up with the same ID are told apart by where they are attached.
+func AssignStableIDs(devices []DeviceInfo) {
+	seen := make(map[string]int, len(devices))
+	for i := range devices {
+		if devices[i].StableID == "" {
+			devices[i].StableID = StableID(devices[i])
+		}
+		seen[devices[i].StableID]++
+	}
+
+	for i := range devices {
+		if seen[devices[i].StableID] > 1 && devices[i].Metadata[MetadataSerial] != "" {
+			devices[i].StableID = stableIDWithoutSerial(devices[i])
+		}
+	}
+}
+
+// FindByStableID returns the device with the given stable ID
+func FindByStableID(devices []DeviceInfo, id string) (DeviceInfo, bool) {
+	for _, device := range devices {
+		if device.StableID == id {
+			return device, true
+		}
+	}
+	return DeviceInfo{}, false
+}
+
+// FindByPath returns the device detected at the given path
+func FindByPath(devices []DeviceInfo, path string) (DeviceInfo, bool) {
+	for _, device := range devices {
+		if device.Path == path {
+			return device, true
+		}
+	}
+	return DeviceInfo{}, false
+}
@@ -0,0 +1,104 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package detection
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStableID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		want string
+		info DeviceInfo
+	}{
+		{
+			name: "USB serial number",
+			info: DeviceInfo{Transport: "uart", Path: "/dev/ttyUSB3", Metadata: map[string]string{
+				MetadataVIDPID: "0403:6001", MetadataSerial: "A50285BI", MetadataUSBPort: "1-1.2",
+			}},
+			want: "uart:usb-serial:0403:6001:A50285BI",
+		},
+		{
+			name: "USB port path without serial",
+			info: DeviceInfo{Transport: "uart", Path: "/dev/ttyUSB0", Metadata: map[string]string{
+				MetadataVIDPID: "1A86:7523", MetadataUSBPort: "1-1.4",
+			}},
+			want: "uart:usb-port:1-1.4",
+		},
+		{
+			name: "I2C bus and address",
+			info: DeviceInfo{Transport: "i2c", Path: "/dev/i2c-1:0x24", Metadata: map[string]string{
+				MetadataBus: "/dev/i2c-1", MetadataAddress: "0x24",
+			}},
+			want: "i2c:/dev/i2c-1:0x24",
+		},
+		{
+			name: "path fallback",
+			info: DeviceInfo{Transport: "spi", Path: "/dev/spidev0.0"},
+			want: "spi:/dev/spidev0.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, StableID(tt.info))
+		})
+	}
+}
+
+func TestAssignStableIDs_DuplicateSerials(t *testing.T) {
+	t.Parallel()
+
+	clone := func(path, port string) DeviceInfo {
+		return DeviceInfo{Transport: "uart", Path: path, Metadata: map[string]string{
+			MetadataVIDPID: "10C4:EA60", MetadataSerial: "0001", MetadataUSBPort: port,
+		}}
+	}
+	devices := []DeviceInfo{
+		clone("/dev/ttyUSB0", "1-1.1"),
+		clone("/dev/ttyUSB1", "1-1.2"),
+		{Transport: "uart", Path: "/dev/ttyS0", StableID: "custom"},
+	}
+
+	AssignStableIDs(devices)
+
+	assert.Equal(t, "uart:usb-port:1-1.1", devices[0].StableID)
+	assert.Equal(t, "uart:usb-port:1-1.2", devices[1].StableID)
+	assert.Equal(t, "custom", devices[2].StableID, "detector provided IDs are kept")
+
+	found, ok := FindByStableID(devices, "uart:usb-port:1-1.2")
+	assert.True(t, ok)
+	assert.Equal(t, "/dev/ttyUSB1", found.Path)
+	_, ok = FindByStableID(devices, "uart:usb-port:9-9")
+	assert.False(t, ok)
+
+	found, ok = FindByPath(devices, "/dev/ttyUSB1")
+	assert.True(t, ok)
+	assert.Equal(t, "uart:usb-port:1-1.2", found.StableID)
+	_, ok = FindByPath(devices, "/dev/ttyUSB9")
+	assert.False(t, ok)
+}
@@ -240,7 +240,7 @@ func (d *detector) createDeviceInfo(port *serialPort, confidence detection.Confi
 // addPortMetadata adds available port metadata to the device
 func (*detector) addPortMetadata(device *detection.DeviceInfo, port *serialPort) {
 	if port.VIDPID != "" {
-		device.Metadata["vidpid"] = port.VIDPID
+		device.Metadata[detection.MetadataVIDPID] = port.VIDPID
 	}
 	if port.Manufacturer != "" {
 		device.Metadata["manufacturer"] = port.Manufacturer
@@ -249,7 +249,10 @@ func (*detector) addPortMetadata(device *detection.DeviceInfo, port *serialPort)
 		device.Metadata["product"] = port.Product
 	}
 	if port.SerialNumber != "" {
-		device.Metadata["serial"] = port.SerialNumber
+		device.Metadata[detection.MetadataSerial] = port.SerialNumber
+	}
+	if port.USBPort != "" {
+		device.Metadata[detection.MetadataUSBPort] = port.USBPort
 	}
 }
 
@@ -269,6 +272,8 @@ type serialPort struct {
 	Manufacturer string
 	Product      string
 	SerialNumber string
+	// USBPort is the physical USB port path, e.g. "1-1.2" (Linux only)
+	USBPort string
 }
 
 // isLikelyPN532 checks if a serial port is likely to be a PN532 device
@@ -92,6 +92,8 @@ func readUSBAttributes(port *serialPort, devicePath string) {
 	current := devicePath
 	for i := 0; i < 10; i++ { // Limit iterations to prevent infinite loops
 		if readUSBIdentifiers(port, current) {
+			// The USB device directory is named after its port path
+			port.USBPort = filepath.Base(current)
 			break
 		}
 
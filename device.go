@@ -65,9 +65,20 @@ type Device struct {
 	transport       Transport
 	config          *DeviceConfig
 	firmwareVersion *FirmwareVersion
+	stableID        string
 	currentTarget   byte
 }
 
+// StableID returns the stable reader ID (see detection.StableID) of a device
+// found by auto-detection in ConnectDevice, or an empty string if it is not
+// known. Readers connected by path have no hardware identity to derive one
+// from, their path may change when devices are enumerated again. Callers
+// that know the path look it up with detection.FindByPath and connect with
+// WithStableID instead.
+func (d *Device) StableID() string {
+	return d.stableID
+}
+
 // setCurrentTarget sets the active target number for data exchange operations
 func (d *Device) setCurrentTarget(targetNumber byte) {
 	d.currentTarget = targetNumber
@@ -157,6 +168,7 @@ type connectConfig struct {
 	transportFactory       TransportFactory
 	transportDeviceFactory TransportFromDeviceFactory
 	deviceDetector         func(*detection.Options) ([]detection.DeviceInfo, error)
+	stableID               string
 	deviceOptions          []Option
 	timeout                time.Duration
 	autoDetect             bool
@@ -203,6 +215,20 @@ func WithTransportFromDeviceFactory(factory TransportFromDeviceFactory) ConnectO
 	}
 }
 
+// WithStableID connects to the detected reader with the given stable ID
+// (see detection.StableID) instead of the first one found. It implies
+// auto-detection and needs WithTransportFromDeviceFactory.
+func WithStableID(id string) ConnectOption {
+	return func(c *connectConfig) error {
+		if id == "" {
+			return errors.New("stable ID must not be empty")
+		}
+		c.stableID = id
+		c.autoDetect = true
+		return nil
+	}
+}
+
 // WithConnectionRetries sets the number of connection retry attempts
 func WithConnectionRetries(maxAttempts int) ConnectOption {
 	return func(c *connectConfig) error {
@@ -253,11 +279,26 @@ func applyConnectOptions(opts []ConnectOption) (*connectConfig, error) {
 	return config, nil
 }
 
-func createTransport(path string, config *connectConfig) (Transport, error) {
+// createTransport opens the transport for a path or a detected device and
+// returns the stable ID of a detected reader, empty for a path
+func createTransport(path string, config *connectConfig) (Transport, string, error) {
 	if config.autoDetect || path == "" {
-		return createAutoDetectedTransport(config.transportDeviceFactory, config.deviceDetector)
+		info, err := detectDevice(config.deviceDetector, config.stableID)
+		if err != nil {
+			return nil, "", err
+		}
+		if config.transportDeviceFactory == nil {
+			return nil, "", errors.New("transport device factory not provided")
+		}
+		transport, err := config.transportDeviceFactory(info)
+		return transport, info.StableID, err
+	}
+
+	transport, err := createManualTransport(path, config.transportFactory)
+	if err != nil {
+		return nil, "", err
 	}
-	return createManualTransport(path, config.transportFactory)
+	return transport, "", nil
 }
 
 func setupDevice(transport Transport, config *connectConfig) (*Device, error) {
@@ -315,7 +356,7 @@ func ConnectDevice(path string, opts ...ConnectOption) (*Device, error) {
 		return nil, fmt.Errorf("failed to apply connect options: %w", err)
 	}
 
-	transport, err := createTransport(path, config)
+	transport, stableID, err := createTransport(path, config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create transport: %w", err)
 	}
@@ -325,6 +366,7 @@ func ConnectDevice(path string, opts ...ConnectOption) (*Device, error) {
 		_ = transport.Close()
 		return nil, err
 	}
+	device.stableID = stableID
 
 	return device, nil
 }
@@ -343,11 +385,12 @@ func createManualTransport(path string, factory TransportFactory) (Transport, er
 	return transport, nil
 }
 
-// createAutoDetectedTransport handles auto-detection of devices
-func createAutoDetectedTransport(
-	factory TransportFromDeviceFactory,
+// detectDevice runs auto-detection and picks the reader with the given
+// stable ID, or the first reader found if stableID is empty
+func detectDevice(
 	detector func(*detection.Options) ([]detection.DeviceInfo, error),
-) (Transport, error) {
+	stableID string,
+) (detection.DeviceInfo, error) {
 	opts := detection.DefaultOptions()
 	opts.Mode = detection.Safe
 
@@ -361,19 +404,25 @@ func createAutoDetectedTransport(
 	}
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to detect devices: %w", err)
+		return detection.DeviceInfo{}, fmt.Errorf("failed to detect devices: %w", err)
 	}
 
 	if len(devices) == 0 {
-		return nil, errors.New("no PN532 devices found")
+		return detection.DeviceInfo{}, errors.New("no PN532 devices found")
 	}
 
-	// Use the first detected device
-	device := devices[0]
-	if factory == nil {
-		return nil, errors.New("transport device factory not provided")
+	// Custom detectors may not fill in the stable IDs
+	detection.AssignStableIDs(devices)
+	if stableID == "" {
+		// Use the first detected device
+		return devices[0], nil
+	}
+
+	device, ok := detection.FindByStableID(devices, stableID)
+	if !ok {
+		return detection.DeviceInfo{}, fmt.Errorf("%w with stable ID %q", detection.ErrNoDevicesFound, stableID)
 	}
-	return factory(device)
+	return device, nil
 }
 
 // Transport returns the underlying transport
@@ -398,11 +447,52 @@ func (d *Device) SetTimeout(timeout time.Duration) error {
 // SetRetryConfig updates the retry configuration
 func (d *Device) SetRetryConfig(config *RetryConfig) {
 	d.config.RetryConfig = config
-	if tr, ok := d.transport.(*TransportWithRetry); ok {
+	if tr := d.retryTransport(); tr != nil {
 		tr.SetRetryConfig(config)
 	}
 }
 
+// retryTransport returns the retry layer of the transport, looking through
+// other transport wrappers, or nil if there is none
+func (d *Device) retryTransport() *TransportWithRetry {
+	for transport := d.transport; transport != nil; transport = unwrapTransport(transport) {
+		if tr, ok := transport.(*TransportWithRetry); ok {
+			return tr
+		}
+	}
+	return nil
+}
+
+// ReopenTransport closes and reopens the transport connection, looking
+// through transport wrappers for a Reopener. The device must be initialized
+// again afterwards.
+func (d *Device) ReopenTransport() error {
+	for transport := d.transport; transport != nil; transport = unwrapTransport(transport) {
+		if reopener, ok := transport.(Reopener); ok {
+			if err := reopener.Reopen(); err != nil {
+				return fmt.Errorf("failed to reopen transport: %w", err)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s transport cannot be reopened", ErrDeviceNotSupported, d.transport.Type())
+}
+
+// HardReset resets the PN532 through the transport, looking through
+// transport wrappers for a HardResetter. The device must be initialized
+// again afterwards.
+func (d *Device) HardReset() error {
+	for transport := d.transport; transport != nil; transport = unwrapTransport(transport) {
+		if resetter, ok := transport.(HardResetter); ok {
+			if err := resetter.HardReset(); err != nil {
+				return fmt.Errorf("failed to hard reset PN532: %w", err)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s transport cannot hard reset", ErrDeviceNotSupported, d.transport.Type())
+}
+
 // IsAutoPollSupported returns true if the transport supports native InAutoPoll
 func (d *Device) IsAutoPollSupported() bool {
 	return d.hasCapability(CapabilityAutoPollNative)
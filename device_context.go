@@ -344,6 +344,27 @@ func (d *Device) DetectTagContext(ctx context.Context) (*DetectedTag, error) {
 	return tags[0], nil
 }
 
+// SelectTagByUIDContext lists the 106 kbps Type A tag with the given UID on
+// its own, which makes it target 1 for the following commands even when
+// another tag in the field wins anticollision
+func (d *Device) SelectTagByUIDContext(ctx context.Context, uid []byte) (*DetectedTag, error) {
+	res, err := d.executeInListPassiveTarget(ctx, append([]byte{0x01, 0x00}, uid...))
+	if err != nil {
+		return nil, fmt.Errorf("InListPassiveTarget command failed: %w", err)
+	}
+	if err = d.validateInListPassiveTargetResponse(res); err != nil {
+		return nil, err
+	}
+	tags, err := d.parseInListPassiveTargetResponse(res)
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) == 0 || !bytes.Equal(tags[0].UIDBytes, uid) {
+		return nil, ErrNoTagDetected
+	}
+	return tags[0], nil
+}
+
 // DetectTagsContext detects multiple tags in the field with context support
 // Uses polling strategy system with InListPassiveTarget as the preferred default
 func (d *Device) DetectTagsContext(ctx context.Context, maxTags, baudRate byte) ([]*DetectedTag, error) {
@@ -477,7 +498,15 @@ func (d *Device) parseTargetData(targetType AutoPollTarget, targetData []byte) (
 	return uid, atq, sak
 }
 
-// parseISO14443AData parses ISO14443 Type A target data
+// parseISO14443AData parses ISO14443 Type A target data. InAutoPoll repeats
+// the InListPassiveTarget target data (PN532 user manual, InAutoPoll and
+// InListPassiveTarget at 106 kbps Type A):
+//
+//	Tg(1) + SENS_RES(2) + SEL_RES(1) + NFCIDLength(1) + NFCID1(n) + [ATS]
+//
+// where SENS_RES is the ATQ, SEL_RES the SAK and the ATS follows for ISO-DEP
+// targets. Target data without the Tg byte, ATQ(2) + SAK(1) + NFCIDLength(1)
+// + NFCID1(n), is accepted too if it has no trailing bytes.
 func (*Device) parseISO14443AData(targetData []byte) (uid, atq []byte, sak byte) {
 	uid = []byte{0x00, 0x00, 0x00, 0x00}
 	atq = []byte{0x00, 0x00}
@@ -487,31 +516,35 @@ func (*Device) parseISO14443AData(targetData []byte) (uid, atq []byte, sak byte)
 		return uid, atq, sak
 	}
 
-	// Parse ATQ and SAK from the first 3 bytes
-	atq = targetData[0:2] // SENS_RES (ATQ)
-	sak = targetData[2]   // SEL_RES (SAK)
-
-	// Try parsing UID length at offset 3 first (test/mock format)
-	// Format: ATQ(2) + SAK(1) + UID_LENGTH(1) + UID(n)
-	if len(targetData) > 3 {
-		uidLen := targetData[3]
-		if uidLen > 0 && len(targetData) >= 4+int(uidLen) {
-			uid = targetData[4 : 4+int(uidLen)]
-			return uid, atq, sak
+	// The Tg layout is checked first: its SAK sits where the other layout
+	// keeps the UID length, so a SAK equal to the remaining length would
+	// otherwise be read as one
+	if len(targetData) > 4 {
+		uidLen := int(targetData[4])
+		if isTargetNumber(targetData[0]) && isNFCID1Length(uidLen) && len(targetData) >= 5+uidLen {
+			return targetData[5 : 5+uidLen], targetData[1:3], targetData[3]
 		}
 	}
 
-	// Try parsing UID length at offset 4 (real hardware format)
-	// Format: ATQ(2) + SAK(1) + UNKNOWN(1) + UID_LENGTH(1) + UID(n)
-	if len(targetData) > 4 {
-		uidLen := targetData[4]
-		if uidLen > 0 && len(targetData) >= 5+int(uidLen) {
-			uid = targetData[5 : 5+int(uidLen)]
-			return uid, atq, sak
-		}
+	uidLen := int(targetData[3])
+	if uidLen > 0 && len(targetData) == 4+uidLen {
+		return targetData[4:], targetData[0:2], targetData[2]
 	}
 
-	return uid, atq, sak
+	// Unknown layout, keep the ATQ and SAK of the Tg-less layout
+	return uid, targetData[0:2], targetData[2]
+}
+
+// isTargetNumber reports whether b is a logical target number the PN532
+// assigns, it handles at most two targets
+func isTargetNumber(b byte) bool {
+	return b == 0x01 || b == 0x02
+}
+
+// isNFCID1Length reports whether n is a single, double or triple size
+// ISO14443-3 UID length
+func isNFCID1Length(n int) bool {
+	return n == 4 || n == 7 || n == 10
 }
 
 // parseJewelData parses Jewel target data
@@ -524,8 +557,9 @@ func (*Device) parseJewelData(targetData []byte) []byte {
 
 // parseFeliCaData parses FeliCa target data
 func (*Device) parseFeliCaData(targetData []byte) []byte {
-	if len(targetData) >= 18 {
-		return targetData[2:10] // NFCID2 (8 bytes)
+	polRes := feliCaPollingResponse(targetData)
+	if len(polRes) >= 1+feliCaIDmLength {
+		return polRes[1 : 1+feliCaIDmLength] // NFCID2 (IDm, 8 bytes)
 	}
 	return []byte{0x00, 0x00, 0x00, 0x00}
 }
@@ -903,6 +937,19 @@ func (*Device) parseInListTargetData(res []byte, offset, targetIndex int) (*targ
 	}, nil
 }
 
+// DetectTagsAutoPollContext polls for the given target types with InAutoPoll
+// and returns up to maxTags found targets. Unlike InAutoPollContext the
+// results are parsed into DetectedTags that can be passed to CreateTag.
+func (d *Device) DetectTagsAutoPollContext(
+	ctx context.Context, maxTags, pollCount, pollPeriod byte, targetTypes []AutoPollTarget,
+) ([]*DetectedTag, error) {
+	results, err := d.InAutoPollContext(ctx, pollCount, pollPeriod, targetTypes)
+	if err != nil {
+		return nil, err
+	}
+	return d.convertAutoPollResults(ctx, results, d.normalizeMaxTargets(maxTags))
+}
+
 // fallbackToInAutoPoll provides a fallback detection method for clone devices
 // that don't support InListPassiveTarget command properly
 func (d *Device) fallbackToInAutoPoll(ctx context.Context, maxTg, brTy byte) ([]*DetectedTag, error) {
@@ -958,10 +1005,162 @@ func (d *Device) PowerDownContext(ctx context.Context, wakeupEnable, irqEnable b
 		return fmt.Errorf("PowerDown command failed: %w", err)
 	}
 
-	// PowerDown response should be 0x17
-	if len(res) != 1 || res[0] != 0x17 {
+	// PowerDown response is 0x17 followed by a status byte, which some
+	// firmware and transports leave out
+	if len(res) == 0 || len(res) > 2 || res[0] != 0x17 {
 		return fmt.Errorf("unexpected PowerDown response: %v", res)
 	}
+	if len(res) == 2 && res[1] != 0x00 {
+		return fmt.Errorf("PowerDown error: 0x%02X", res[1])
+	}
+
+	return nil
+}
+
+// rfFieldOn is the RFConfiguration RF field item value switching the field on
+const rfFieldOn = 0x02
+
+// rfResetDelay is how long the RF field stays off during ResetRFFieldContext,
+// long enough for tags in the field to lose power
+const rfResetDelay = 10 * time.Millisecond
+
+// SetRFFieldContext switches the RF field on or off with context support
+// Based on PN532 manual section 7.3.1, configuration item 0x01
+func (d *Device) SetRFFieldContext(ctx context.Context, on bool) error {
+	field := byte(0x00)
+	if on {
+		field = rfFieldOn
+	}
 
+	res, err := d.transport.SendCommandWithContext(ctx, cmdRFConfiguration, []byte{0x01, field})
+	if err != nil {
+		return fmt.Errorf("RFConfiguration command failed: %w", err)
+	}
+	if len(res) == 0 || res[0] != cmdRFConfiguration+1 {
+		return fmt.Errorf("unexpected RFConfiguration response: %v", res)
+	}
 	return nil
 }
+
+// ResetRFFieldContext switches the RF field off and on again with context
+// support, which resets the tags in the field
+func (d *Device) ResetRFFieldContext(ctx context.Context) error {
+	if err := d.SetRFFieldContext(ctx, false); err != nil {
+		return err
+	}
+
+	select {
+	case <-time.After(rfResetDelay):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return d.SetRFFieldContext(ctx, true)
+}
+
+// SetSerialBaudRateContext negotiates a new HSU baud rate with context support.
+// The PN532 acknowledges the command at the current rate and only switches once
+// the host has ACKed the response, after which the host port is switched and the
+// link is verified with GetFirmwareVersion, retrying a few times at the new rate.
+// If the PN532 never answers at the new rate the host port is reverted to the
+// previous rate, which only recovers the link if the PN532 ignored the switch.
+// When the PN532 does not answer at either rate the returned error wraps
+// ErrBaudRateMismatch: the PN532 may already be at baudRate, so the caller
+// should reopen the port at that rate (or reset the PN532 back to 115200).
+func (d *Device) SetSerialBaudRateContext(ctx context.Context, baudRate int) error {
+	code, ok := serialBaudRateCodes[baudRate]
+	if !ok {
+		return fmt.Errorf("%w: unsupported serial baud rate %d", ErrInvalidParameter, baudRate)
+	}
+
+	configurer := d.baudRateConfigurer()
+	if configurer == nil {
+		return fmt.Errorf("%w: %s transport cannot change baud rate", ErrDeviceNotSupported, d.transport.Type())
+	}
+
+	previous := configurer.BaudRate()
+	if previous == baudRate {
+		return nil
+	}
+
+	res, err := d.transport.SendCommandWithContext(ctx, cmdSetSerialBaudRate, []byte{code})
+	if err != nil {
+		return fmt.Errorf("SetSerialBaudRate command failed: %w", err)
+	}
+	if len(res) < 1 || res[0] != cmdSetSerialBaudRate+1 {
+		return fmt.Errorf("unexpected SetSerialBaudRate response: %v", res)
+	}
+
+	// Give the PN532 time to reconfigure its HSU after receiving our ACK
+	select {
+	case <-time.After(baudRateSwitchDelay):
+	case <-ctx.Done():
+		return fmt.Errorf("context cancelled while waiting for baud rate switch: %w", ctx.Err())
+	}
+
+	if err := configurer.SetBaudRate(baudRate); err != nil {
+		return fmt.Errorf("failed to switch host baud rate to %d: %w", baudRate, err)
+	}
+
+	if err := d.verifyBaudRate(ctx, baudRate); err != nil {
+		return d.revertBaudRate(ctx, configurer, baudRate, previous, err)
+	}
+
+	debugf("Serial baud rate switched from %d to %d", previous, baudRate)
+	return nil
+}
+
+// verifyBaudRate checks the link at the current host baud rate, retrying so a
+// PN532 that is slow to come up at the new rate is not mistaken for a failure
+func (d *Device) verifyBaudRate(ctx context.Context, baudRate int) error {
+	var err error
+	for attempt := 1; attempt <= baudRateVerifyAttempts; attempt++ {
+		if _, err = d.GetFirmwareVersionContext(ctx); err == nil {
+			return nil
+		}
+		debugf("Baud rate %d check %d/%d failed: %v", baudRate, attempt, baudRateVerifyAttempts, err)
+		if attempt == baudRateVerifyAttempts {
+			break
+		}
+
+		select {
+		case <-time.After(baudRateSwitchDelay):
+		case <-ctx.Done():
+			return fmt.Errorf("context cancelled while verifying baud rate: %w", ctx.Err())
+		}
+	}
+	return err
+}
+
+// baudRateConfigurer returns the transport's baud rate configurer, looking
+// through transport wrappers, or nil if the transport does not support it
+func (d *Device) baudRateConfigurer() BaudRateConfigurer {
+	for transport := d.transport; transport != nil; transport = unwrapTransport(transport) {
+		if configurer, ok := transport.(BaudRateConfigurer); ok {
+			return configurer
+		}
+	}
+	return nil
+}
+
+// revertBaudRate restores the previous host baud rate after a failed switch.
+// This only helps if the PN532 never switched; if it does not answer at the
+// previous rate either, the PN532 may be at the attempted rate and the error
+// wraps ErrBaudRateMismatch so the caller can reopen the port at that rate.
+func (d *Device) revertBaudRate(
+	ctx context.Context, configurer BaudRateConfigurer, attempted, previous int, cause error,
+) error {
+	debugf("Baud rate %d not confirmed (%v), reverting to %d", attempted, cause, previous)
+
+	if err := configurer.SetBaudRate(previous); err != nil {
+		return fmt.Errorf("%w: baud rate %d not confirmed (%w) and revert to %d failed, "+
+			"PN532 may be at %d: %w", ErrBaudRateMismatch, attempted, cause, previous, attempted, err)
+	}
+
+	if _, err := d.GetFirmwareVersionContext(ctx); err != nil {
+		return fmt.Errorf("%w: PN532 unresponsive at %d and %d, it may be at %d; "+
+			"reopen the port at that rate: %w", ErrBaudRateMismatch, attempted, previous, attempted, err)
+	}
+
+	return fmt.Errorf("baud rate %d not confirmed, reverted to %d: %w", attempted, previous, cause)
+}
@@ -89,3 +89,157 @@ func TestDiagnoseContextCancellation(t *testing.T) {
 	assert.ErrorIs(t, err, context.DeadlineExceeded,
 		"Expected context.DeadlineExceeded, got: %v", err)
 }
+
+func TestDetectTagsAutoPollContext(t *testing.T) {
+	t.Parallel()
+
+	ntag := []byte{0x00, 0x0C, 0x01, 0x00, 0x44, 0x00, 0x07, 0x04, 0x12, 0x34, 0x56, 0x78, 0x9A, 0xBC}
+	classic := []byte{0x10, 0x09, 0x01, 0x00, 0x04, 0x08, 0x04, 0xDE, 0xAD, 0xBE, 0xEF}
+
+	tests := []struct {
+		name      string
+		wantUIDs  []string
+		wantTypes []TagType
+		response  []byte
+		maxTags   byte
+	}{
+		{
+			name:      "NTAG",
+			response:  append([]byte{0x61, 0x01}, ntag...),
+			maxTags:   1,
+			wantUIDs:  []string{"04123456789abc"},
+			wantTypes: []TagType{TagTypeNTAG},
+		},
+		{
+			name:      "Two_Targets",
+			response:  append(append([]byte{0x61, 0x02}, ntag...), classic...),
+			maxTags:   2,
+			wantUIDs:  []string{"04123456789abc", "deadbeef"},
+			wantTypes: []TagType{TagTypeNTAG, TagTypeMIFARE},
+		},
+		{
+			name:      "Limited_To_MaxTags",
+			response:  append(append([]byte{0x61, 0x02}, ntag...), classic...),
+			maxTags:   1,
+			wantUIDs:  []string{"04123456789abc"},
+			wantTypes: []TagType{TagTypeNTAG},
+		},
+		{
+			name:     "No_Targets",
+			response: []byte{0x61, 0x00},
+			maxTags:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := NewMockTransport()
+			mock.SetResponse(cmdInAutoPoll, tt.response)
+			device, err := New(mock)
+			require.NoError(t, err)
+
+			tags, err := device.DetectTagsAutoPollContext(context.Background(), tt.maxTags, 1, 1,
+				[]AutoPollTarget{AutoPollGeneric106kbps, AutoPollMifare})
+			require.NoError(t, err)
+			require.Len(t, tags, len(tt.wantUIDs))
+			for i, tag := range tags {
+				assert.Equal(t, tt.wantUIDs[i], tag.UID)
+				assert.Equal(t, tt.wantTypes[i], tag.Type)
+				assert.True(t, tag.FromInAutoPoll)
+			}
+		})
+	}
+}
+
+func TestParseISO14443AData(t *testing.T) {
+	t.Parallel()
+
+	uid4 := []byte{0xDE, 0xAD, 0xBE, 0xEF}
+	uid7 := []byte{0x04, 0x12, 0x34, 0x56, 0x78, 0x9A, 0xBC}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantUID []byte
+		wantATQ []byte
+		wantSAK byte
+	}{
+		{
+			name:    "Tg_Layout",
+			data:    append([]byte{0x01, 0x00, 0x44, 0x00, 0x07}, uid7...),
+			wantUID: uid7, wantATQ: []byte{0x00, 0x44}, wantSAK: 0x00,
+		},
+		{
+			name:    "Tg_Layout_Second_Target",
+			data:    append([]byte{0x02, 0x00, 0x04, 0x08, 0x04}, uid4...),
+			wantUID: uid4, wantATQ: []byte{0x00, 0x04}, wantSAK: 0x08,
+		},
+		{
+			name: "Tg_Layout_With_ATS",
+			data: append(append([]byte{0x01, 0x03, 0x44, 0x20, 0x07}, uid7...),
+				0x06, 0x75, 0x77, 0x81, 0x02, 0x80),
+			wantUID: uid7, wantATQ: []byte{0x03, 0x44}, wantSAK: 0x20,
+		},
+		{
+			name:    "Truncated_UID",
+			data:    append([]byte{0x01, 0x00, 0x04, 0x08, 0x04}, uid4[:2]...),
+			wantUID: []byte{0x00, 0x00, 0x00, 0x00}, wantATQ: []byte{0x01, 0x00}, wantSAK: 0x04,
+		},
+		{
+			// SAK 0x08 looks like an 8 byte UID in the Tg-less layout, the
+			// 3 ATS bytes make the total length match it
+			name:    "Tg_Layout_SAK_Collides_With_Length",
+			data:    append(append([]byte{0x01, 0x00, 0x04, 0x08, 0x04}, uid4...), 0x02, 0x78, 0x00),
+			wantUID: uid4, wantATQ: []byte{0x00, 0x04}, wantSAK: 0x08,
+		},
+		{
+			// The Tg-less layout would read SAK 0x07 as a 7 byte UID
+			name:    "Tg_Layout_SAK_Equals_UID_Length",
+			data:    append([]byte{0x01, 0x00, 0x44, 0x07, 0x07}, uid7...),
+			wantUID: uid7, wantATQ: []byte{0x00, 0x44}, wantSAK: 0x07,
+		},
+		{
+			name:    "Tgless_Layout",
+			data:    append([]byte{0x00, 0x44, 0x00, 0x07}, uid7...),
+			wantUID: uid7, wantATQ: []byte{0x00, 0x44}, wantSAK: 0x00,
+		},
+		{
+			name:    "Tgless_Layout_4_Byte_UID",
+			data:    append([]byte{0x00, 0x04, 0x08, 0x04}, uid4...),
+			wantUID: uid4, wantATQ: []byte{0x00, 0x04}, wantSAK: 0x08,
+		},
+		{
+			name:    "Too_Short",
+			data:    []byte{0x00, 0x44, 0x00},
+			wantUID: []byte{0x00, 0x00, 0x00, 0x00}, wantATQ: []byte{0x00, 0x00}, wantSAK: 0x00,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			uid, atq, sak := (&Device{}).parseISO14443AData(tt.data)
+			assert.Equal(t, tt.wantUID, uid)
+			assert.Equal(t, tt.wantATQ, atq)
+			assert.Equal(t, tt.wantSAK, sak)
+		})
+	}
+}
+
+func TestResetRFFieldContext(t *testing.T) {
+	t.Parallel()
+
+	mock := NewMockTransport()
+	mock.SetResponse(cmdRFConfiguration, []byte{cmdRFConfiguration + 1})
+	device, err := New(mock)
+	require.NoError(t, err)
+
+	require.NoError(t, device.ResetRFFieldContext(context.Background()))
+	assert.Equal(t, 2, mock.GetCallCount(cmdRFConfiguration))
+
+	mock.SetResponse(cmdRFConfiguration, []byte{0x7F})
+	require.ErrorContains(t, device.SetRFField(true), "unexpected RFConfiguration response")
+}
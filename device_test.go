@@ -254,6 +254,28 @@ func TestDevice_DetectTagsContext(t *testing.T) {
 	}
 }
 
+func TestDevice_SelectTagByUIDContext(t *testing.T) {
+	t.Parallel()
+
+	mock := NewMockTransport()
+	mock.SetResponse(testutil.CmdInListPassiveTarget,
+		testutil.BuildTagDetectionResponse("NTAG213", testutil.TestNTAG213UID))
+	device, err := New(mock)
+	require.NoError(t, err)
+
+	tag, err := device.SelectTagByUIDContext(context.Background(), testutil.TestNTAG213UID)
+	require.NoError(t, err)
+	assert.Equal(t, testutil.TestNTAG213UID, tag.UIDBytes)
+	assert.Equal(t, byte(1), tag.TargetNumber)
+
+	_, err = device.SelectTagByUIDContext(context.Background(), []byte{0x04, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06})
+	require.ErrorIs(t, err, ErrNoTagDetected)
+
+	mock.SetResponse(testutil.CmdInListPassiveTarget, testutil.BuildNoTagResponse())
+	_, err = device.SelectTagByUIDContext(context.Background(), testutil.TestNTAG213UID)
+	require.ErrorIs(t, err, ErrNoTagDetected)
+}
+
 func TestDevice_GetFirmwareVersionContext(t *testing.T) {
 	t.Parallel()
 
@@ -538,3 +560,152 @@ func TestConnectDevice_WithConnectionRetries(t *testing.T) {
 		assert.Equal(t, 1, samAttempts, "Auto-detection should only make single attempt")
 	})
 }
+
+func TestConnectDevice_WithStableID(t *testing.T) {
+	t.Parallel()
+
+	detector := func(_ *detection.Options) ([]detection.DeviceInfo, error) {
+		return []detection.DeviceInfo{
+			{Transport: "uart", Path: "/dev/ttyUSB0", Metadata: map[string]string{
+				detection.MetadataVIDPID: "0403:6001", detection.MetadataSerial: "LEFT",
+			}},
+			{Transport: "uart", Path: "/dev/ttyUSB1", Metadata: map[string]string{
+				detection.MetadataVIDPID: "0403:6001", detection.MetadataSerial: "RIGHT",
+			}},
+		}, nil
+	}
+
+	var openedPath string
+	deviceFactory := func(info detection.DeviceInfo) (Transport, error) {
+		openedPath = info.Path
+		mock := NewMockTransport()
+		mock.SetResponse(testutil.CmdGetFirmwareVersion, testutil.BuildFirmwareVersionResponse())
+		mock.SetResponse(testutil.CmdSAMConfiguration, testutil.BuildSAMConfigurationResponse())
+		return mock, nil
+	}
+
+	device, err := ConnectDevice("",
+		WithStableID("uart:usb-serial:0403:6001:RIGHT"),
+		WithTransportFromDeviceFactory(deviceFactory),
+		WithDeviceDetector(detector))
+	require.NoError(t, err)
+	assert.Equal(t, "/dev/ttyUSB1", openedPath)
+	assert.Equal(t, "uart:usb-serial:0403:6001:RIGHT", device.StableID())
+
+	_, err = ConnectDevice("",
+		WithStableID("uart:usb-serial:0403:6001:MIDDLE"),
+		WithTransportFromDeviceFactory(deviceFactory),
+		WithDeviceDetector(detector))
+	require.ErrorIs(t, err, detection.ErrNoDevicesFound)
+
+	_, err = ConnectDevice("", WithStableID(""))
+	require.Error(t, err)
+}
+
+func TestConnectDevice_StableIDSharedSerial(t *testing.T) {
+	t.Parallel()
+
+	// Cheap adapters with the same serial are told apart by their USB port
+	detector := func(_ *detection.Options) ([]detection.DeviceInfo, error) {
+		clone := func(path, port string) detection.DeviceInfo {
+			return detection.DeviceInfo{Transport: "uart", Path: path, Metadata: map[string]string{
+				detection.MetadataVIDPID: "10C4:EA60", detection.MetadataSerial: "0001", detection.MetadataUSBPort: port,
+			}}
+		}
+		return []detection.DeviceInfo{clone("/dev/ttyUSB0", "1-1.1"), clone("/dev/ttyUSB1", "1-1.2")}, nil
+	}
+
+	var openedPath string
+	deviceFactory := func(info detection.DeviceInfo) (Transport, error) {
+		openedPath = info.Path
+		mock := NewMockTransport()
+		mock.SetResponse(testutil.CmdGetFirmwareVersion, testutil.BuildFirmwareVersionResponse())
+		mock.SetResponse(testutil.CmdSAMConfiguration, testutil.BuildSAMConfigurationResponse())
+		return mock, nil
+	}
+
+	device, err := ConnectDevice("",
+		WithStableID("uart:usb-port:1-1.2"),
+		WithTransportFromDeviceFactory(deviceFactory),
+		WithDeviceDetector(detector))
+	require.NoError(t, err)
+	assert.Equal(t, "/dev/ttyUSB1", openedPath)
+	assert.Equal(t, "uart:usb-port:1-1.2", device.StableID())
+
+	device, err = ConnectDevice("",
+		WithAutoDetection(),
+		WithTransportFromDeviceFactory(deviceFactory),
+		WithDeviceDetector(detector))
+	require.NoError(t, err)
+	assert.Equal(t, "/dev/ttyUSB0", openedPath)
+	assert.Equal(t, "uart:usb-port:1-1.1", device.StableID())
+}
+
+func TestConnectDevice_ManualPathStableID(t *testing.T) {
+	t.Parallel()
+
+	factory := func(_ string) (Transport, error) {
+		mock := NewMockTransport()
+		mock.SetResponse(testutil.CmdGetFirmwareVersion, testutil.BuildFirmwareVersionResponse())
+		mock.SetResponse(testutil.CmdSAMConfiguration, testutil.BuildSAMConfigurationResponse())
+		return mock, nil
+	}
+
+	// A path carries no hardware identity
+	device, err := ConnectDevice("/dev/mock0", WithTransportFactory(factory))
+	require.NoError(t, err)
+	assert.Empty(t, device.StableID())
+}
+
+// resettableTransport is a MockTransport that can be reopened and hard reset
+type resettableTransport struct {
+	*MockTransport
+	err     error
+	reopens int
+	resets  int
+}
+
+func (r *resettableTransport) Reopen() error {
+	r.reopens++
+	return r.err
+}
+
+func (r *resettableTransport) HardReset() error {
+	r.resets++
+	return r.err
+}
+
+func TestDevice_ReopenTransportAndHardReset(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Through_Wrapper", func(t *testing.T) {
+		t.Parallel()
+		transport := &resettableTransport{MockTransport: NewMockTransport()}
+		device, err := New(NewTransportWithRetry(transport, DefaultRetryConfig()))
+		require.NoError(t, err)
+
+		require.NoError(t, device.ReopenTransport())
+		require.NoError(t, device.HardReset())
+		assert.Equal(t, 1, transport.reopens)
+		assert.Equal(t, 1, transport.resets)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Parallel()
+		transport := &resettableTransport{MockTransport: NewMockTransport(), err: errors.New("port gone")}
+		device, err := New(transport)
+		require.NoError(t, err)
+
+		require.ErrorContains(t, device.ReopenTransport(), "port gone")
+		require.ErrorContains(t, device.HardReset(), "port gone")
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		t.Parallel()
+		device, err := New(NewMockTransport())
+		require.NoError(t, err)
+
+		require.ErrorIs(t, device.ReopenTransport(), ErrDeviceNotSupported)
+		require.ErrorIs(t, device.HardReset(), ErrDeviceNotSupported)
+	})
+}
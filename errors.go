@@ -46,6 +46,7 @@ var (
 	ErrDeviceNotSupported = errors.New("device not supported")
 	ErrCommandFailed      = errors.New("command execution failed")
 	ErrInvalidResponse    = errors.New("invalid response format")
+	ErrBaudRateMismatch   = errors.New("baud rate mismatch")
 
 	// Tag errors - generally not retryable
 	ErrTagNotFound    = errors.New("tag not found")
@@ -72,6 +73,20 @@ const (
 	ErrorTypeTimeout
 )
 
+// String returns the lowercase name of the error type
+func (e ErrorType) String() string {
+	switch e {
+	case ErrorTypeTransient:
+		return "transient"
+	case ErrorTypePermanent:
+		return "permanent"
+	case ErrorTypeTimeout:
+		return "timeout"
+	default:
+		return fmt.Sprintf("ErrorType(%d)", int(e))
+	}
+}
+
 // TransportError wraps transport-level errors with additional context
 type TransportError struct {
 	Err       error     // Underlying error
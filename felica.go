@@ -68,34 +68,33 @@ type FeliCaTag struct {
 }
 
 // NewFeliCaTag creates a new FeliCa tag instance from polling response data
-// targetData should contain the FeliCa polling response (POL_RES)
+// targetData should contain the FeliCa polling response (POL_RES), either
+// bare or as InAutoPoll/InListPassiveTarget target data prefixed with the
+// target number and POL_RES length
 func NewFeliCaTag(device *Device, targetData []byte) (*FeliCaTag, error) {
-	if len(targetData) < 18 {
-		return nil, fmt.Errorf("FeliCa target data too short: %d bytes, need at least 18", len(targetData))
+	polRes := feliCaPollingResponse(targetData)
+	if len(polRes) < 17 {
+		return nil, fmt.Errorf("FeliCa target data too short: %d bytes, need at least 17", len(targetData))
 	}
 
 	// Parse FeliCa polling response structure:
 	// Byte 0: Response Code (0x01 for polling response)
-	// Byte 1: IDm[0]
-	// ...
-	// Byte 8: IDm[7]
-	// Byte 9: PMm[0]
-	// ...
-	// Byte 16: PMm[7]
-	// Byte 17-18: System Code (optional, depends on response length)
+	// Byte 1-8: IDm
+	// Byte 9-16: PMm
+	// Byte 17-18: System Code (optional, depends on request code)
 
 	// Extract IDm (8 bytes) - starts at byte 1
 	idm := make([]byte, feliCaIDmLength)
-	copy(idm, targetData[1:9])
+	copy(idm, polRes[1:9])
 
 	// Extract PMm (8 bytes) - starts at byte 9
 	pmm := make([]byte, feliCaPMmLength)
-	copy(pmm, targetData[9:17])
+	copy(pmm, polRes[9:17])
 
-	// Extract system code if present (last 2 bytes, big endian)
+	// Extract system code if present (big endian)
 	systemCode := uint16(0xFFFF) // Default wildcard
-	if len(targetData) >= 19 {
-		systemCode = uint16(targetData[17])<<8 | uint16(targetData[18])
+	if len(polRes) >= 19 {
+		systemCode = uint16(polRes[17])<<8 | uint16(polRes[18])
 	}
 
 	tag := &FeliCaTag{
@@ -115,6 +114,25 @@ func NewFeliCaTag(device *Device, targetData []byte) (*FeliCaTag, error) {
 	return tag, nil
 }
 
+// feliCaPollingResponse returns the POL_RES in FeliCa target data, starting
+// at the 0x01 response code. The PN532 reports FeliCa targets as
+// [Tg, POL_RES length, POL_RES...].
+func feliCaPollingResponse(targetData []byte) []byte {
+	if len(targetData) >= 3 && int(targetData[1]) == len(targetData)-1 && targetData[2] == 0x01 {
+		return targetData[2:]
+	}
+	return targetData
+}
+
+// feliCaBlockElement encodes a block list element for the first service of
+// the service code list: 2 bytes for blocks up to 255, 3 bytes above
+func feliCaBlockElement(block uint16) []byte {
+	if block <= 0xFF {
+		return []byte{0x80, byte(block)}
+	}
+	return []byte{0x00, byte(block & 0xFF), byte(block >> 8)}
+}
+
 // GetIDm returns the Manufacture ID (IDm) of the FeliCa tag
 func (f *FeliCaTag) GetIDm() []byte {
 	return f.idm
@@ -170,10 +188,8 @@ func (f *FeliCaTag) ReadBlockExtended(block uint16) ([]byte, error) {
 	// Service count, service code, block count, and block list element
 	cmd = append(cmd, 0x01, // Service count (1 service)
 		byte(f.serviceCode&0xFF), byte((f.serviceCode>>8)&0xFF), // Service code (2 bytes, little endian)
-		0x01, // Block count (1 block)
-		// Block list element (3 bytes for 16-bit block addressing)
-		// Format: 0x80 | (block >> 8), block & 0xFF, 0x00
-		0x80|byte((block>>8)&0x0F), byte(block&0xFF), 0x00)
+		0x01) // Block count (1 block)
+	cmd = append(cmd, feliCaBlockElement(block)...)
 
 	// Send command via data exchange
 	response, err := f.device.SendDataExchange(cmd)
@@ -182,7 +198,7 @@ func (f *FeliCaTag) ReadBlockExtended(block uint16) ([]byte, error) {
 	}
 
 	// Check response format
-	if len(response) < 12 {
+	if len(response) < 11 {
 		return nil, fmt.Errorf("FeliCa read response too short: %d bytes", len(response))
 	}
 
@@ -191,6 +207,7 @@ func (f *FeliCaTag) ReadBlockExtended(block uint16) ([]byte, error) {
 	// IDm: 8 bytes
 	// Status Flag 1: 1 byte
 	// Status Flag 2: 1 byte
+	// Number of Blocks: 1 byte (only on success)
 	// Block Data: 16 bytes per block
 
 	if response[0] != 0x07 {
@@ -205,13 +222,13 @@ func (f *FeliCaTag) ReadBlockExtended(block uint16) ([]byte, error) {
 		return nil, fmt.Errorf("FeliCa read failed with status: 0x%02X%02X", statusFlag1, statusFlag2)
 	}
 
-	// Extract block data (starts at byte 11)
-	if len(response) < 11+feliCaBlockSize {
+	// Extract block data (starts at byte 12, after the block count)
+	if len(response) < 12+feliCaBlockSize {
 		return nil, errors.New("FeliCa read response missing block data")
 	}
 
 	blockData := make([]byte, feliCaBlockSize)
-	copy(blockData, response[11:11+feliCaBlockSize])
+	copy(blockData, response[12:12+feliCaBlockSize])
 
 	return blockData, nil
 }
@@ -246,10 +263,8 @@ func (f *FeliCaTag) WriteBlockExtended(block uint16, data []byte) error {
 	// Service count, service code, block count, and block list element
 	cmd = append(cmd, 0x01, // Service count (1 service)
 		byte(f.serviceCode&0xFF), byte((f.serviceCode>>8)&0xFF), // Service code (2 bytes, little endian)
-		0x01, // Block count (1 block)
-		// Block list element (3 bytes for 16-bit block addressing)
-		// Format: 0x80 | (block >> 8), block & 0xFF, 0x00
-		0x80|byte((block>>8)&0x0F), byte(block&0xFF), 0x00)
+		0x01) // Block count (1 block)
+	cmd = append(cmd, feliCaBlockElement(block)...)
 
 	// Add block data (16 bytes)
 	cmd = append(cmd, data...)
@@ -604,8 +619,9 @@ func (*FeliCaTag) validateWritePermissions(aib []byte) error {
 		return errors.New("tag is write-protected")
 	}
 
+	// RWFlag 0x00 is read-only, 0x01 read/write
 	rwFlag := aib[10]
-	if rwFlag == 0x01 {
+	if rwFlag == 0x00 {
 		return errors.New("NDEF data area is read-only")
 	}
 
@@ -687,6 +703,126 @@ func (*FeliCaTag) validateAIB(aib []byte) bool {
 	return sum == storedChecksum
 }
 
+// feliCaNDEFVersion is the AIB version of NFC Forum Type 3 Tag mapping 1.0
+const feliCaNDEFVersion = 0x10
+
+// FeliCaAttributeInfo is the Attribute Information Block (AIB), block 0 of
+// an NFC Forum Type 3 tag
+type FeliCaAttributeInfo struct {
+	NDEFLength      uint32 // Ln: length of the NDEF message in bytes
+	MaxBlocks       uint16 // Nmaxb: number of blocks available for NDEF data
+	Version         byte   // Mapping version, 0x10 for 1.0
+	BlocksPerRead   byte   // Nbr: blocks per Read Without Encryption
+	BlocksPerWrite  byte   // Nbw: blocks per Write Without Encryption
+	WriteInProgress bool   // WriteF: a write was started but not finished
+	ReadOnly        bool   // RWFlag: the NDEF data can't be written
+}
+
+// NewFeliCaAttributeInfo returns the AIB of an empty, writable NDEF area
+func NewFeliCaAttributeInfo(maxBlocks uint16, blocksPerRead, blocksPerWrite byte) *FeliCaAttributeInfo {
+	return &FeliCaAttributeInfo{
+		Version:        feliCaNDEFVersion,
+		BlocksPerRead:  blocksPerRead,
+		BlocksPerWrite: blocksPerWrite,
+		MaxBlocks:      maxBlocks,
+	}
+}
+
+// Bytes encodes the AIB including its checksum
+func (a *FeliCaAttributeInfo) Bytes() []byte {
+	aib := make([]byte, feliCaBlockSize)
+	aib[0] = a.Version
+	aib[1] = a.BlocksPerRead
+	aib[2] = a.BlocksPerWrite
+	aib[3] = byte(a.MaxBlocks >> 8)
+	aib[4] = byte(a.MaxBlocks)
+	if a.WriteInProgress {
+		aib[9] = 0x0F
+	}
+	if !a.ReadOnly {
+		aib[10] = 0x01
+	}
+	aib[11] = byte(a.NDEFLength >> 16)
+	aib[12] = byte(a.NDEFLength >> 8)
+	aib[13] = byte(a.NDEFLength)
+
+	var sum uint16
+	for _, b := range aib[:14] {
+		sum += uint16(b)
+	}
+	aib[14] = byte(sum >> 8)
+	aib[15] = byte(sum)
+	return aib
+}
+
+// ReadAttributeInfo reads and decodes the AIB
+func (f *FeliCaTag) ReadAttributeInfo() (*FeliCaAttributeInfo, error) {
+	originalServiceCode := f.serviceCode
+	f.serviceCode = feliCaServiceCodeNDEFRead
+	defer func() { f.serviceCode = originalServiceCode }()
+
+	aib, err := f.ReadBlockExtended(0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attribute information block: %w", err)
+	}
+	if !f.validateAIB(aib) {
+		return nil, errors.New("invalid attribute information block or checksum mismatch")
+	}
+	// A blank block 0 passes the checksum, the major version rules it out
+	if aib[0]>>4 != feliCaNDEFVersion>>4 {
+		return nil, fmt.Errorf("unsupported NDEF mapping version 0x%02X", aib[0])
+	}
+
+	return &FeliCaAttributeInfo{
+		Version:         aib[0],
+		BlocksPerRead:   aib[1],
+		BlocksPerWrite:  aib[2],
+		MaxBlocks:       uint16(aib[3])<<8 | uint16(aib[4]),
+		WriteInProgress: aib[9] == 0x0F,
+		ReadOnly:        aib[10] == 0x00,
+		NDEFLength:      uint32(aib[11])<<16 | uint32(aib[12])<<8 | uint32(aib[13]),
+	}, nil
+}
+
+// WriteAttributeInfo writes the AIB through the NDEF read/write service
+func (f *FeliCaTag) WriteAttributeInfo(info *FeliCaAttributeInfo) error {
+	originalServiceCode := f.serviceCode
+	f.serviceCode = feliCaServiceCodeNDEFWrite
+	defer func() { f.serviceCode = originalServiceCode }()
+
+	if err := f.WriteBlockExtended(0, info.Bytes()); err != nil {
+		return fmt.Errorf("failed to write attribute information block: %w", err)
+	}
+	return nil
+}
+
+// MakeReadOnly sets the RWFlag of the attribute information block to
+// read-only. Type 3 tags have no lock bits, the flag tells readers not to
+// write. With dryRun nothing is written.
+func (f *FeliCaTag) MakeReadOnly(dryRun bool) ([]LockChange, error) {
+	info, err := f.ReadAttributeInfo()
+	if err != nil {
+		return nil, err
+	}
+	if info.ReadOnly {
+		return nil, nil
+	}
+
+	info.ReadOnly = true
+	changes := []LockChange{{
+		Description: "attribute information block: RWFlag read-only",
+		Data:        info.Bytes(),
+		Address:     0,
+	}}
+	if dryRun {
+		return changes, nil
+	}
+	if err := f.WriteAttributeInfo(info); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
 // DebugInfo returns detailed debug information about the FeliCa tag
 func (f *FeliCaTag) DebugInfo() string {
 	return f.DebugInfoWithNDEF(f)
@@ -0,0 +1,240 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package pn532
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testFeliCaIDm = []byte{0x01, 0x2E, 0x3D, 0x4C, 0x5B, 0x6A, 0x79, 0x88}
+
+// feliCaRequest is a Read or Write Without Encryption command seen by
+// feliCaCardTransport
+type feliCaRequest struct {
+	element     []byte
+	serviceCode uint16
+	block       uint16
+}
+
+// feliCaCardTransport answers Read and Write Without Encryption commands
+// from an in-memory block store and records the requests
+type feliCaCardTransport struct {
+	*MockTransport
+	blocks   map[uint16][]byte
+	requests []feliCaRequest
+}
+
+func (f *feliCaCardTransport) SendCommand(cmd byte, args []byte) ([]byte, error) {
+	return f.SendCommandWithContext(context.Background(), cmd, args)
+}
+
+func (f *feliCaCardTransport) SendCommandWithContext(ctx context.Context, cmd byte, args []byte) ([]byte, error) {
+	if cmd != cmdInDataExchange {
+		return f.MockTransport.SendCommandWithContext(ctx, cmd, args)
+	}
+
+	// Tg, command code, IDm, service count, service code, block count
+	frame := args[1:]
+	const elementOffset = 13
+	req := feliCaRequest{serviceCode: uint16(frame[10]) | uint16(frame[11])<<8}
+	data := frame[elementOffset+2:]
+	if frame[elementOffset]&0x80 != 0 {
+		req.element = frame[elementOffset : elementOffset+2]
+		req.block = uint16(frame[elementOffset+1])
+	} else {
+		req.element = frame[elementOffset : elementOffset+3]
+		req.block = uint16(frame[elementOffset+1]) | uint16(frame[elementOffset+2])<<8
+		data = frame[elementOffset+3:]
+	}
+	f.requests = append(f.requests, req)
+
+	res := []byte{0x41, 0x00, frame[0] + 1}
+	res = append(res, frame[1:9]...)
+	res = append(res, 0x00, 0x00)
+	if frame[0] == feliCaCmdWriteWithoutEncryption {
+		f.blocks[req.block] = append([]byte(nil), data...)
+		return res, nil
+	}
+	block, ok := f.blocks[req.block]
+	if !ok {
+		block = make([]byte, feliCaBlockSize)
+	}
+	res = append(res, 0x01)
+	return append(res, block...), nil
+}
+
+// newTestFeliCaTag creates a FeliCa tag backed by a feliCaCardTransport
+func newTestFeliCaTag(t *testing.T) (*FeliCaTag, *feliCaCardTransport) {
+	t.Helper()
+
+	transport := &feliCaCardTransport{MockTransport: NewMockTransport(), blocks: make(map[uint16][]byte)}
+	device, err := New(transport)
+	require.NoError(t, err)
+
+	targetData := append([]byte{0x01}, testFeliCaIDm...)
+	targetData = append(targetData, 0x03, 0x01, 0x4B, 0x02, 0x4F, 0x49, 0x93, 0xFF, 0x12, 0xFC)
+	tag, err := NewFeliCaTag(device, targetData)
+	require.NoError(t, err)
+	return tag, transport
+}
+
+func TestFeliCaBlockElement(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		want  []byte
+		block uint16
+	}{
+		{name: "First_Block", block: 0, want: []byte{0x80, 0x00}},
+		{name: "Last_Two_Byte_Block", block: 0xFF, want: []byte{0x80, 0xFF}},
+		{name: "First_Three_Byte_Block", block: 0x100, want: []byte{0x00, 0x00, 0x01}},
+		{name: "Little_Endian_Block", block: 0x1234, want: []byte{0x00, 0x34, 0x12}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, feliCaBlockElement(tt.block))
+		})
+	}
+}
+
+func TestFeliCaTag_BlockAddressing(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		wantElement []byte
+		block       uint16
+	}{
+		{name: "Two_Byte_Element", block: 5, wantElement: []byte{0x80, 0x05}},
+		{name: "Three_Byte_Element", block: 300, wantElement: []byte{0x00, 0x2C, 0x01}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tag, transport := newTestFeliCaTag(t)
+			data := []byte{
+				0xA0, 0xA1, 0xA2, 0xA3, 0xA4, 0xA5, 0xA6, 0xA7,
+				0xA8, 0xA9, 0xAA, 0xAB, 0xAC, 0xAD, 0xAE, 0xAF,
+			}
+			require.NoError(t, tag.WriteBlockExtended(tt.block, data))
+
+			// Block data follows the block count at offset 12 of the response
+			got, err := tag.ReadBlockExtended(tt.block)
+			require.NoError(t, err)
+			assert.Equal(t, data, got)
+
+			require.Len(t, transport.requests, 2)
+			for _, req := range transport.requests {
+				assert.Equal(t, tt.wantElement, req.element)
+				assert.Equal(t, tt.block, req.block)
+			}
+		})
+	}
+}
+
+func TestFeliCaAttributeInfo_Bytes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		want         []byte
+		ndefLength   uint32
+		readOnly     bool
+		writePending bool
+	}{
+		{
+			name:       "Writable",
+			ndefLength: 0x20,
+			want: []byte{
+				0x10, 0x04, 0x01, 0x00, 0x0D, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x01, 0x00, 0x00, 0x20, 0x00, 0x43,
+			},
+		},
+		{
+			name:       "Read_Only",
+			ndefLength: 0x20,
+			readOnly:   true,
+			want: []byte{
+				0x10, 0x04, 0x01, 0x00, 0x0D, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x20, 0x00, 0x42,
+			},
+		},
+		{
+			name:         "Write_In_Progress",
+			ndefLength:   0x012345,
+			writePending: true,
+			want: []byte{
+				0x10, 0x04, 0x01, 0x00, 0x0D, 0x00, 0x00, 0x00,
+				0x00, 0x0F, 0x01, 0x01, 0x23, 0x45, 0x00, 0x9B,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			info := NewFeliCaAttributeInfo(0x0D, 4, 1)
+			info.NDEFLength = tt.ndefLength
+			info.ReadOnly = tt.readOnly
+			info.WriteInProgress = tt.writePending
+			assert.Equal(t, tt.want, info.Bytes())
+		})
+	}
+}
+
+func TestFeliCaTag_AttributeInfo(t *testing.T) {
+	t.Parallel()
+
+	tag, transport := newTestFeliCaTag(t)
+	info := NewFeliCaAttributeInfo(0x0D, 4, 1)
+	info.NDEFLength = 0x20
+	info.ReadOnly = true
+	require.NoError(t, tag.WriteAttributeInfo(info))
+
+	got, err := tag.ReadAttributeInfo()
+	require.NoError(t, err)
+	assert.Equal(t, info, got)
+
+	// The AIB is written and read through the NDEF services
+	require.Len(t, transport.requests, 2)
+	assert.Equal(t, uint16(feliCaServiceCodeNDEFWrite), transport.requests[0].serviceCode)
+	assert.Equal(t, uint16(feliCaServiceCodeNDEFRead), transport.requests[1].serviceCode)
+	assert.Equal(t, uint16(feliCaServiceCodeNDEFRead), tag.GetServiceCode(), "service code is restored")
+
+	transport.blocks[0][15]++
+	_, err = tag.ReadAttributeInfo()
+	require.ErrorContains(t, err, "checksum")
+
+	// A blank block 0 has a valid checksum but no mapping version
+	transport.blocks[0] = make([]byte, feliCaBlockSize)
+	_, err = tag.ReadAttributeInfo()
+	require.ErrorContains(t, err, "unsupported NDEF mapping version")
+}
@@ -0,0 +1,118 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+// Package pins provides GPIO helpers shared by the I2C and SPI transports for
+// the PN532 IRQ and RSTPDN lines.
+package pins
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpioreg"
+)
+
+const (
+	// resetPulse is how long RSTPDN is held low during a hard reset
+	resetPulse = 10 * time.Millisecond
+	// resetSettle is how long to wait after releasing RSTPDN before talking to the chip
+	resetSettle = 10 * time.Millisecond
+)
+
+// ErrPinNotFound is returned when a GPIO pin name cannot be resolved
+var ErrPinNotFound = errors.New("GPIO pin not found")
+
+// IRQPin is the subset of a GPIO input needed to wait on the PN532 IRQ line.
+// periph.io gpio.PinIn satisfies this interface.
+type IRQPin interface {
+	In(pull gpio.Pull, edge gpio.Edge) error
+	Read() gpio.Level
+	WaitForEdge(timeout time.Duration) bool
+}
+
+// ResetPin is the subset of a GPIO output needed to drive the PN532 RSTPDN line.
+// periph.io gpio.PinOut satisfies this interface.
+type ResetPin interface {
+	Out(level gpio.Level) error
+}
+
+// ByName resolves a GPIO pin by name from the periph.io registry.
+// The periph host must already be initialized.
+func ByName(name string) (gpio.PinIO, error) {
+	pin := gpioreg.ByName(name)
+	if pin == nil {
+		return nil, fmt.Errorf("%w: %s", ErrPinNotFound, name)
+	}
+	return pin, nil
+}
+
+// ConfigureIRQ sets up the IRQ pin as a pulled-up input with falling edge
+// detection, since the PN532 pulls IRQ low when a response is ready
+func ConfigureIRQ(pin IRQPin) error {
+	if err := pin.In(gpio.PullUp, gpio.FallingEdge); err != nil {
+		return fmt.Errorf("failed to configure IRQ pin: %w", err)
+	}
+	return nil
+}
+
+// WaitIRQ blocks until the IRQ line is low or the deadline passes.
+// It returns true if the PN532 signalled ready. The level is re-checked after
+// every edge so stale edges queued by the GPIO driver do not cause false wakeups.
+func WaitIRQ(pin IRQPin, deadline time.Time) bool {
+	for {
+		if pin.Read() == gpio.Low {
+			return true
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return false
+		}
+
+		if !pin.WaitForEdge(remaining) {
+			return pin.Read() == gpio.Low
+		}
+	}
+}
+
+// ConfigureReset drives the RSTPDN pin high so the PN532 runs normally
+func ConfigureReset(pin ResetPin) error {
+	if err := pin.Out(gpio.High); err != nil {
+		return fmt.Errorf("failed to configure reset pin: %w", err)
+	}
+	return nil
+}
+
+// HardReset pulses RSTPDN low to reset the PN532 and waits for it to restart
+func HardReset(pin ResetPin) error {
+	if err := pin.Out(gpio.Low); err != nil {
+		return fmt.Errorf("failed to assert reset pin: %w", err)
+	}
+	time.Sleep(resetPulse)
+
+	if err := pin.Out(gpio.High); err != nil {
+		return fmt.Errorf("failed to release reset pin: %w", err)
+	}
+	time.Sleep(resetSettle)
+
+	return nil
+}
@@ -0,0 +1,127 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package pins
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	testutil "github.com/ZaparooProject/go-pn532/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"periph.io/x/conn/v3/gpio"
+)
+
+func TestConfigureIRQ(t *testing.T) {
+	t.Parallel()
+
+	pin := testutil.NewFakeIRQPin()
+	require.NoError(t, ConfigureIRQ(pin))
+
+	pull, edge := pin.Config()
+	assert.Equal(t, gpio.PullUp, pull)
+	assert.Equal(t, gpio.FallingEdge, edge)
+}
+
+func TestConfigureIRQ_Error(t *testing.T) {
+	t.Parallel()
+
+	pin := testutil.NewFakeIRQPin()
+	pin.InErr = errors.New("pin busy")
+
+	err := ConfigureIRQ(pin)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "pin busy")
+}
+
+func TestWaitIRQ_AlreadyLow(t *testing.T) {
+	t.Parallel()
+
+	pin := testutil.NewFakeIRQPin()
+	pin.Assert()
+
+	assert.True(t, WaitIRQ(pin, time.Now().Add(time.Second)))
+	assert.Equal(t, 0, pin.WaitCalls(), "should not wait when the line is already low")
+}
+
+func TestWaitIRQ_FallingEdge(t *testing.T) {
+	t.Parallel()
+
+	pin := testutil.NewFakeIRQPin()
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		pin.Assert()
+	}()
+
+	start := time.Now()
+	assert.True(t, WaitIRQ(pin, time.Now().Add(time.Second)))
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+func TestWaitIRQ_Timeout(t *testing.T) {
+	t.Parallel()
+
+	pin := testutil.NewFakeIRQPin()
+
+	start := time.Now()
+	assert.False(t, WaitIRQ(pin, time.Now().Add(20*time.Millisecond)))
+	assert.GreaterOrEqual(t, time.Since(start), 15*time.Millisecond)
+}
+
+func TestWaitIRQ_IgnoresStaleEdge(t *testing.T) {
+	t.Parallel()
+
+	// A queued edge from a previous response while the line is back high
+	pin := testutil.NewFakeIRQPin()
+	pin.Assert()
+	pin.Release()
+
+	assert.False(t, WaitIRQ(pin, time.Now().Add(20*time.Millisecond)))
+	assert.GreaterOrEqual(t, pin.WaitCalls(), 2)
+}
+
+func TestHardReset(t *testing.T) {
+	t.Parallel()
+
+	pin := &testutil.FakeResetPin{}
+	require.NoError(t, ConfigureReset(pin))
+	require.NoError(t, HardReset(pin))
+
+	assert.Equal(t, []gpio.Level{gpio.High, gpio.Low, gpio.High}, pin.Levels())
+}
+
+func TestHardReset_Error(t *testing.T) {
+	t.Parallel()
+
+	pin := &testutil.FakeResetPin{OutErr: errors.New("write failed")}
+
+	err := HardReset(pin)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "write failed")
+}
+
+func TestByName_NotFound(t *testing.T) {
+	t.Parallel()
+
+	_, err := ByName("NO_SUCH_PIN_42")
+	require.ErrorIs(t, err, ErrPinNotFound)
+}
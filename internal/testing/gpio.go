@@ -0,0 +1,144 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package testing
+
+import (
+	"sync"
+	"time"
+
+	"periph.io/x/conn/v3/gpio"
+)
+
+// FakeIRQPin simulates the PN532 IRQ line for transport tests.
+// The line idles high; Assert pulls it low and signals a falling edge.
+type FakeIRQPin struct {
+	InErr     error
+	edges     chan struct{}
+	edge      gpio.Edge
+	waitCalls int
+	mu        sync.Mutex
+	pull      gpio.Pull
+	level     gpio.Level
+}
+
+// NewFakeIRQPin creates a fake IRQ pin idling high
+func NewFakeIRQPin() *FakeIRQPin {
+	return &FakeIRQPin{
+		level: gpio.High,
+		edges: make(chan struct{}, 16),
+	}
+}
+
+// In records the requested pull and edge configuration
+func (p *FakeIRQPin) In(pull gpio.Pull, edge gpio.Edge) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.InErr != nil {
+		return p.InErr
+	}
+	p.pull = pull
+	p.edge = edge
+	return nil
+}
+
+// Read returns the current line level
+func (p *FakeIRQPin) Read() gpio.Level {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.level
+}
+
+// WaitForEdge blocks until Assert is called or the timeout expires
+func (p *FakeIRQPin) WaitForEdge(timeout time.Duration) bool {
+	p.mu.Lock()
+	p.waitCalls++
+	p.mu.Unlock()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-p.edges:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// Assert pulls the line low, as the PN532 does when a response is ready
+func (p *FakeIRQPin) Assert() {
+	p.mu.Lock()
+	p.level = gpio.Low
+	p.mu.Unlock()
+
+	select {
+	case p.edges <- struct{}{}:
+	default:
+	}
+}
+
+// Release returns the line to its idle high level
+func (p *FakeIRQPin) Release() {
+	p.mu.Lock()
+	p.level = gpio.High
+	p.mu.Unlock()
+}
+
+// Config returns the pull and edge passed to In
+func (p *FakeIRQPin) Config() (gpio.Pull, gpio.Edge) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.pull, p.edge
+}
+
+// WaitCalls returns how many times WaitForEdge was called
+func (p *FakeIRQPin) WaitCalls() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.waitCalls
+}
+
+// FakeResetPin records the levels driven onto the PN532 RSTPDN line
+type FakeResetPin struct {
+	OutErr error
+	levels []gpio.Level
+	mu     sync.Mutex
+}
+
+// Out records the driven level
+func (p *FakeResetPin) Out(level gpio.Level) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.OutErr != nil {
+		return p.OutErr
+	}
+	p.levels = append(p.levels, level)
+	return nil
+}
+
+// Levels returns every level driven so far
+func (p *FakeResetPin) Levels() []gpio.Level {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	result := make([]gpio.Level, len(p.levels))
+	copy(result, p.levels)
+	return result
+}
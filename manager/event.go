@@ -0,0 +1,94 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package manager
+
+import (
+	"fmt"
+	"time"
+
+	pn532 "github.com/ZaparooProject/go-pn532"
+	"github.com/ZaparooProject/go-pn532/detection"
+)
+
+// EventType identifies what happened on a reader
+type EventType int
+
+const (
+	// EventReaderConnected is emitted once a reader has been opened and polling started
+	EventReaderConnected EventType = iota
+	// EventReaderDisconnected is emitted when a reader stops responding or is closed
+	EventReaderDisconnected
+	// EventCardDetected is emitted when a card is placed on a reader
+	EventCardDetected
+	// EventCardRemoved is emitted when a card leaves a reader
+	EventCardRemoved
+	// EventCardChanged is emitted when a different card replaces the current one
+	EventCardChanged
+	// EventError reports a detection or connection failure
+	EventError
+)
+
+// String returns a human-readable name for the event type
+func (t EventType) String() string {
+	switch t {
+	case EventReaderConnected:
+		return "reader_connected"
+	case EventReaderDisconnected:
+		return "reader_disconnected"
+	case EventCardDetected:
+		return "card_detected"
+	case EventCardRemoved:
+		return "card_removed"
+	case EventCardChanged:
+		return "card_changed"
+	case EventError:
+		return "error"
+	default:
+		return fmt.Sprintf("EventType(%d)", int(t))
+	}
+}
+
+// ReaderInfo identifies the reader an event came from
+type ReaderInfo struct {
+	// ID is the stable reader ID, it stays the same when a reader is
+	// unplugged and comes back under a different path
+	ID     string
+	Device detection.DeviceInfo
+}
+
+// readerID derives the manager-wide reader ID from detection results
+func readerID(info detection.DeviceInfo) string {
+	if info.StableID != "" {
+		return info.StableID
+	}
+	return detection.StableID(info)
+}
+
+// Event is a single entry in the Manager event stream
+type Event struct {
+	Time time.Time
+	// Err is set for EventError and, if known, the cause of EventReaderDisconnected
+	Err error
+	// Tag is set for EventCardDetected and EventCardChanged
+	Tag    *pn532.DetectedTag
+	Reader ReaderInfo
+	Type   EventType
+}
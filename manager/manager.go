@@ -0,0 +1,388 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+// Package manager runs several PN532 readers at once. It discovers readers
+// with the detection package, keeps a polling.Session running on each one and
+// merges their card events into a single stream tagged with the reader they
+// came from. Readers that disappear are closed and picked up again by a later
+// scan once they come back.
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	pn532 "github.com/ZaparooProject/go-pn532"
+	"github.com/ZaparooProject/go-pn532/detection"
+	"github.com/ZaparooProject/go-pn532/polling"
+)
+
+const (
+	defaultScanInterval  = 5 * time.Second
+	defaultEventBuffer   = 64
+	defaultMaxPollErrors = 5
+)
+
+// ErrAlreadyRunning is returned when Run is called more than once
+var ErrAlreadyRunning = errors.New("manager is already running")
+
+// reader is one open device and the session polling it
+type reader struct {
+	device  *pn532.Device
+	session *polling.Session
+	cancel  context.CancelFunc
+	done    chan struct{}
+	err     error
+	info    ReaderInfo
+}
+
+// Manager discovers PN532 readers and polls all of them
+type Manager struct {
+	detector         DetectorFunc
+	connector        ConnectorFunc
+	transportFactory pn532.TransportFromDeviceFactory
+	readers          map[string]*reader
+	events           chan Event
+	rescan           chan struct{}
+	exited           chan *reader
+	connectOpts      []pn532.ConnectOption
+	detectionOpts    detection.Options
+	pollingConfig    polling.Config
+	scanInterval     time.Duration
+	eventBuffer      int
+	mu               sync.RWMutex
+	eventsMu         sync.RWMutex
+	hotplug          bool
+	eventsClosed     bool
+	running          atomic.Bool
+}
+
+// New creates a Manager. Either WithTransportFactory or WithConnector must
+// be given so the manager knows how to open the readers it finds.
+func New(opts ...Option) (*Manager, error) {
+	detectionOpts := detection.DefaultOptions()
+	// Readers come and go, cached results would hide that
+	detectionOpts.EnableCache = false
+
+	pollingConfig := *polling.DefaultConfig()
+	pollingConfig.MaxPollErrors = defaultMaxPollErrors
+
+	m := &Manager{
+		detector:      detection.DetectAllContext,
+		detectionOpts: detectionOpts,
+		pollingConfig: pollingConfig,
+		scanInterval:  defaultScanInterval,
+		eventBuffer:   defaultEventBuffer,
+		hotplug:       true,
+		readers:       make(map[string]*reader),
+		rescan:        make(chan struct{}, 1),
+		exited:        make(chan *reader),
+	}
+
+	for _, opt := range opts {
+		if err := opt(m); err != nil {
+			return nil, fmt.Errorf("failed to apply manager option: %w", err)
+		}
+	}
+
+	if m.connector == nil {
+		if m.transportFactory == nil {
+			return nil, errors.New("manager needs a transport factory or a connector")
+		}
+		m.connector = m.connectDevice
+	}
+	m.events = make(chan Event, m.eventBuffer)
+
+	return m, nil
+}
+
+// Events returns the unified event stream. The channel is closed once Run
+// has returned and all readers are closed. Consumers must keep draining it,
+// a full channel stalls polling on the reader that is trying to report.
+func (m *Manager) Events() <-chan Event {
+	return m.events
+}
+
+// Readers returns the currently connected readers sorted by ID
+func (m *Manager) Readers() []ReaderInfo {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	infos := make([]ReaderInfo, 0, len(m.readers))
+	for _, r := range m.readers {
+		infos = append(infos, r.info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].ID < infos[j].ID
+	})
+	return infos
+}
+
+// Session returns the polling session of a connected reader, e.g. to write
+// to a tag with Session.WriteToNextTag
+func (m *Manager) Session(id string) (*polling.Session, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	r, ok := m.readers[id]
+	if !ok {
+		return nil, false
+	}
+	return r.session, true
+}
+
+// Rescan asks a running manager to look for readers now instead of waiting
+// for the next scan interval
+func (m *Manager) Rescan() {
+	select {
+	case m.rescan <- struct{}{}:
+	default:
+	}
+}
+
+// Run scans for readers and polls them until ctx is cancelled. It blocks,
+// returns ctx.Err() and closes every reader before returning.
+func (m *Manager) Run(ctx context.Context) error {
+	if !m.running.CompareAndSwap(false, true) {
+		return ErrAlreadyRunning
+	}
+	defer m.closeEvents()
+	defer m.closeAll()
+
+	if m.hotplug {
+		go m.watchHotplug(ctx)
+	}
+
+	ticker := time.NewTicker(m.scanInterval)
+	defer ticker.Stop()
+
+	m.scan(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			m.scan(ctx)
+		case <-m.rescan:
+			m.scan(ctx)
+		case r := <-m.exited:
+			m.disconnect(ctx, r)
+		}
+	}
+}
+
+// watchHotplug triggers a debounced rescan on kernel device events. Without
+// hotplug support the manager relies on periodic scanning alone.
+func (m *Manager) watchHotplug(ctx context.Context) {
+	err := watchUevents(ctx, m.Rescan)
+	if err == nil || ctx.Err() != nil || errors.Is(err, detection.ErrUnsupportedPlatform) {
+		return
+	}
+	m.emit(ctx, Event{Type: EventError, Err: fmt.Errorf("hotplug events unavailable: %w", err)})
+}
+
+// scan runs the detector and connects every reader that is not open yet
+func (m *Manager) scan(ctx context.Context) {
+	opts := m.detectionOpts
+	opts.IgnorePaths = append([]string(nil), m.detectionOpts.IgnorePaths...)
+	m.mu.RLock()
+	for _, r := range m.readers {
+		// Probing an open port would disturb the session using it
+		opts.IgnorePaths = append(opts.IgnorePaths, r.info.Device.Path)
+	}
+	m.mu.RUnlock()
+
+	devices, err := m.detector(ctx, &opts)
+	if err != nil && !errors.Is(err, detection.ErrNoDevicesFound) && ctx.Err() == nil {
+		m.emit(ctx, Event{Type: EventError, Err: fmt.Errorf("reader detection failed: %w", err)})
+	}
+
+	for _, info := range devices {
+		if ctx.Err() != nil {
+			return
+		}
+		id := readerID(info)
+		m.mu.RLock()
+		_, open := m.readers[id]
+		m.mu.RUnlock()
+		if !open {
+			m.connect(ctx, ReaderInfo{ID: id, Device: info})
+		}
+	}
+}
+
+// connect opens a reader and starts polling it
+func (m *Manager) connect(ctx context.Context, info ReaderInfo) {
+	device, err := m.connector(ctx, info.Device)
+	if err != nil {
+		m.emit(ctx, Event{Type: EventError, Reader: info, Err: fmt.Errorf("failed to connect reader: %w", err)})
+		return
+	}
+
+	readerCtx, cancel := context.WithCancel(ctx)
+	config := m.pollingConfig
+	r := &reader{
+		info:    info,
+		device:  device,
+		session: polling.NewSession(device, &config),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	m.attachCallbacks(readerCtx, r)
+
+	m.mu.Lock()
+	m.readers[info.ID] = r
+	m.mu.Unlock()
+
+	m.emit(ctx, Event{Type: EventReaderConnected, Reader: info})
+
+	go func() {
+		defer close(r.done)
+		r.err = r.session.Start(readerCtx)
+		if readerCtx.Err() != nil {
+			return
+		}
+		// The session gave up on its own, let Run tear the reader down
+		select {
+		case m.exited <- r:
+		case <-readerCtx.Done():
+		}
+	}()
+}
+
+// attachCallbacks forwards session callbacks to the event stream
+func (m *Manager) attachCallbacks(ctx context.Context, r *reader) {
+	r.session.OnCardDetected = func(tag *pn532.DetectedTag) error {
+		m.emit(ctx, Event{Type: EventCardDetected, Reader: r.info, Tag: tag})
+		return nil
+	}
+	r.session.OnCardChanged = func(tag *pn532.DetectedTag) error {
+		m.emit(ctx, Event{Type: EventCardChanged, Reader: r.info, Tag: tag})
+		return nil
+	}
+	r.session.OnCardRemoved = func() {
+		m.emit(ctx, Event{Type: EventCardRemoved, Reader: r.info})
+	}
+}
+
+// disconnect closes a reader whose session stopped and reports it
+func (m *Manager) disconnect(ctx context.Context, r *reader) {
+	m.mu.Lock()
+	delete(m.readers, r.info.ID)
+	m.mu.Unlock()
+
+	r.cancel()
+	<-r.done
+	m.closeReader(r)
+
+	m.emit(ctx, Event{Type: EventReaderDisconnected, Reader: r.info, Err: r.err})
+}
+
+// closeAll stops every reader when Run exits
+func (m *Manager) closeAll() {
+	m.mu.Lock()
+	readers := make([]*reader, 0, len(m.readers))
+	for id, r := range m.readers {
+		readers = append(readers, r)
+		delete(m.readers, id)
+	}
+	m.mu.Unlock()
+
+	for _, r := range readers {
+		r.cancel()
+	}
+	for _, r := range readers {
+		<-r.done
+		m.closeReader(r)
+		m.tryEmit(Event{Type: EventReaderDisconnected, Reader: r.info})
+	}
+}
+
+// closeReader releases the session and device of a stopped reader
+func (*Manager) closeReader(r *reader) {
+	_ = r.session.Close()
+	_ = r.device.Close()
+}
+
+// emit publishes an event, giving up if ctx is cancelled first
+func (m *Manager) emit(ctx context.Context, event Event) {
+	event.Time = time.Now()
+
+	// Removal timers may still fire while Run shuts down
+	m.eventsMu.RLock()
+	defer m.eventsMu.RUnlock()
+	if m.eventsClosed {
+		return
+	}
+	select {
+	case m.events <- event:
+	case <-ctx.Done():
+	}
+}
+
+// tryEmit publishes an event only if there is room, nobody may be reading
+// any more during shutdown
+func (m *Manager) tryEmit(event Event) {
+	event.Time = time.Now()
+
+	m.eventsMu.RLock()
+	defer m.eventsMu.RUnlock()
+	if m.eventsClosed {
+		return
+	}
+	select {
+	case m.events <- event:
+	default:
+	}
+}
+
+// closeEvents closes the event stream once no emitter can use it
+func (m *Manager) closeEvents() {
+	m.eventsMu.Lock()
+	defer m.eventsMu.Unlock()
+	m.eventsClosed = true
+	close(m.events)
+}
+
+// connectDevice is the default connector built on pn532.ConnectDevice. The
+// reader is connected through detection of just this device, so the device
+// knows the stable ID it is managed under.
+func (m *Manager) connectDevice(_ context.Context, info detection.DeviceInfo) (*pn532.Device, error) {
+	info.StableID = readerID(info)
+	detector := func(*detection.Options) ([]detection.DeviceInfo, error) {
+		return []detection.DeviceInfo{info}, nil
+	}
+	opts := append([]pn532.ConnectOption{
+		pn532.WithStableID(info.StableID),
+		pn532.WithDeviceDetector(detector),
+		pn532.WithTransportFromDeviceFactory(m.transportFactory),
+	}, m.connectOpts...)
+
+	device, err := pn532.ConnectDevice("", opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect %s: %w", info, err)
+	}
+	return device, nil
+}
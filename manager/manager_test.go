@@ -0,0 +1,378 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package manager
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	pn532 "github.com/ZaparooProject/go-pn532"
+	"github.com/ZaparooProject/go-pn532/detection"
+	"github.com/ZaparooProject/go-pn532/pn532sim"
+	"github.com/ZaparooProject/go-pn532/polling"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testUID = []byte{0x04, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66}
+
+// fakeBus stands in for the host's ports, each with a simulated reader
+type fakeBus struct {
+	readers map[string]*pn532sim.Simulator
+	serials map[string]string
+	mu      sync.Mutex
+}
+
+func newFakeBus() *fakeBus {
+	return &fakeBus{
+		readers: make(map[string]*pn532sim.Simulator),
+		serials: make(map[string]string),
+	}
+}
+
+// plugSerial attaches a reader that reports a USB serial number
+func (b *fakeBus) plugSerial(t *testing.T, path, serial string) *pn532sim.Simulator {
+	t.Helper()
+	sim := b.plug(t, path)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.serials[path] = serial
+	return sim
+}
+
+func (b *fakeBus) plug(t *testing.T, path string) *pn532sim.Simulator {
+	t.Helper()
+	sim, err := pn532sim.New()
+	require.NoError(t, err)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.readers[path] = sim
+	return sim
+}
+
+func (b *fakeBus) unplug(path string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if sim, ok := b.readers[path]; ok {
+		_ = sim.Close()
+		delete(b.readers, path)
+		delete(b.serials, path)
+	}
+}
+
+func (b *fakeBus) detect(_ context.Context, opts *detection.Options) ([]detection.DeviceInfo, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var devices []detection.DeviceInfo
+	for path := range b.readers {
+		if detection.IsPathIgnored(path, opts.IgnorePaths) {
+			continue
+		}
+		info := detection.DeviceInfo{Transport: "mock", Path: path, Confidence: detection.High}
+		if serial, ok := b.serials[path]; ok {
+			info.Metadata = map[string]string{detection.MetadataSerial: serial}
+		}
+		devices = append(devices, info)
+	}
+	if len(devices) == 0 {
+		return nil, detection.ErrNoDevicesFound
+	}
+	return devices, nil
+}
+
+func (b *fakeBus) connect(ctx context.Context, info detection.DeviceInfo) (*pn532.Device, error) {
+	b.mu.Lock()
+	sim, ok := b.readers[info.Path]
+	b.mu.Unlock()
+	if !ok {
+		return nil, errors.New("no such device")
+	}
+
+	device, err := pn532.New(sim)
+	if err != nil {
+		return nil, err
+	}
+	if err := device.InitContext(ctx); err != nil {
+		return nil, err
+	}
+	return device, nil
+}
+
+// startManager runs a manager on the bus until the test ends
+func startManager(t *testing.T, bus *fakeBus, opts ...Option) *Manager {
+	t.Helper()
+
+	opts = append([]Option{
+		WithDetector(bus.detect),
+		WithConnector(bus.connect),
+		WithHotplug(false),
+		WithScanInterval(time.Hour),
+		WithPollingConfig(&polling.Config{
+			PollInterval:       5 * time.Millisecond,
+			CardRemovalTimeout: 50 * time.Millisecond,
+			MaxPollErrors:      2,
+		}),
+	}, opts...)
+	m, err := New(opts...)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- m.Run(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return m
+}
+
+// nextEvent waits for the next event of the given type, skipping others
+func nextEvent(t *testing.T, events <-chan Event, eventType EventType) Event {
+	t.Helper()
+
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case event, ok := <-events:
+			require.True(t, ok, "event stream closed while waiting for %s", eventType)
+			if event.Type == eventType {
+				return event
+			}
+		case <-timeout:
+			require.FailNow(t, "timed out waiting for event", eventType.String())
+		}
+	}
+}
+
+func TestNew_Options(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantErr bool
+	}{
+		{name: "no way to connect", wantErr: true},
+		{name: "transport factory", opts: []Option{WithTransportFactory(
+			func(detection.DeviceInfo) (pn532.Transport, error) { return nil, errors.New("unused") },
+		)}},
+		{name: "connector", opts: []Option{WithConnector(newFakeBus().connect)}},
+		{name: "nil detector", opts: []Option{WithDetector(nil)}, wantErr: true},
+		{name: "nil connector", opts: []Option{WithConnector(nil)}, wantErr: true},
+		{name: "nil polling config", opts: []Option{WithPollingConfig(nil)}, wantErr: true},
+		{name: "zero scan interval", opts: []Option{WithScanInterval(0)}, wantErr: true},
+		{name: "negative event buffer", opts: []Option{WithEventBuffer(-1)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(tt.opts...)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestWithPollingConfig_KeepsErrorLimit(t *testing.T) {
+	t.Parallel()
+
+	m, err := New(WithConnector(newFakeBus().connect), WithPollingConfig(polling.DefaultConfig()))
+	require.NoError(t, err)
+	assert.Equal(t, defaultMaxPollErrors, m.pollingConfig.MaxPollErrors)
+}
+
+func TestManager_CardEvents(t *testing.T) {
+	t.Parallel()
+
+	bus := newFakeBus()
+	left := bus.plug(t, "/dev/left")
+	bus.plug(t, "/dev/right")
+	m := startManager(t, bus)
+
+	connected := map[string]bool{}
+	for len(connected) < 2 {
+		connected[nextEvent(t, m.Events(), EventReaderConnected).Reader.ID] = true
+	}
+	assert.True(t, connected["mock:/dev/left"])
+	assert.True(t, connected["mock:/dev/right"])
+	require.Len(t, m.Readers(), 2)
+	assert.Equal(t, "mock:/dev/left", m.Readers()[0].ID)
+
+	tag := pn532sim.NewNTAG213(testUID)
+	left.PlaceTag(tag)
+	detected := nextEvent(t, m.Events(), EventCardDetected)
+	assert.Equal(t, "mock:/dev/left", detected.Reader.ID)
+	require.NotNil(t, detected.Tag)
+	assert.Equal(t, testUID, detected.Tag.UIDBytes)
+	assert.False(t, detected.Time.IsZero())
+
+	left.RemoveTag(tag)
+	removed := nextEvent(t, m.Events(), EventCardRemoved)
+	assert.Equal(t, "mock:/dev/left", removed.Reader.ID)
+
+	session, ok := m.Session("mock:/dev/right")
+	require.True(t, ok)
+	assert.NotNil(t, session)
+	_, ok = m.Session("mock:/dev/missing")
+	assert.False(t, ok)
+}
+
+func TestManager_Reconnect(t *testing.T) {
+	t.Parallel()
+
+	bus := newFakeBus()
+	bus.plug(t, "/dev/reader")
+	m := startManager(t, bus)
+	nextEvent(t, m.Events(), EventReaderConnected)
+
+	bus.unplug("/dev/reader")
+	disconnected := nextEvent(t, m.Events(), EventReaderDisconnected)
+	assert.Equal(t, "mock:/dev/reader", disconnected.Reader.ID)
+	require.ErrorIs(t, disconnected.Err, polling.ErrTooManyPollErrors)
+	assert.Empty(t, m.Readers())
+
+	sim := bus.plug(t, "/dev/reader")
+	m.Rescan()
+	reconnected := nextEvent(t, m.Events(), EventReaderConnected)
+	assert.Equal(t, "mock:/dev/reader", reconnected.Reader.ID)
+
+	sim.PlaceTag(pn532sim.NewNTAG213(testUID))
+	detected := nextEvent(t, m.Events(), EventCardDetected)
+	assert.Equal(t, "mock:/dev/reader", detected.Reader.ID)
+}
+
+func TestManager_StableIDAcrossPaths(t *testing.T) {
+	t.Parallel()
+
+	bus := newFakeBus()
+	bus.plugSerial(t, "/dev/ttyUSB0", "LEFT")
+	m := startManager(t, bus)
+	connected := nextEvent(t, m.Events(), EventReaderConnected)
+	assert.Equal(t, "mock:usb-serial:LEFT", connected.Reader.ID)
+
+	// Re-enumerated under another name after being plugged back in
+	bus.unplug("/dev/ttyUSB0")
+	nextEvent(t, m.Events(), EventReaderDisconnected)
+	bus.plugSerial(t, "/dev/ttyUSB1", "LEFT")
+	m.Rescan()
+
+	reconnected := nextEvent(t, m.Events(), EventReaderConnected)
+	assert.Equal(t, "mock:usb-serial:LEFT", reconnected.Reader.ID)
+	assert.Equal(t, "/dev/ttyUSB1", reconnected.Reader.Device.Path)
+}
+
+func TestManager_DefaultConnectorStableID(t *testing.T) {
+	t.Parallel()
+
+	bus := newFakeBus()
+	sim := bus.plugSerial(t, "/dev/ttyUSB0", "LEFT")
+	// The default connector opens the reader through the transport factory
+	m, err := New(
+		WithDetector(bus.detect),
+		WithTransportFactory(func(detection.DeviceInfo) (pn532.Transport, error) { return sim, nil }),
+		WithHotplug(false),
+		WithScanInterval(time.Hour),
+		WithPollingConfig(&polling.Config{PollInterval: 5 * time.Millisecond, CardRemovalTimeout: 50 * time.Millisecond}),
+	)
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- m.Run(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	connected := nextEvent(t, m.Events(), EventReaderConnected)
+	assert.Equal(t, "mock:usb-serial:LEFT", connected.Reader.ID)
+	session, ok := m.Session(connected.Reader.ID)
+	require.True(t, ok)
+	events := session.Events()
+
+	sim.PlaceTag(pn532sim.NewNTAG213(testUID))
+	select {
+	case event := <-events:
+		assert.Equal(t, polling.EventDetected, event.Type)
+		assert.Equal(t, "mock:usb-serial:LEFT", event.Reader, "session events name the managed reader")
+	case <-time.After(2 * time.Second):
+		require.FailNow(t, "timed out waiting for session event")
+	}
+}
+
+func TestManager_ConnectError(t *testing.T) {
+	t.Parallel()
+
+	bus := newFakeBus()
+	bus.plug(t, "/dev/broken")
+	connectErr := errors.New("port busy")
+	m := startManager(t, bus, WithConnector(func(context.Context, detection.DeviceInfo) (*pn532.Device, error) {
+		return nil, connectErr
+	}))
+
+	event := nextEvent(t, m.Events(), EventError)
+	assert.Equal(t, "mock:/dev/broken", event.Reader.ID)
+	require.ErrorIs(t, event.Err, connectErr)
+	assert.Empty(t, m.Readers())
+}
+
+func TestManager_RunStops(t *testing.T) {
+	t.Parallel()
+
+	bus := newFakeBus()
+	bus.plug(t, "/dev/reader")
+	m, err := New(WithDetector(bus.detect), WithConnector(bus.connect), WithHotplug(false))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- m.Run(ctx) }()
+	nextEvent(t, m.Events(), EventReaderConnected)
+
+	require.ErrorIs(t, m.Run(ctx), ErrAlreadyRunning)
+
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+	assert.Empty(t, m.Readers())
+
+	// Remaining events drain, then the stream is closed
+	for event := range m.Events() {
+		assert.Equal(t, EventReaderDisconnected, event.Type)
+	}
+}
+
+func TestEventType_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "card_detected", EventCardDetected.String())
+	assert.Equal(t, "reader_disconnected", EventReaderDisconnected.String())
+	assert.Equal(t, "EventType(42)", EventType(42).String())
+}
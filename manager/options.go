@@ -0,0 +1,133 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package manager
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	pn532 "github.com/ZaparooProject/go-pn532"
+	"github.com/ZaparooProject/go-pn532/detection"
+	"github.com/ZaparooProject/go-pn532/polling"
+)
+
+// DetectorFunc discovers the readers currently attached to the host
+type DetectorFunc func(ctx context.Context, opts *detection.Options) ([]detection.DeviceInfo, error)
+
+// ConnectorFunc opens and initializes a discovered reader
+type ConnectorFunc func(ctx context.Context, info detection.DeviceInfo) (*pn532.Device, error)
+
+// Option configures a Manager
+type Option func(*Manager) error
+
+// WithDetectionOptions sets the options passed to the detector on every scan.
+// Paths of readers that are already open are always added to IgnorePaths.
+func WithDetectionOptions(opts detection.Options) Option {
+	return func(m *Manager) error {
+		m.detectionOpts = opts
+		return nil
+	}
+}
+
+// WithDetector replaces detection.DetectAllContext as the discovery mechanism
+func WithDetector(detector DetectorFunc) Option {
+	return func(m *Manager) error {
+		if detector == nil {
+			return errors.New("detector must not be nil")
+		}
+		m.detector = detector
+		return nil
+	}
+}
+
+// WithTransportFactory sets how transports are created for discovered readers
+func WithTransportFactory(factory pn532.TransportFromDeviceFactory) Option {
+	return func(m *Manager) error {
+		m.transportFactory = factory
+		return nil
+	}
+}
+
+// WithConnector replaces pn532.ConnectDevice for opening discovered readers
+func WithConnector(connector ConnectorFunc) Option {
+	return func(m *Manager) error {
+		if connector == nil {
+			return errors.New("connector must not be nil")
+		}
+		m.connector = connector
+		return nil
+	}
+}
+
+// WithConnectOptions adds options passed to pn532.ConnectDevice
+func WithConnectOptions(opts ...pn532.ConnectOption) Option {
+	return func(m *Manager) error {
+		m.connectOpts = append(m.connectOpts, opts...)
+		return nil
+	}
+}
+
+// WithPollingConfig sets the polling configuration used for every reader.
+// If MaxPollErrors is zero the manager default is used so that unplugged
+// readers are still noticed.
+func WithPollingConfig(config *polling.Config) Option {
+	return func(m *Manager) error {
+		if config == nil {
+			return errors.New("polling config must not be nil")
+		}
+		m.pollingConfig = *config
+		if m.pollingConfig.MaxPollErrors == 0 {
+			m.pollingConfig.MaxPollErrors = defaultMaxPollErrors
+		}
+		return nil
+	}
+}
+
+// WithScanInterval sets how often the manager looks for new readers
+func WithScanInterval(interval time.Duration) Option {
+	return func(m *Manager) error {
+		if interval <= 0 {
+			return errors.New("scan interval must be positive")
+		}
+		m.scanInterval = interval
+		return nil
+	}
+}
+
+// WithEventBuffer sets the capacity of the Events channel
+func WithEventBuffer(size int) Option {
+	return func(m *Manager) error {
+		if size < 0 {
+			return errors.New("event buffer size must not be negative")
+		}
+		m.eventBuffer = size
+		return nil
+	}
+}
+
+// WithHotplug enables or disables rescanning on kernel device events (Linux only)
+func WithHotplug(enabled bool) Option {
+	return func(m *Manager) error {
+		m.hotplug = enabled
+		return nil
+	}
+}
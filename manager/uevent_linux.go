@@ -0,0 +1,113 @@
+//go:build linux
+
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package manager
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// ueventGroupKernel is the netlink multicast group of kernel uevents
+	ueventGroupKernel  = 1
+	ueventBufferSize   = 16 * 1024
+	ueventReadTimeout  = 500 * time.Millisecond
+	ueventDebounceTime = 500 * time.Millisecond
+)
+
+// readerSubsystems are the device subsystems PN532 transports show up in
+var readerSubsystems = [][]byte{[]byte("tty"), []byte("i2c-dev"), []byte("spidev")}
+
+// watchUevents listens for kernel device events and calls rescan, debounced,
+// whenever a possible reader is added or removed. It returns nil once ctx is
+// cancelled.
+func watchUevents(ctx context.Context, rescan func()) error {
+	fd, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_RAW|unix.SOCK_CLOEXEC, unix.NETLINK_KOBJECT_UEVENT)
+	if err != nil {
+		return fmt.Errorf("failed to open uevent socket: %w", err)
+	}
+	defer func() { _ = unix.Close(fd) }()
+
+	if err := unix.Bind(fd, &unix.SockaddrNetlink{Family: unix.AF_NETLINK, Groups: ueventGroupKernel}); err != nil {
+		return fmt.Errorf("failed to bind uevent socket: %w", err)
+	}
+	// Wake up regularly to notice cancellation
+	timeout := unix.NsecToTimeval(ueventReadTimeout.Nanoseconds())
+	if err := unix.SetsockoptTimeval(fd, unix.SOL_SOCKET, unix.SO_RCVTIMEO, &timeout); err != nil {
+		return fmt.Errorf("failed to set uevent socket timeout: %w", err)
+	}
+
+	// A USB adapter produces a burst of events, scan once it has settled
+	debounce := time.AfterFunc(time.Hour, rescan)
+	debounce.Stop()
+	defer debounce.Stop()
+
+	buf := make([]byte, ueventBufferSize)
+	for ctx.Err() == nil {
+		n, _, err := unix.Recvfrom(fd, buf, 0)
+		switch {
+		case errors.Is(err, unix.EAGAIN), errors.Is(err, unix.EINTR):
+			continue
+		case errors.Is(err, unix.ENOBUFS):
+			// Events were dropped, we can't tell what changed
+			debounce.Reset(ueventDebounceTime)
+			continue
+		case err != nil:
+			return fmt.Errorf("failed to read uevent: %w", err)
+		}
+
+		if isReaderUevent(buf[:n]) {
+			debounce.Reset(ueventDebounceTime)
+		}
+	}
+	return nil
+}
+
+// isReaderUevent reports whether a kernel uevent adds or removes a device
+// a reader could be attached through. Messages are a header followed by
+// NUL-separated KEY=value pairs.
+func isReaderUevent(msg []byte) bool {
+	var action, subsystem []byte
+	for _, field := range bytes.Split(msg, []byte{0}) {
+		if value, ok := bytes.CutPrefix(field, []byte("ACTION=")); ok {
+			action = value
+		} else if value, ok := bytes.CutPrefix(field, []byte("SUBSYSTEM=")); ok {
+			subsystem = value
+		}
+	}
+
+	if !bytes.Equal(action, []byte("add")) && !bytes.Equal(action, []byte("remove")) {
+		return false
+	}
+	for _, s := range readerSubsystems {
+		if bytes.Equal(subsystem, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+//go:build linux
+
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package manager
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsReaderUevent(t *testing.T) {
+	t.Parallel()
+
+	uevent := func(fields ...string) []byte {
+		return []byte(strings.Join(fields, "\x00") + "\x00")
+	}
+
+	tests := []struct {
+		name string
+		msg  []byte
+		want bool
+	}{
+		{
+			name: "USB serial adapter added",
+			msg: uevent("add@/devices/pci0000:00/usb1/1-1/1-1:1.0/ttyUSB0/tty/ttyUSB0",
+				"ACTION=add", "DEVPATH=/devices/pci0000:00/usb1/1-1/1-1:1.0/ttyUSB0/tty/ttyUSB0",
+				"SUBSYSTEM=tty", "MAJOR=188", "MINOR=0", "DEVNAME=ttyUSB0", "SEQNUM=4242"),
+			want: true,
+		},
+		{
+			name: "I2C bus removed",
+			msg:  uevent("remove@/devices/i2c-1/i2c-dev/i2c-1", "ACTION=remove", "SUBSYSTEM=i2c-dev", "DEVNAME=i2c-1"),
+			want: true,
+		},
+		{
+			name: "SPI device added",
+			msg:  uevent("add@/devices/spi0.0/spidev/spidev0.0", "ACTION=add", "SUBSYSTEM=spidev"),
+			want: true,
+		},
+		{
+			name: "USB interface bound",
+			msg:  uevent("bind@/devices/usb1/1-1/1-1:1.0", "ACTION=bind", "SUBSYSTEM=usb"),
+			want: false,
+		},
+		{
+			name: "tty changed",
+			msg:  uevent("change@/devices/virtual/tty/tty1", "ACTION=change", "SUBSYSTEM=tty"),
+			want: false,
+		},
+		{name: "empty", msg: nil, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, isReaderUevent(tt.msg))
+		})
+	}
+}
@@ -67,6 +67,14 @@ var (
 	chineseCloneUnlock8Bit = byte(0x43)
 )
 
+// Sector trailer access bits and general purpose byte of a read-only NDEF
+// sector: data blocks readable with either key and never writable, trailer
+// frozen
+var (
+	mifareReadOnlyAccessBits = []byte{0x07, 0x8F, 0x0F}
+	mifareGPBReadOnly        = byte(0x03)
+)
+
 // MIFARE commands
 const (
 	mifareCmdAuth  = 0x60
@@ -78,6 +86,9 @@ const (
 const (
 	mifareBlockSize         = 16 // 16 bytes per block
 	mifareSectorSize        = 4  // 4 blocks per sector
+	mifareLargeSectorSize   = 16 // 16 blocks per sector from sector 32 of a 4K
+	mifareSmallSectors      = 32 // Sectors of 4 blocks on a 4K
+	mifareMAD2Sector        = 16 // Sector holding MAD2 on a 4K
 	mifareManufacturerBlock = 0  // Manufacturer block
 	mifareKeySize           = 6  // 6 bytes per key
 )
@@ -1255,6 +1266,92 @@ func (t *MIFARETag) formatForNDEFWithKey(blankKey []byte) error {
 	return nil
 }
 
+// MakeReadOnly permanently write protects every sector that opens with the
+// NDEF key by writing read-only access bits and setting the write access
+// bits of its general purpose byte. This can't be undone. The MAD sectors
+// are left alone. Sectors already read-only are left out, with dryRun
+// nothing is written.
+func (t *MIFARETag) MakeReadOnly(dryRun bool) ([]LockChange, error) {
+	ndefKeyBytes := t.ndefKey.bytes()
+	defer clearKeyBytes(ndefKeyBytes)
+
+	var changes []LockChange
+	ndefSectors := 0
+	for sector := 1; sector < int(t.determineMaxSectors()); sector++ {
+		// Sector 16 of a 4K holds MAD2
+		if t.IsMIFARE4K() && sector == mifareMAD2Sector {
+			continue
+		}
+
+		trailerBlock := uint8(MIFARETrailerBlock(sector))
+		current, err := t.readNDEFTrailer(trailerBlock)
+		if err != nil {
+			// Not an NDEF sector
+			continue
+		}
+		ndefSectors++
+
+		trailer := make([]byte, 0, mifareBlockSize)
+		trailer = append(trailer, ndefKeyBytes...)
+		trailer = append(trailer, mifareReadOnlyAccessBits...)
+		trailer = append(trailer, current[9]|mifareGPBReadOnly)
+		trailer = append(trailer, ndefKeyBytes...)
+		if bytes.Equal(current[6:10], trailer[6:10]) {
+			continue
+		}
+
+		change := LockChange{
+			Description: fmt.Sprintf("sector %d trailer: read-only access bits", sector),
+			Data:        trailer,
+			Address:     uint16(trailerBlock),
+		}
+		if !dryRun {
+			if err := t.WriteBlock(trailerBlock, trailer); err != nil {
+				return changes, fmt.Errorf("failed to lock sector %d: %w", sector, err)
+			}
+		}
+		changes = append(changes, change)
+	}
+
+	if ndefSectors == 0 {
+		return nil, errors.New("tag is not NDEF formatted: no sector opens with the NDEF key")
+	}
+	return changes, nil
+}
+
+// readNDEFTrailer authenticates with the NDEF key, key B first as it
+// manages NDEF sector trailers, and reads the trailer
+func (t *MIFARETag) readNDEFTrailer(trailerBlock uint8) ([]byte, error) {
+	// Authentication addresses sectors as block / 4, which for the 16-block
+	// sectors of a 4K still lands in the trailer's sector
+	sector := trailerBlock / mifareSectorSize
+	if err := t.authenticateNDEF(sector, MIFAREKeyB); err != nil {
+		if err := t.authenticateNDEF(sector, MIFAREKeyA); err != nil {
+			return nil, err
+		}
+	}
+	return t.ReadBlock(trailerBlock)
+}
+
+// MIFARETrailerBlock returns the trailer block of a MIFARE Classic sector.
+// Sectors 32-39 of a 4K hold 16 blocks.
+func MIFARETrailerBlock(sector int) int {
+	if sector < mifareSmallSectors {
+		return sector*mifareSectorSize + mifareSectorSize - 1
+	}
+	return mifareSmallSectors*mifareSectorSize + (sector-mifareSmallSectors)*mifareLargeSectorSize +
+		mifareLargeSectorSize - 1
+}
+
+// IsMIFARETrailerBlock reports whether a MIFARE Classic block is a sector
+// trailer
+func IsMIFARETrailerBlock(block int) bool {
+	if block < mifareSmallSectors*mifareSectorSize {
+		return block%mifareSectorSize == mifareSectorSize-1
+	}
+	return (block-mifareSmallSectors*mifareSectorSize)%mifareLargeSectorSize == mifareLargeSectorSize-1
+}
+
 // DebugInfo returns detailed debug information about the MIFARE tag
 func (t *MIFARETag) DebugInfo() string {
 	return t.DebugInfoWithNDEF(t)
@@ -167,6 +167,40 @@ func getMIFAREReadBlockTestCases() []struct {
 	}
 }
 
+func TestMIFARETrailerBlock(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		sector int
+		want   int
+	}{
+		{sector: 0, want: 3},
+		{sector: 1, want: 7},
+		{sector: 15, want: 63},
+		{sector: 31, want: 127},
+		{sector: 32, want: 143},
+		{sector: 39, want: 255},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, MIFARETrailerBlock(tt.sector), "sector %d", tt.sector)
+	}
+}
+
+func TestIsMIFARETrailerBlock(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		block int
+		want  bool
+	}{
+		{block: 0}, {block: 3, want: true}, {block: 4}, {block: 127, want: true},
+		{block: 128}, {block: 131}, {block: 143, want: true}, {block: 144}, {block: 255, want: true},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, IsMIFARETrailerBlock(tt.block), "block %d", tt.block)
+	}
+}
+
 func TestMIFARETag_ReadBlock(t *testing.T) {
 	t.Parallel()
 
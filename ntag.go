@@ -67,6 +67,9 @@ const (
 	ntagPageCC         = 3 // Capability Container
 	ntagPageStaticLock = 2 // Static lock bytes (page 2, bytes 2-3)
 
+	// CC write access byte denying writes
+	ntagCCReadOnly = 0x0F
+
 	// Configuration pages by NTAG type
 	ntag213DynLock = 0x28 // Dynamic lock bytes (NTAG213)
 	ntag213Cfg0    = 0x29 // Config 0 (NTAG213)
@@ -987,6 +990,92 @@ func (t *NTAGTag) LockPage(page uint8) error {
 	return nil
 }
 
+// MakeReadOnly permanently write protects the NDEF data: it sets the CC
+// write access byte to 0x0F, then the dynamic lock bits covering the user
+// memory above page 15 and finally the static lock bytes. This can't be
+// undone. Changes already on the tag are left out, with dryRun nothing is
+// written.
+func (t *NTAGTag) MakeReadOnly(dryRun bool) ([]LockChange, error) {
+	if t.tagType == NTAGTypeUnknown {
+		if err := t.DetectType(); err != nil {
+			return nil, fmt.Errorf("failed to detect NTAG type: %w", err)
+		}
+	}
+	dynLockPage, dynLockBits, err := t.dynamicLockBits()
+	if err != nil {
+		return nil, err
+	}
+
+	cc, err := t.ReadBlock(ntagPageCC)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read capability container: %w", err)
+	}
+	if cc[0] != 0xE1 {
+		return nil, errors.New("tag is not NDEF formatted: invalid capability container")
+	}
+	dynLock, err := t.ReadBlock(dynLockPage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dynamic lock bytes: %w", err)
+	}
+	staticLock, err := t.ReadBlock(ntagPageStaticLock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read static lock bytes: %w", err)
+	}
+
+	// The CC must be written before its static lock bit is set
+	var changes []LockChange
+	if cc[3] != ntagCCReadOnly {
+		changes = append(changes, LockChange{
+			Description: "capability container: no write access",
+			Data:        []byte{cc[0], cc[1], cc[2], ntagCCReadOnly},
+			Address:     ntagPageCC,
+		})
+	}
+	lockedDyn := []byte{dynLock[0] | dynLockBits[0], dynLock[1] | dynLockBits[1], dynLock[2], dynLock[3]}
+	if !bytes.Equal(lockedDyn, dynLock) {
+		changes = append(changes, LockChange{
+			Description: fmt.Sprintf("dynamic lock bytes: pages 16-%d", dynLockPage-1),
+			Data:        lockedDyn,
+			Address:     uint16(dynLockPage),
+		})
+	}
+	if staticLock[2] != 0xFF || staticLock[3] != 0xFF {
+		changes = append(changes, LockChange{
+			Description: "static lock bytes: pages 3-15",
+			Data:        []byte{staticLock[0], staticLock[1], 0xFF, 0xFF},
+			Address:     ntagPageStaticLock,
+		})
+	}
+
+	if dryRun {
+		return changes, nil
+	}
+	for i, change := range changes {
+		if err := t.WriteBlock(uint8(change.Address), change.Data); err != nil {
+			return changes[:i], fmt.Errorf("failed to lock %s: %w", change.Description, err)
+		}
+	}
+	return changes, nil
+}
+
+// dynamicLockBits returns the dynamic lock page and the lock bits covering
+// the whole user memory above page 15. Each bit locks 2 pages on an NTAG213
+// and 16 pages on an NTAG215/216.
+func (t *NTAGTag) dynamicLockBits() (page uint8, bits []byte, err error) {
+	switch t.tagType {
+	case NTAGType213:
+		return ntag213DynLock, []byte{0xFF, 0x0F}, nil
+	case NTAGType215:
+		return ntag215DynLock, []byte{0xFF, 0x00}, nil
+	case NTAGType216:
+		return ntag216DynLock, []byte{0xFF, 0x3F}, nil
+	case NTAGTypeUnknown:
+		return 0, nil, errors.New("unknown NTAG type for dynamic lock")
+	default:
+		return 0, nil, errors.New("unknown NTAG type for dynamic lock")
+	}
+}
+
 // lockStaticPage locks a page using static lock bytes (pages 3-15)
 func (t *NTAGTag) lockStaticPage(page uint8) error {
 	// Read current lock bytes
@@ -49,7 +49,7 @@ func WithMaxRetries(maxAttempts int) Option {
 			device.config.RetryConfig = DefaultRetryConfig()
 		}
 		device.config.RetryConfig.MaxAttempts = maxAttempts
-		if tr, ok := device.transport.(*TransportWithRetry); ok {
+		if tr := device.retryTransport(); tr != nil {
 			tr.SetRetryConfig(device.config.RetryConfig)
 		}
 		return nil
@@ -63,13 +63,23 @@ func WithRetryBackoff(initialBackoff time.Duration) Option {
 			device.config.RetryConfig = DefaultRetryConfig()
 		}
 		device.config.RetryConfig.InitialBackoff = initialBackoff
-		if tr, ok := device.transport.(*TransportWithRetry); ok {
+		if tr := device.retryTransport(); tr != nil {
 			tr.SetRetryConfig(device.config.RetryConfig)
 		}
 		return nil
 	}
 }
 
+// WithInstrumentation wraps the device transport in an InstrumentedTransport
+// reporting to the given metrics sink and tracer; either may be nil. Options
+// that configure the retry layer keep working when applied afterwards.
+func WithInstrumentation(metrics MetricsSink, tracer Tracer) Option {
+	return func(device *Device) error {
+		device.transport = NewInstrumentedTransport(device.transport, metrics, tracer)
+		return nil
+	}
+}
+
 // NewWithOptions creates a new PN532 device with the given transport and options
 func NewWithOptions(transport Transport, opts ...Option) (*Device, error) {
 	device := &Device{
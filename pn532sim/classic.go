@@ -0,0 +1,450 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package pn532sim
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+)
+
+// MIFARE Classic commands
+const (
+	classicCmdAuthA = 0x60
+	classicCmdAuthB = 0x61
+	classicCmdRead  = 0x30
+	classicCmdWrite = 0xA0
+)
+
+const (
+	classicBlockSize = 16
+	classicKeySize   = 6
+	classicUIDLength = 4
+	classicNoKey     = -1
+)
+
+// Key permissions in the access condition tables
+const (
+	keyA    = 1 << 0
+	keyB    = 1 << 1
+	keyAny  = keyA | keyB
+	keyNone = 0
+)
+
+// DefaultClassicKey is the transport key of factory-fresh MIFARE Classic tags
+var DefaultClassicKey = []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}
+
+// defaultAccessBits is the transport configuration: data blocks readable
+// and writable with key A or B, trailer managed with key A
+var defaultAccessBits = []byte{0xFF, 0x07, 0x80, 0x69}
+
+// dataPermissions are the keys allowed to read and write a data block
+type dataPermissions struct {
+	read, write int
+}
+
+// trailerPermissions are the keys allowed to access sector trailer fields
+type trailerPermissions struct {
+	writeKeyA, readAccess, writeAccess, readKeyB, writeKeyB int
+}
+
+// dataBlockAccess holds the data block permissions, indexed by access
+// condition C1C2C3
+var dataBlockAccess = [8]dataPermissions{
+	{keyAny, keyAny},   // 000
+	{keyAny, keyNone},  // 001
+	{keyAny, keyNone},  // 010
+	{keyB, keyB},       // 011
+	{keyAny, keyB},     // 100
+	{keyB, keyNone},    // 101
+	{keyAny, keyB},     // 110
+	{keyNone, keyNone}, // 111
+}
+
+// trailerAccess holds the sector trailer permissions, indexed by access
+// condition C1C2C3
+var trailerAccess = [8]trailerPermissions{
+	{keyA, keyA, keyNone, keyA, keyA},            // 000
+	{keyA, keyA, keyA, keyA, keyA},               // 001 (transport configuration)
+	{keyNone, keyA, keyNone, keyA, keyNone},      // 010
+	{keyB, keyAny, keyB, keyNone, keyB},          // 011
+	{keyB, keyAny, keyNone, keyNone, keyB},       // 100
+	{keyNone, keyAny, keyB, keyNone, keyNone},    // 101
+	{keyNone, keyAny, keyNone, keyNone, keyNone}, // 110
+	{keyNone, keyAny, keyNone, keyNone, keyNone}, // 111
+}
+
+// Classic is a virtual MIFARE Classic 1K or 4K tag. It emulates per-sector
+// key A/B authentication with the access conditions in each sector trailer.
+// As with Crypto1 on a real tag, a failed authentication or any other error
+// drops the tag back to the idle state, so it must be re-selected with
+// InListPassiveTarget or InSelect before it answers again.
+type Classic struct {
+	uid        []byte
+	blocks     [][]byte
+	mu         sync.Mutex
+	authSector int
+	authKey    int
+	halted     bool
+	sak        byte
+	atq        [2]byte
+}
+
+// NewClassic1K creates a factory-fresh MIFARE Classic 1K tag with default
+// keys. The UID is zero-padded or truncated to 4 bytes.
+func NewClassic1K(uid []byte) *Classic {
+	return newClassic(uid, 64, [2]byte{0x00, 0x04}, 0x08)
+}
+
+// NewClassic4K creates a factory-fresh MIFARE Classic 4K tag with default
+// keys. The UID is zero-padded or truncated to 4 bytes.
+func NewClassic4K(uid []byte) *Classic {
+	return newClassic(uid, 256, [2]byte{0x00, 0x02}, 0x18)
+}
+
+// SetSAK changes the SAK the tag answers selection with, e.g. 0x28 or 0x38
+// for a SmartMX chip emulating a Classic 1K or 4K
+func (c *Classic) SetSAK(sak byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sak = sak
+	c.blocks[0][5] = sak
+}
+
+func newClassic(uid []byte, blockCount int, atq [2]byte, sak byte) *Classic {
+	uid = normalizeUID(uid, classicUIDLength)
+	c := &Classic{
+		uid:        uid,
+		blocks:     make([][]byte, blockCount),
+		atq:        atq,
+		sak:        sak,
+		authSector: classicNoKey,
+		authKey:    classicNoKey,
+	}
+	for i := range c.blocks {
+		c.blocks[i] = make([]byte, classicBlockSize)
+	}
+
+	// Manufacturer block: UID, BCC, SAK and ATQA
+	bcc := uid[0] ^ uid[1] ^ uid[2] ^ uid[3]
+	copy(c.blocks[0], []byte{uid[0], uid[1], uid[2], uid[3], bcc, sak, atq[1], atq[0]})
+
+	for sector := 0; sector < c.sectorCount(); sector++ {
+		c.setTrailer(sector, DefaultClassicKey, defaultAccessBits, DefaultClassicKey)
+	}
+	return c
+}
+
+// UID implements Tag
+func (c *Classic) UID() []byte {
+	return append([]byte(nil), c.uid...)
+}
+
+// Modulation implements Tag
+func (*Classic) Modulation() Modulation {
+	return ModulationISO14443A
+}
+
+// TargetData implements Tag
+func (c *Classic) TargetData() []byte {
+	return typeATargetData(c.atq, c.sak, c.uid)
+}
+
+// Activate implements Tag
+func (c *Classic) Activate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.halted = false
+	c.resetAuth()
+}
+
+// Halt implements Tag
+func (c *Classic) Halt() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.halted = true
+	c.resetAuth()
+}
+
+// Block returns a copy of a block as stored, including both keys
+func (c *Classic) Block(block int) []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if block < 0 || block >= len(c.blocks) {
+		return nil
+	}
+	return append([]byte(nil), c.blocks[block]...)
+}
+
+// SetBlock overwrites a block directly, bypassing authentication and access
+// conditions. It is meant for preparing test fixtures.
+func (c *Classic) SetBlock(block int, data []byte) error {
+	if block < 0 || block >= len(c.blocks) || len(data) != classicBlockSize {
+		return fmt.Errorf("invalid block %d or data length %d", block, len(data))
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	copy(c.blocks[block], data)
+	return nil
+}
+
+// SetSectorKeys replaces both keys of a sector, keeping its access bits
+func (c *Classic) SetSectorKeys(sector int, keyA, keyB []byte) error {
+	if sector < 0 || sector >= c.sectorCount() || len(keyA) != classicKeySize || len(keyB) != classicKeySize {
+		return fmt.Errorf("invalid sector %d or key length", sector)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	trailer := c.blocks[c.trailerBlock(sector)]
+	c.setTrailer(sector, keyA, trailer[6:10], keyB)
+	return nil
+}
+
+// Exchange implements Tag
+func (c *Classic) Exchange(cmd []byte) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.halted || len(cmd) == 0 {
+		return nil, StatusTimeout
+	}
+
+	var res []byte
+	var err error
+	switch cmd[0] {
+	case classicCmdAuthA, classicCmdAuthB:
+		err = c.authenticate(cmd)
+	case classicCmdRead:
+		res, err = c.read(cmd[1:])
+	case classicCmdWrite:
+		err = c.write(cmd[1:])
+	default:
+		// HALT and unknown commands are not answered
+		err = StatusTimeout
+	}
+
+	if err != nil {
+		c.halted = true
+		c.resetAuth()
+	}
+	return res, err
+}
+
+// Transceive implements Tag. The PN532 only runs the Crypto1 handshake
+// for InDataExchange, so only reads within an established session work.
+func (c *Classic) Transceive(frame []byte) ([]byte, error) {
+	if len(frame) > 0 && frame[0] == classicCmdRead {
+		return c.Exchange(frame)
+	}
+	return nil, StatusTimeout
+}
+
+func (c *Classic) authenticate(cmd []byte) error {
+	// Command: auth type, block, key (6 bytes), UID (4 bytes)
+	if len(cmd) < 2+classicKeySize+classicUIDLength || int(cmd[1]) >= len(c.blocks) {
+		return StatusMifareAuth
+	}
+
+	sector := c.sectorOf(int(cmd[1]))
+	trailer := c.blocks[c.trailerBlock(sector)]
+	key := cmd[2 : 2+classicKeySize]
+	uid := cmd[2+classicKeySize : 2+classicKeySize+classicUIDLength]
+
+	stored, keyType := trailer[0:6], keyA
+	if cmd[0] == classicCmdAuthB {
+		stored, keyType = trailer[10:16], keyB
+	}
+	if !bytes.Equal(key, stored) || !bytes.Equal(uid, c.uid) {
+		return StatusMifareAuth
+	}
+
+	c.authSector = sector
+	c.authKey = keyType
+	return nil
+}
+
+func (c *Classic) read(args []byte) ([]byte, error) {
+	if len(args) != 1 {
+		return nil, StatusMifareAuth
+	}
+	block := int(args[0])
+	if !c.authorized(block) {
+		return nil, StatusMifareAuth
+	}
+
+	data := append([]byte(nil), c.blocks[block]...)
+	if block != c.trailerBlock(c.authSector) {
+		if !c.allowed(c.dataAccess(block).read) {
+			return nil, StatusMifareAuth
+		}
+		return data, nil
+	}
+
+	// Key A is never readable; access bits and key B depend on the trailer
+	perms, _ := c.trailerAccess()
+	copy(data[0:6], make([]byte, classicKeySize))
+	if !c.allowed(perms.readAccess) {
+		copy(data[6:10], make([]byte, 4))
+	}
+	if !c.allowed(perms.readKeyB) {
+		copy(data[10:16], make([]byte, classicKeySize))
+	}
+	return data, nil
+}
+
+func (c *Classic) write(args []byte) error {
+	if len(args) != 1+classicBlockSize {
+		return StatusMifareAuth
+	}
+	block, data := int(args[0]), args[1:]
+	if block == 0 || !c.authorized(block) {
+		return StatusMifareAuth
+	}
+
+	if block != c.trailerBlock(c.authSector) {
+		if !c.allowed(c.dataAccess(block).write) {
+			return StatusMifareAuth
+		}
+		copy(c.blocks[block], data)
+		return nil
+	}
+
+	// Each trailer field is only updated if its access condition allows it
+	perms, _ := c.trailerAccess()
+	trailer := c.blocks[block]
+	updated := false
+	for _, field := range []struct {
+		perm       int
+		start, end int
+	}{
+		{perms.writeKeyA, 0, 6},
+		{perms.writeAccess, 6, 10},
+		{perms.writeKeyB, 10, 16},
+	} {
+		if c.allowed(field.perm) {
+			copy(trailer[field.start:field.end], data[field.start:field.end])
+			updated = true
+		}
+	}
+	if !updated {
+		return StatusMifareAuth
+	}
+	return nil
+}
+
+// authorized reports whether a block is in the authenticated sector
+func (c *Classic) authorized(block int) bool {
+	return block < len(c.blocks) && c.authSector != classicNoKey && c.sectorOf(block) == c.authSector
+}
+
+// allowed reports whether the authenticated key grants a permission.
+// Key B grants nothing while the trailer makes it readable.
+func (c *Classic) allowed(perm int) bool {
+	if c.authKey == keyB {
+		if perms, ok := c.trailerAccess(); ok && perms.readKeyB != keyNone {
+			return false
+		}
+	}
+	return perm&c.authKey != 0
+}
+
+// dataAccess returns the permissions for a data block in the authenticated sector
+func (c *Classic) dataAccess(block int) dataPermissions {
+	conditions, ok := decodeAccessBits(c.blocks[c.trailerBlock(c.authSector)][6:9])
+	if !ok {
+		return dataBlockAccess[7]
+	}
+
+	// Large 4K sectors share one access condition between groups of 5 blocks
+	index := block - c.firstBlock(c.authSector)
+	if c.sectorOf(block) >= 32 {
+		index /= 5
+	}
+	return dataBlockAccess[conditions[index]]
+}
+
+// trailerAccess returns the trailer permissions for the authenticated sector.
+// Invalid access bits block the sector, as on a real tag.
+func (c *Classic) trailerAccess() (perms trailerPermissions, ok bool) {
+	conditions, ok := decodeAccessBits(c.blocks[c.trailerBlock(c.authSector)][6:9])
+	if !ok {
+		return perms, false
+	}
+	return trailerAccess[conditions[3]], true
+}
+
+// decodeAccessBits returns the C1C2C3 access condition for each block group,
+// checking them against their inverted copies
+func decodeAccessBits(bits []byte) (conditions [4]byte, ok bool) {
+	c1, c2, c3 := bits[1]>>4, bits[2]&0x0F, bits[2]>>4
+	if ^bits[0]&0x0F != c1 || ^bits[0]>>4 != c2 || ^bits[1]&0x0F != c3 {
+		return conditions, false
+	}
+	for i := range conditions {
+		conditions[i] = (c1>>i&1)<<2 | (c2>>i&1)<<1 | c3>>i&1
+	}
+	return conditions, true
+}
+
+func (c *Classic) resetAuth() {
+	c.authSector = classicNoKey
+	c.authKey = classicNoKey
+}
+
+func (c *Classic) setTrailer(sector int, keyA, accessBits, keyB []byte) {
+	trailer := c.blocks[c.trailerBlock(sector)]
+	copy(trailer[0:6], keyA)
+	copy(trailer[6:10], accessBits)
+	copy(trailer[10:16], keyB)
+}
+
+// sectorCount returns the number of sectors: 16 for 1K, 40 for 4K
+func (c *Classic) sectorCount() int {
+	if len(c.blocks) > 128 {
+		return 32 + (len(c.blocks)-128)/16
+	}
+	return len(c.blocks) / 4
+}
+
+// sectorOf returns the sector containing a block. On 4K tags sectors 32-39
+// have 16 blocks each.
+func (*Classic) sectorOf(block int) int {
+	if block < 128 {
+		return block / 4
+	}
+	return 32 + (block-128)/16
+}
+
+// firstBlock returns the first block of a sector
+func (*Classic) firstBlock(sector int) int {
+	if sector < 32 {
+		return sector * 4
+	}
+	return 128 + (sector-32)*16
+}
+
+// trailerBlock returns the sector trailer block of a sector
+func (c *Classic) trailerBlock(sector int) int {
+	if sector < 32 {
+		return c.firstBlock(sector) + 3
+	}
+	return c.firstBlock(sector) + 15
+}
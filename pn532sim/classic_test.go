@@ -0,0 +1,204 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package pn532sim
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ZaparooProject/go-pn532"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fastMIFAREConfig keeps authentication retries quick. Three attempts are
+// needed so the third one re-selects the tag halted by a failed key.
+func fastMIFAREConfig() *pn532.MIFAREConfig {
+	return &pn532.MIFAREConfig{
+		RetryConfig: &pn532.RetryConfig{
+			MaxAttempts:       3,
+			InitialBackoff:    time.Microsecond,
+			MaxBackoff:        10 * time.Microsecond,
+			BackoffMultiplier: 2.0,
+			RetryTimeout:      time.Second,
+		},
+	}
+}
+
+func authCommand(keyType byte, block int, key, uid []byte) []byte {
+	cmd := []byte{keyType, byte(block)}
+	cmd = append(cmd, key...)
+	return append(cmd, uid...)
+}
+
+// ndefKey is the NFC Forum public key for NDEF sectors
+var ndefKey = []byte{0xD3, 0xF7, 0xD3, 0xF7, 0xD3, 0xF7}
+
+// formatNDEF gives sectors 1-15 the NDEF key and the NFC Forum access
+// conditions: key A reads, key B reads and writes
+func formatNDEF(t *testing.T, tag *Classic) {
+	t.Helper()
+
+	trailer := append(append(append([]byte(nil), ndefKey...), 0x7F, 0x07, 0x88, 0x40), ndefKey...)
+	for sector := 1; sector < 16; sector++ {
+		require.NoError(t, tag.SetBlock(sector*4+3, trailer))
+	}
+}
+
+func TestClassic_DeviceRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tag := NewClassic1K(testUID4)
+	formatNDEF(t, tag)
+
+	device, _ := newTestDevice(t, WithTags(tag))
+	detected, err := device.DetectTag()
+	require.NoError(t, err)
+	require.Equal(t, pn532.TagTypeMIFARE, detected.Type)
+	created, err := device.CreateTag(detected)
+	require.NoError(t, err)
+	mifare, ok := created.(*pn532.MIFARETag)
+	require.True(t, ok)
+	mifare.SetConfig(fastMIFAREConfig())
+
+	require.NoError(t, mifare.WriteText("hello"))
+
+	read, err := mifare.ReadNDEF()
+	require.NoError(t, err)
+	require.Len(t, read.Records, 1)
+	assert.Equal(t, "hello", read.Records[0].Text)
+}
+
+func TestClassic_KeyBReadableCannotWrite(t *testing.T) {
+	t.Parallel()
+
+	// With the transport configuration key B is readable, so it
+	// authenticates but grants no access
+	tag := NewClassic1K(testUID4)
+	_, err := tag.Exchange(authCommand(classicCmdAuthB, 4, DefaultClassicKey, testUID4))
+	require.NoError(t, err)
+	_, err = tag.Exchange(append([]byte{classicCmdWrite, 4}, make([]byte, 16)...))
+	require.ErrorIs(t, err, StatusMifareAuth)
+}
+
+func TestClassic_4KLargeSectors(t *testing.T) {
+	t.Parallel()
+
+	tag := NewClassic4K(testUID4)
+	assert.Equal(t, []byte{0x00, 0x02, 0x18, 0x04}, tag.TargetData()[:4])
+
+	// Sector 32 spans blocks 128-143 with its trailer at block 143
+	_, err := tag.Exchange(authCommand(classicCmdAuthA, 128, DefaultClassicKey, testUID4))
+	require.NoError(t, err)
+	_, err = tag.Exchange(append([]byte{classicCmdWrite, 142}, make([]byte, 16)...))
+	require.NoError(t, err)
+	trailer, err := tag.Exchange([]byte{classicCmdRead, 143})
+	require.NoError(t, err)
+	assert.Equal(t, defaultAccessBits, trailer[6:10])
+}
+
+func TestClassic_Authentication(t *testing.T) {
+	t.Parallel()
+
+	wrongKey := []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06}
+	tests := []struct {
+		name    string
+		cmd     []byte
+		wantErr bool
+	}{
+		{name: "key A", cmd: authCommand(classicCmdAuthA, 4, DefaultClassicKey, testUID4)},
+		{name: "wrong key", cmd: authCommand(classicCmdAuthA, 4, wrongKey, testUID4), wantErr: true},
+		{name: "wrong UID", cmd: authCommand(classicCmdAuthA, 4, DefaultClassicKey, testUID7[:4]), wantErr: true},
+		{name: "out of range", cmd: authCommand(classicCmdAuthB, 64, DefaultClassicKey, testUID4), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tag := NewClassic1K(testUID4)
+			_, err := tag.Exchange(tt.cmd)
+			if tt.wantErr {
+				require.ErrorIs(t, err, StatusMifareAuth)
+				return
+			}
+			require.NoError(t, err)
+
+			_, err = tag.Exchange([]byte{classicCmdRead, 5})
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestClassic_AccessControl(t *testing.T) {
+	t.Parallel()
+
+	tag := NewClassic1K(testUID4)
+
+	// Reading before authentication fails and halts the tag
+	_, err := tag.Exchange([]byte{classicCmdRead, 4})
+	require.ErrorIs(t, err, StatusMifareAuth)
+	_, err = tag.Exchange([]byte{classicCmdRead, 4})
+	require.ErrorIs(t, err, StatusTimeout)
+
+	tag.Activate()
+	_, err = tag.Exchange(authCommand(classicCmdAuthA, 4, DefaultClassicKey, testUID4))
+	require.NoError(t, err)
+
+	// Authentication only covers its own sector
+	_, err = tag.Exchange([]byte{classicCmdRead, 8})
+	require.ErrorIs(t, err, StatusMifareAuth)
+
+	tag.Activate()
+	_, err = tag.Exchange(authCommand(classicCmdAuthA, 7, DefaultClassicKey, testUID4))
+	require.NoError(t, err)
+
+	// Key A never reads back from the trailer
+	trailer, err := tag.Exchange([]byte{classicCmdRead, 7})
+	require.NoError(t, err)
+	assert.Equal(t, make([]byte, 6), trailer[:6])
+	assert.Equal(t, defaultAccessBits, trailer[6:10])
+
+	// The manufacturer block is read-only
+	tag.Activate()
+	_, err = tag.Exchange(authCommand(classicCmdAuthA, 0, DefaultClassicKey, testUID4))
+	require.NoError(t, err)
+	_, err = tag.Exchange(append([]byte{classicCmdWrite, 0}, make([]byte, 16)...))
+	require.ErrorIs(t, err, StatusMifareAuth)
+}
+
+func TestClassic_SectorKeys(t *testing.T) {
+	t.Parallel()
+
+	tag := NewClassic1K(testUID4)
+	keyA := []byte{0xA0, 0xA1, 0xA2, 0xA3, 0xA4, 0xA5}
+	keyB := []byte{0xB0, 0xB1, 0xB2, 0xB3, 0xB4, 0xB5}
+	require.NoError(t, tag.SetSectorKeys(1, keyA, keyB))
+
+	_, err := tag.Exchange(authCommand(classicCmdAuthA, 4, DefaultClassicKey, testUID4))
+	require.ErrorIs(t, err, StatusMifareAuth)
+
+	// A failed authentication halts the tag until it is selected again
+	tag.Activate()
+	_, err = tag.Exchange(authCommand(classicCmdAuthA, 4, keyA, testUID4))
+	require.NoError(t, err)
+	assert.Equal(t, keyB, tag.Block(7)[10:16])
+}
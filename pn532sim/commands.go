@@ -0,0 +1,305 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package pn532sim
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// Firmware reported by GetFirmwareVersion: PN532 v1.6, ISO14443A/B and ISO18092
+var firmwareVersion = []byte{0x32, 0x01, 0x06, 0x07}
+
+// InListPassiveTarget baud rate and modulation types (BrTy)
+const (
+	brTy106TypeA  = 0x00
+	brTy212FeliCa = 0x01
+	brTy424FeliCa = 0x02
+	brTy106TypeB  = 0x03
+	brTy106Jewel  = 0x04
+)
+
+// InAutoPoll target types answered by virtual tags
+var autoPollModulations = map[byte]Modulation{
+	0x00: ModulationISO14443A, // Generic passive 106 kbps
+	0x10: ModulationISO14443A, // MIFARE
+	0x20: ModulationISO14443A, // ISO14443-4A
+	0x01: ModulationFeliCa,    // Generic passive 212 kbps
+	0x02: ModulationFeliCa,    // Generic passive 424 kbps
+	0x11: ModulationFeliCa,    // FeliCa 212 kbps
+	0x12: ModulationFeliCa,    // FeliCa 424 kbps
+}
+
+var (
+	errMissingParameters = errors.New("missing parameters")
+	errInvalidValue      = errors.New("invalid parameter value")
+)
+
+func (*Simulator) diagnose(args []byte) ([]byte, error) {
+	if len(args) == 0 {
+		return nil, syntaxError(cmdDiagnose, errMissingParameters)
+	}
+
+	switch args[0] {
+	case 0x00:
+		// Communication line test echoes the test number and data
+		return append([]byte{0x01}, args...), nil
+	case 0x01, 0x02, 0x04, 0x07:
+		// ROM, RAM, polling and antenna tests all pass
+		return []byte{0x01, 0x00}, nil
+	default:
+		return []byte{0x01}, nil
+	}
+}
+
+func (*Simulator) getFirmwareVersion(_ []byte) ([]byte, error) {
+	return append([]byte{cmdGetFirmwareVersion + 1}, firmwareVersion...), nil
+}
+
+func (s *Simulator) getGeneralStatus(_ []byte) ([]byte, error) {
+	field := byte(0x00)
+	if s.rfOn {
+		field = 0x01
+	}
+
+	res := []byte{cmdGetGeneralStatus + 1, s.lastError, field, byte(len(s.targets))}
+	for _, t := range s.targets {
+		br, modType := byte(0x00), byte(0x00)
+		if t.tag.Modulation() == ModulationFeliCa {
+			br, modType = 0x01, 0x10
+		}
+		res = append(res, t.number, br, br, modType)
+	}
+	// SAM status
+	return append(res, 0x00), nil
+}
+
+func (s *Simulator) setSerialBaudRate(args []byte) ([]byte, error) {
+	if len(args) == 0 {
+		return nil, syntaxError(cmdSetSerialBaudRate, errMissingParameters)
+	}
+	if int(args[0]) >= len(serialBaudRates) {
+		return nil, syntaxError(cmdSetSerialBaudRate, fmt.Errorf("%w: BR 0x%02X", errInvalidValue, args[0]))
+	}
+
+	// The response still goes out at the old rate; the host switches after it
+	s.baudRate = serialBaudRates[args[0]]
+	return []byte{cmdSetSerialBaudRate + 1}, nil
+}
+
+func (*Simulator) samConfiguration(args []byte) ([]byte, error) {
+	if len(args) == 0 {
+		return nil, syntaxError(cmdSAMConfiguration, errMissingParameters)
+	}
+	if args[0] < 0x01 || args[0] > 0x04 {
+		return nil, syntaxError(cmdSAMConfiguration, fmt.Errorf("%w: mode 0x%02X", errInvalidValue, args[0]))
+	}
+	return []byte{cmdSAMConfiguration + 1}, nil
+}
+
+func (s *Simulator) powerDown(args []byte) ([]byte, error) {
+	if len(args) == 0 {
+		return nil, syntaxError(cmdPowerDown, errMissingParameters)
+	}
+
+	s.releaseTargets()
+	s.rfOn = false
+	s.poweredDown = true
+	return []byte{cmdPowerDown + 1, 0x00}, nil
+}
+
+func (s *Simulator) rfConfiguration(args []byte) ([]byte, error) {
+	if len(args) < 2 {
+		return nil, syntaxError(cmdRFConfiguration, errMissingParameters)
+	}
+
+	// CfgItem 0x01 is the RF field; bit 1 switches it on
+	if args[0] == 0x01 {
+		s.rfOn = args[1]&0x02 != 0
+		if !s.rfOn {
+			s.releaseTargets()
+		}
+	}
+	return []byte{cmdRFConfiguration + 1}, nil
+}
+
+func (s *Simulator) inDataExchange(args []byte) ([]byte, error) {
+	if len(args) == 0 {
+		return nil, syntaxError(cmdInDataExchange, errMissingParameters)
+	}
+
+	// Bit 6 of Tg is the MI (more information) flag
+	t := s.findTarget(args[0] & 0x3F)
+	if t == nil {
+		s.lastError = byte(StatusWrongContext)
+		return []byte{cmdInDataExchange + 1, byte(StatusWrongContext)}, nil
+	}
+	return s.exchange(t, cmdInDataExchange+1, args[1:], false), nil
+}
+
+func (s *Simulator) inCommunicateThru(args []byte) ([]byte, error) {
+	if len(s.targets) == 0 {
+		s.lastError = byte(StatusWrongContext)
+		return []byte{cmdInCommunicateThru + 1, byte(StatusWrongContext)}, nil
+	}
+	return s.exchange(s.targets[0], cmdInCommunicateThru+1, args, true), nil
+}
+
+func (s *Simulator) inSelect(args []byte) ([]byte, error) {
+	if len(args) == 0 {
+		return nil, syntaxError(cmdInSelect, errMissingParameters)
+	}
+
+	t := s.findTarget(args[0])
+	switch {
+	case t == nil:
+		return []byte{cmdInSelect + 1, byte(StatusWrongContext)}, nil
+	case t.gone:
+		return []byte{cmdInSelect + 1, byte(StatusTimeout)}, nil
+	default:
+		t.tag.Activate()
+		return []byte{cmdInSelect + 1, 0x00}, nil
+	}
+}
+
+func (s *Simulator) inDeselect(args []byte) ([]byte, error) {
+	if len(args) == 0 {
+		return nil, syntaxError(cmdInDeselect, errMissingParameters)
+	}
+
+	for _, t := range s.targets {
+		if (args[0] == 0x00 || t.number == args[0]) && !t.gone {
+			t.tag.Halt()
+		}
+	}
+	return []byte{cmdInDeselect + 1, 0x00}, nil
+}
+
+func (s *Simulator) inRelease(args []byte) ([]byte, error) {
+	if len(args) == 0 {
+		return nil, syntaxError(cmdInRelease, errMissingParameters)
+	}
+
+	if args[0] == 0x00 {
+		s.releaseTargets()
+		return []byte{cmdInRelease + 1, 0x00}, nil
+	}
+
+	for i, t := range s.targets {
+		if t.number != args[0] {
+			continue
+		}
+		if !t.gone {
+			t.tag.Halt()
+		}
+		s.targets = append(s.targets[:i], s.targets[i+1:]...)
+		return []byte{cmdInRelease + 1, 0x00}, nil
+	}
+	return []byte{cmdInRelease + 1, byte(StatusWrongContext)}, nil
+}
+
+func (s *Simulator) inListPassiveTarget(args []byte) ([]byte, error) {
+	if len(args) < 2 {
+		return nil, syntaxError(cmdInListPassiveTarget, errMissingParameters)
+	}
+	maxTg, brTy, initiatorData := args[0], args[1], args[2:]
+	if maxTg < 1 || maxTg > maxTargets || brTy > brTy106Jewel {
+		return nil, syntaxError(cmdInListPassiveTarget,
+			fmt.Errorf("%w: MaxTg %d, BrTy 0x%02X", errInvalidValue, maxTg, brTy))
+	}
+
+	var targets []*target
+	switch brTy {
+	case brTy106TypeA:
+		targets = s.activate(ModulationISO14443A, int(maxTg), matchUID(initiatorData))
+	case brTy212FeliCa, brTy424FeliCa:
+		targets = s.activate(ModulationFeliCa, int(maxTg), matchSystemCode(initiatorData))
+	default:
+		// Type B and Jewel targets are not emulated
+		targets = s.activate(ModulationISO14443A, 0, nil)
+	}
+
+	res := []byte{cmdInListPassiveTarget + 1, byte(len(targets))}
+	for _, t := range targets {
+		res = append(res, t.number)
+		res = append(res, t.tag.TargetData()...)
+	}
+	return res, nil
+}
+
+func (s *Simulator) inAutoPoll(args []byte) ([]byte, error) {
+	if len(args) < 3 {
+		return nil, syntaxError(cmdInAutoPoll, errMissingParameters)
+	}
+	period, types := args[1], args[2:]
+	if period < 1 || period > 15 || len(types) > 15 {
+		return nil, syntaxError(cmdInAutoPoll, fmt.Errorf("%w: period %d, %d types", errInvalidValue, period, len(types)))
+	}
+
+	// Poll each requested type in order until a target answers, like the
+	// PN532 does; the first matching type is reported for the target
+	for _, pollType := range types {
+		modulation, ok := autoPollModulations[pollType]
+		if !ok {
+			continue
+		}
+		targets := s.activate(modulation, maxTargets, nil)
+		if len(targets) == 0 {
+			continue
+		}
+
+		res := []byte{cmdInAutoPoll + 1, byte(len(targets))}
+		for _, t := range targets {
+			data := t.tag.TargetData()
+			res = append(res, pollType, byte(len(data)+1), t.number)
+			res = append(res, data...)
+		}
+		return res, nil
+	}
+
+	return []byte{cmdInAutoPoll + 1, 0x00}, nil
+}
+
+// matchUID filters Type A tags by the UID given as InListPassiveTarget
+// initiator data. Data that is not a valid UID length is ignored.
+func matchUID(initiatorData []byte) func(Tag) bool {
+	switch len(initiatorData) {
+	case 4, 7, 10:
+		return func(tag Tag) bool {
+			return bytes.Equal(tag.UID(), initiatorData)
+		}
+	default:
+		return nil
+	}
+}
+
+// matchSystemCode filters FeliCa tags by the system code in the polling
+// payload given as InListPassiveTarget initiator data
+func matchSystemCode(initiatorData []byte) func(Tag) bool {
+	if len(initiatorData) < 3 || initiatorData[0] != feliCaCmdPolling {
+		return nil
+	}
+	return func(tag Tag) bool {
+		felica, ok := tag.(*FeliCa)
+		return !ok || felica.matchesSystemCode(initiatorData[1], initiatorData[2])
+	}
+}
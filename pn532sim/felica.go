@@ -0,0 +1,318 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package pn532sim
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+)
+
+// FeliCa commands
+const (
+	feliCaCmdPolling         = 0x00
+	feliCaCmdRequestService  = 0x02
+	feliCaCmdRequestResponse = 0x04
+	feliCaCmdRead            = 0x06
+	feliCaCmdWrite           = 0x08
+)
+
+const (
+	feliCaIDLength     = 8
+	feliCaBlockSize    = 16
+	feliCaDataBlocks   = 13 // NDEF data blocks after the attribute information block
+	feliCaMaxReadBlock = 4  // Nbr: blocks per Read Without Encryption
+	feliCaMaxWrite     = 1  // Nbw: blocks per Write Without Encryption
+	feliCaSystemNDEF   = 0x12FC
+	feliCaServiceRead  = 0x000B // NDEF read-only service
+	feliCaServiceWrite = 0x0009 // NDEF read/write service
+)
+
+// FeliCa status flag 2 error codes
+const (
+	feliCaErrServiceCount = 0xA1
+	feliCaErrBlockCount   = 0xA2
+	feliCaErrServiceCode  = 0xA6
+	feliCaErrBlockNumber  = 0xA8
+)
+
+// feliCaDefaultPMm is the manufacture parameter reported in polling responses
+var feliCaDefaultPMm = []byte{0x00, 0xF1, 0x00, 0x00, 0x00, 0x01, 0x43, 0x00}
+
+// FeliCa is a virtual FeliCa tag formatted as an NFC Forum Type 3 tag with
+// system code 0x12FC. It emulates Polling, Request Service, Request Response
+// and Read/Write Without Encryption on the NDEF services 0x000B and 0x0009.
+// Block 0 holds the attribute information block (AIB) of an empty NDEF
+// message.
+type FeliCa struct {
+	idm        []byte
+	pmm        []byte
+	blocks     [][]byte
+	mu         sync.Mutex
+	systemCode uint16
+}
+
+// NewFeliCa creates a virtual FeliCa tag with an empty NDEF message.
+// The IDm is zero-padded or truncated to 8 bytes.
+func NewFeliCa(idm []byte) *FeliCa {
+	f := &FeliCa{
+		idm:        normalizeUID(idm, feliCaIDLength),
+		pmm:        append([]byte(nil), feliCaDefaultPMm...),
+		blocks:     make([][]byte, 1+feliCaDataBlocks),
+		systemCode: feliCaSystemNDEF,
+	}
+	for i := range f.blocks {
+		f.blocks[i] = make([]byte, feliCaBlockSize)
+	}
+
+	// AIB: version 1.0, Nbr, Nbw, Nmaxb, WriteF off, RWFlag read/write, Ln 0
+	aib := f.blocks[0]
+	copy(aib, []byte{0x10, feliCaMaxReadBlock, feliCaMaxWrite, 0x00, feliCaDataBlocks})
+	aib[10] = 0x01
+	var sum uint16
+	for _, b := range aib[:14] {
+		sum += uint16(b)
+	}
+	aib[14], aib[15] = byte(sum>>8), byte(sum)
+
+	return f
+}
+
+// UID implements Tag and returns the IDm
+func (f *FeliCa) UID() []byte {
+	return append([]byte(nil), f.idm...)
+}
+
+// Modulation implements Tag
+func (*FeliCa) Modulation() Modulation {
+	return ModulationFeliCa
+}
+
+// TargetData implements Tag. It is the polling response prefixed with its
+// length: response code, IDm, PMm and system code.
+func (f *FeliCa) TargetData() []byte {
+	res := f.pollingResponse(true)
+	return append([]byte{byte(len(res) + 1)}, res...)
+}
+
+// Activate implements Tag
+func (*FeliCa) Activate() {}
+
+// Halt implements Tag
+func (*FeliCa) Halt() {}
+
+// Block returns a copy of a block
+func (f *FeliCa) Block(block int) []byte {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if block < 0 || block >= len(f.blocks) {
+		return nil
+	}
+	return append([]byte(nil), f.blocks[block]...)
+}
+
+// SetBlock overwrites a block directly. It is meant for preparing test fixtures.
+func (f *FeliCa) SetBlock(block int, data []byte) error {
+	if block < 0 || block >= len(f.blocks) || len(data) != feliCaBlockSize {
+		return fmt.Errorf("invalid block %d or data length %d", block, len(data))
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	copy(f.blocks[block], data)
+	return nil
+}
+
+// Exchange implements Tag. Commands are FeliCa frames without the length byte.
+func (f *FeliCa) Exchange(cmd []byte) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(cmd) == 0 {
+		return nil, StatusTimeout
+	}
+	if cmd[0] == feliCaCmdPolling {
+		if len(cmd) < 5 || !f.matchesSystemCode(cmd[1], cmd[2]) {
+			return nil, StatusTimeout
+		}
+		return f.pollingResponse(cmd[3] == 0x01), nil
+	}
+
+	// All other commands are addressed to the IDm; other cards stay silent
+	if len(cmd) < 1+feliCaIDLength || !bytes.Equal(cmd[1:1+feliCaIDLength], f.idm) {
+		return nil, StatusTimeout
+	}
+	args := cmd[1+feliCaIDLength:]
+
+	switch cmd[0] {
+	case feliCaCmdRequestResponse:
+		return f.withHeader(feliCaCmdRequestResponse, 0x00), nil
+	case feliCaCmdRequestService:
+		return f.requestService(args)
+	case feliCaCmdRead:
+		return f.read(args), nil
+	case feliCaCmdWrite:
+		return f.write(args), nil
+	default:
+		return nil, StatusTimeout
+	}
+}
+
+// Transceive implements Tag. Raw FeliCa frames carry a leading length byte.
+func (f *FeliCa) Transceive(frame []byte) ([]byte, error) {
+	if len(frame) < 2 || int(frame[0]) != len(frame) {
+		return nil, StatusTimeout
+	}
+	res, err := f.Exchange(frame[1:])
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{byte(len(res) + 1)}, res...), nil
+}
+
+// matchesSystemCode checks a polled system code; 0xFF bytes are wildcards
+func (f *FeliCa) matchesSystemCode(hi, lo byte) bool {
+	return (hi == 0xFF || hi == byte(f.systemCode>>8)) && (lo == 0xFF || lo == byte(f.systemCode))
+}
+
+func (f *FeliCa) pollingResponse(withSystemCode bool) []byte {
+	res := make([]byte, 0, 1+2*feliCaIDLength+2)
+	res = append(res, feliCaCmdPolling+1)
+	res = append(res, f.idm...)
+	res = append(res, f.pmm...)
+	if withSystemCode {
+		res = append(res, byte(f.systemCode>>8), byte(f.systemCode))
+	}
+	return res
+}
+
+// withHeader builds a response: response code, IDm and the given data
+func (f *FeliCa) withHeader(cmd byte, data ...byte) []byte {
+	res := make([]byte, 0, 1+feliCaIDLength+len(data))
+	res = append(res, cmd+1)
+	res = append(res, f.idm...)
+	return append(res, data...)
+}
+
+func (f *FeliCa) requestService(args []byte) ([]byte, error) {
+	if len(args) < 1 || len(args) < 1+2*int(args[0]) {
+		return nil, StatusTimeout
+	}
+
+	data := []byte{args[0]}
+	for i := 0; i < int(args[0]); i++ {
+		code := uint16(args[1+2*i]) | uint16(args[2+2*i])<<8
+		if code == feliCaServiceRead || code == feliCaServiceWrite {
+			data = append(data, 0x00, 0x00)
+		} else {
+			data = append(data, 0xFF, 0xFF)
+		}
+	}
+	return f.withHeader(feliCaCmdRequestService, data...), nil
+}
+
+func (f *FeliCa) read(args []byte) []byte {
+	services, blockList, _, errCode := parseBlockRequest(args, feliCaMaxReadBlock)
+	if errCode != 0 {
+		return f.withHeader(feliCaCmdRead, 0xFF, errCode)
+	}
+
+	data := []byte{0x00, 0x00, byte(len(blockList))}
+	for _, element := range blockList {
+		code := services[element.service]
+		if code != feliCaServiceRead && code != feliCaServiceWrite {
+			return f.withHeader(feliCaCmdRead, 0x01, feliCaErrServiceCode)
+		}
+		if int(element.block) >= len(f.blocks) {
+			return f.withHeader(feliCaCmdRead, 0x01, feliCaErrBlockNumber)
+		}
+		data = append(data, f.blocks[element.block]...)
+	}
+	return f.withHeader(feliCaCmdRead, data...)
+}
+
+func (f *FeliCa) write(args []byte) []byte {
+	services, blockList, payload, errCode := parseBlockRequest(args, feliCaMaxWrite)
+	if errCode == 0 && len(payload) != len(blockList)*feliCaBlockSize {
+		errCode = feliCaErrBlockCount
+	}
+	if errCode != 0 {
+		return f.withHeader(feliCaCmdWrite, 0xFF, errCode)
+	}
+
+	for _, element := range blockList {
+		if services[element.service] != feliCaServiceWrite {
+			return f.withHeader(feliCaCmdWrite, 0x01, feliCaErrServiceCode)
+		}
+		if int(element.block) >= len(f.blocks) {
+			return f.withHeader(feliCaCmdWrite, 0x01, feliCaErrBlockNumber)
+		}
+	}
+	for i, element := range blockList {
+		copy(f.blocks[element.block], payload[i*feliCaBlockSize:])
+	}
+	return f.withHeader(feliCaCmdWrite, 0x00, 0x00)
+}
+
+// blockListElement is one entry of a FeliCa block list
+type blockListElement struct {
+	service int
+	block   uint16
+}
+
+// parseBlockRequest parses the service code list and block list of a Read or
+// Write Without Encryption command. It returns the remaining bytes (the write
+// payload) or a status flag 2 error code.
+func parseBlockRequest(args []byte, maxBlocks int) (
+	services []uint16, blockList []blockListElement, rest []byte, errCode byte,
+) {
+	if len(args) < 1 || args[0] == 0 || args[0] > 16 || len(args) < 1+2*int(args[0])+1 {
+		return nil, nil, nil, feliCaErrServiceCount
+	}
+	for i := 0; i < int(args[0]); i++ {
+		services = append(services, uint16(args[1+2*i])|uint16(args[2+2*i])<<8)
+	}
+	args = args[1+2*len(services):]
+
+	count := int(args[0])
+	if count == 0 || count > maxBlocks {
+		return nil, nil, nil, feliCaErrBlockCount
+	}
+	args = args[1:]
+
+	for i := 0; i < count; i++ {
+		// Bit 7 selects a 2-byte element; the low nibble indexes the service list
+		if len(args) < 2 || (args[0]&0x80 == 0 && len(args) < 3) {
+			return nil, nil, nil, feliCaErrBlockNumber
+		}
+		element := blockListElement{service: int(args[0] & 0x0F), block: uint16(args[1])}
+		if args[0]&0x80 != 0 {
+			args = args[2:]
+		} else {
+			element.block |= uint16(args[2]) << 8
+			args = args[3:]
+		}
+		if element.service >= len(services) {
+			return nil, nil, nil, feliCaErrServiceCode
+		}
+		blockList = append(blockList, element)
+	}
+	return services, blockList, args, 0
+}
@@ -0,0 +1,196 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package pn532sim
+
+import (
+	"testing"
+
+	"github.com/ZaparooProject/go-pn532"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testIDm = []byte{0x01, 0x2E, 0x4C, 0x7A, 0x12, 0x34, 0x56, 0x78}
+
+// feliCaCommand builds a FeliCa command addressed to testIDm
+func feliCaCommand(code byte, args ...byte) []byte {
+	cmd := append([]byte{code}, testIDm...)
+	return append(cmd, args...)
+}
+
+func TestFeliCa_InListPassiveTarget(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		payload []byte
+		want    int
+	}{
+		{name: "NDEF system code", payload: []byte{0x00, 0x12, 0xFC, 0x01, 0x00}, want: 1},
+		{name: "wildcard", payload: []byte{0x00, 0xFF, 0xFF, 0x00, 0x00}, want: 1},
+		{name: "other system code", payload: []byte{0x00, 0x00, 0x03, 0x00, 0x00}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sim, err := New(WithTags(NewFeliCa(testIDm), NewNTAG213(testUID7)))
+			require.NoError(t, err)
+
+			res, err := sim.SendCommand(cmdInListPassiveTarget, append([]byte{0x01, brTy212FeliCa}, tt.payload...))
+			require.NoError(t, err)
+			require.Equal(t, byte(tt.want), res[1])
+			if tt.want == 0 {
+				return
+			}
+			// Tg, POL_RES length, response code, IDm, PMm, system code
+			assert.Equal(t, []byte{0x01, 0x14, 0x01}, res[2:5])
+			assert.Equal(t, testIDm, res[5:13])
+			assert.Equal(t, []byte{0x12, 0xFC}, res[21:23])
+		})
+	}
+}
+
+func TestFeliCa_ReadWrite(t *testing.T) {
+	t.Parallel()
+
+	device, _ := newTestDevice(t, WithTags(NewFeliCa(testIDm)))
+
+	results, err := device.InAutoPoll(1, 1, []pn532.AutoPollTarget{pn532.AutoPollFeliCa212})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	// Read the attribute information block through the NDEF read service
+	res, err := device.SendDataExchange(feliCaCommand(feliCaCmdRead, 0x01, 0x0B, 0x00, 0x01, 0x80, 0x00))
+	require.NoError(t, err)
+	require.Len(t, res, 1+8+2+1+16)
+	assert.Equal(t, byte(0x07), res[0])
+	assert.Equal(t, []byte{0x00, 0x00, 0x01}, res[9:12])
+	assert.Equal(t, byte(0x10), res[12], "AIB version")
+
+	data := []byte{0xD1, 0x01, 0x04, 0x54, 0x02, 0x65, 0x6E, 0x68, 0x00, 0, 0, 0, 0, 0, 0, 0}
+	write := feliCaCommand(feliCaCmdWrite, 0x01, 0x09, 0x00, 0x01, 0x80, 0x01)
+	res, err = device.SendDataExchange(append(write, data...))
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x00, 0x00}, res[9:11])
+
+	// Raw frames carry their own length byte
+	read := feliCaCommand(feliCaCmdRead, 0x01, 0x0B, 0x00, 0x01, 0x80, 0x01)
+	res, err = device.SendRawCommand(append([]byte{byte(len(read) + 1)}, read...))
+	require.NoError(t, err)
+	assert.Equal(t, byte(len(res)), res[0])
+	assert.Equal(t, data, res[13:])
+}
+
+func TestFeliCa_StatusFlags(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		cmd  []byte
+		want []byte
+	}{
+		{
+			name: "write to read-only service",
+			cmd:  feliCaCommand(feliCaCmdWrite, append([]byte{0x01, 0x0B, 0x00, 0x01, 0x80, 0x01}, make([]byte, 16)...)...),
+			want: []byte{0x01, feliCaErrServiceCode},
+		},
+		{
+			name: "block out of range",
+			cmd:  feliCaCommand(feliCaCmdRead, 0x01, 0x0B, 0x00, 0x01, 0x80, 0x20),
+			want: []byte{0x01, feliCaErrBlockNumber},
+		},
+		{
+			name: "too many blocks",
+			cmd:  feliCaCommand(feliCaCmdRead, 0x01, 0x0B, 0x00, 0x05, 0x80, 0, 0x80, 1, 0x80, 2, 0x80, 3, 0x80, 4),
+			want: []byte{0xFF, feliCaErrBlockCount},
+		},
+		{
+			name: "unknown service",
+			cmd:  feliCaCommand(feliCaCmdRead, 0x01, 0x4B, 0x10, 0x01, 0x80, 0x00),
+			want: []byte{0x01, feliCaErrServiceCode},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tag := NewFeliCa(testIDm)
+			res, err := tag.Exchange(tt.cmd)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, res[9:11])
+		})
+	}
+}
+
+func TestFeliCa_OtherIDmIgnored(t *testing.T) {
+	t.Parallel()
+
+	tag := NewFeliCa(testIDm)
+	cmd := append([]byte{feliCaCmdRequestResponse}, make([]byte, 8)...)
+	_, err := tag.Exchange(cmd)
+	require.ErrorIs(t, err, StatusTimeout)
+
+	res, err := tag.Exchange(feliCaCommand(feliCaCmdRequestService, 0x02, 0x09, 0x00, 0x0F, 0x10))
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x02, 0x00, 0x00, 0xFF, 0xFF}, res[9:])
+}
+
+func TestFeliCa_FeliCaTagNDEF(t *testing.T) {
+	t.Parallel()
+
+	sim := NewFeliCa(testIDm)
+	device, _ := newTestDevice(t, WithTags(sim))
+
+	results, err := device.InAutoPoll(1, 1, []pn532.AutoPollTarget{pn532.AutoPollFeliCa212})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	tag, err := pn532.NewFeliCaTag(device, results[0].TargetData)
+	require.NoError(t, err)
+	assert.Equal(t, testIDm, tag.GetIDm())
+	assert.Equal(t, uint16(0x12FC), tag.GetSystemCode())
+
+	info, err := tag.ReadAttributeInfo()
+	require.NoError(t, err)
+	assert.Equal(t, uint16(feliCaDataBlocks), info.MaxBlocks)
+	assert.Equal(t, byte(feliCaMaxReadBlock), info.BlocksPerRead)
+	assert.False(t, info.ReadOnly)
+	assert.Zero(t, info.NDEFLength)
+
+	require.NoError(t, tag.WriteText("hello felica"))
+	msg, err := tag.ReadNDEF()
+	require.NoError(t, err)
+	require.Len(t, msg.Records, 1)
+	assert.Equal(t, "hello felica", msg.Records[0].Text)
+
+	info, err = tag.ReadAttributeInfo()
+	require.NoError(t, err)
+	assert.NotZero(t, info.NDEFLength)
+
+	// A read-only AIB refuses NDEF writes
+	info.ReadOnly = true
+	require.NoError(t, tag.WriteAttributeInfo(info))
+	assert.Equal(t, byte(0x00), sim.Block(0)[10], "RWFlag")
+	require.Error(t, tag.WriteText("rejected"))
+}
@@ -0,0 +1,358 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package pn532sim
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// NTAG21x commands
+const (
+	ntagCmdGetVersion = 0x60
+	ntagCmdRead       = 0x30
+	ntagCmdFastRead   = 0x3A
+	ntagCmdWrite      = 0xA2
+	ntagCmdReadSig    = 0x3C
+	ntagCmdPwdAuth    = 0x1B
+	ntagCmdHalt       = 0x50
+)
+
+const (
+	ntagPageSize  = 4
+	ntagUIDLength = 7
+	ntagNAK       = 0x00 // NAK: invalid argument
+)
+
+// errNAK is returned internally when the tag answers with a NAK
+var errNAK = errors.New("NAK")
+
+// ntagSpec describes the memory layout of an NTAG21x variant
+type ntagSpec struct {
+	name        string
+	totalPages  int
+	ccSize      byte // CC byte 2: data area size / 8
+	storageSize byte // GET_VERSION storage size byte
+	lockPages   int  // pages locked by each dynamic lock bit
+}
+
+var (
+	ntag213Spec = ntagSpec{name: "NTAG213", totalPages: 45, ccSize: 0x12, storageSize: 0x0F, lockPages: 2}
+	ntag215Spec = ntagSpec{name: "NTAG215", totalPages: 135, ccSize: 0x3E, storageSize: 0x11, lockPages: 16}
+	ntag216Spec = ntagSpec{name: "NTAG216", totalPages: 231, ccSize: 0x6D, storageSize: 0x13, lockPages: 16}
+)
+
+// Configuration page offsets from the end of memory
+func (s ntagSpec) dynamicLockPage() int { return s.totalPages - 5 }
+func (s ntagSpec) cfg0Page() int        { return s.totalPages - 4 }
+func (s ntagSpec) cfg1Page() int        { return s.totalPages - 3 }
+func (s ntagSpec) pwdPage() int         { return s.totalPages - 2 }
+func (s ntagSpec) packPage() int        { return s.totalPages - 1 }
+
+// NTAG is a virtual NXP NTAG213/215/216 (NFC Forum Type 2) tag. It emulates
+// READ, FAST_READ, WRITE, GET_VERSION, READ_SIG and PWD_AUTH, static and
+// dynamic lock bits and password protection. Like a real tag it returns to
+// the idle state after a NAK and must be re-selected before it answers again.
+type NTAG struct {
+	uid           []byte
+	memory        []byte
+	spec          ntagSpec
+	mu            sync.Mutex
+	authenticated bool
+	halted        bool
+}
+
+// NewNTAG213 creates a factory-fresh NTAG213 with an empty NDEF message.
+// The UID is zero-padded or truncated to 7 bytes.
+func NewNTAG213(uid []byte) *NTAG {
+	return newNTAG(ntag213Spec, uid)
+}
+
+// NewNTAG215 creates a factory-fresh NTAG215 with an empty NDEF message.
+// The UID is zero-padded or truncated to 7 bytes.
+func NewNTAG215(uid []byte) *NTAG {
+	return newNTAG(ntag215Spec, uid)
+}
+
+// NewNTAG216 creates a factory-fresh NTAG216 with an empty NDEF message.
+// The UID is zero-padded or truncated to 7 bytes.
+func NewNTAG216(uid []byte) *NTAG {
+	return newNTAG(ntag216Spec, uid)
+}
+
+func newNTAG(spec ntagSpec, uid []byte) *NTAG {
+	uid = normalizeUID(uid, ntagUIDLength)
+	t := &NTAG{
+		uid:    uid,
+		spec:   spec,
+		memory: make([]byte, spec.totalPages*ntagPageSize),
+	}
+
+	// Pages 0-2: UID with check bytes, internal byte and static lock bytes
+	bcc0 := 0x88 ^ uid[0] ^ uid[1] ^ uid[2]
+	bcc1 := uid[3] ^ uid[4] ^ uid[5] ^ uid[6]
+	copy(t.memory[0:], []byte{uid[0], uid[1], uid[2], bcc0, uid[3], uid[4], uid[5], uid[6], bcc1, 0x48, 0x00, 0x00})
+
+	// Page 3: capability container; page 4: empty NDEF TLV and terminator
+	copy(t.page(3), []byte{0xE1, 0x10, spec.ccSize, 0x00})
+	copy(t.page(4), []byte{0x03, 0x00, 0xFE, 0x00})
+
+	copy(t.page(spec.dynamicLockPage()), []byte{0x00, 0x00, 0x00, 0xBD})
+	copy(t.page(spec.cfg0Page()), []byte{0x04, 0x00, 0x00, 0xFF}) // AUTH0 = 0xFF: protection off
+	copy(t.page(spec.cfg1Page()), []byte{0x00, 0x05, 0x00, 0x00})
+	copy(t.page(spec.pwdPage()), []byte{0xFF, 0xFF, 0xFF, 0xFF})
+
+	return t
+}
+
+// Name returns the NTAG variant name, e.g. "NTAG215"
+func (t *NTAG) Name() string {
+	return t.spec.name
+}
+
+// UID implements Tag
+func (t *NTAG) UID() []byte {
+	return append([]byte(nil), t.uid...)
+}
+
+// Modulation implements Tag
+func (*NTAG) Modulation() Modulation {
+	return ModulationISO14443A
+}
+
+// TargetData implements Tag
+func (t *NTAG) TargetData() []byte {
+	return typeATargetData([2]byte{0x00, 0x44}, 0x00, t.uid)
+}
+
+// Activate implements Tag
+func (t *NTAG) Activate() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.halted = false
+	t.authenticated = false
+}
+
+// Halt implements Tag
+func (t *NTAG) Halt() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.halted = true
+	t.authenticated = false
+}
+
+// Memory returns a copy of the tag's full memory, including the password
+// and PACK pages that cannot be read over the air
+func (t *NTAG) Memory() []byte {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]byte(nil), t.memory...)
+}
+
+// SetPage overwrites a 4-byte page directly, bypassing lock bits and
+// password protection. It is meant for preparing test fixtures.
+func (t *NTAG) SetPage(page int, data []byte) error {
+	if page < 0 || page >= t.spec.totalPages || len(data) != ntagPageSize {
+		return fmt.Errorf("invalid page %d or data length %d", page, len(data))
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	copy(t.page(page), data)
+	return nil
+}
+
+// Exchange implements Tag. A NAK is reported as StatusMifareAuth, matching
+// what the PN532 returns for InDataExchange.
+func (t *NTAG) Exchange(cmd []byte) ([]byte, error) {
+	res, err := t.handle(cmd)
+	if errors.Is(err, errNAK) {
+		return nil, StatusMifareAuth
+	}
+	return res, err
+}
+
+// Transceive implements Tag. A NAK is returned as its 4-bit value.
+func (t *NTAG) Transceive(frame []byte) ([]byte, error) {
+	res, err := t.handle(frame)
+	if errors.Is(err, errNAK) {
+		return []byte{ntagNAK}, nil
+	}
+	return res, err
+}
+
+func (t *NTAG) handle(cmd []byte) ([]byte, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.halted || len(cmd) == 0 {
+		return nil, StatusTimeout
+	}
+
+	var res []byte
+	var err error
+	switch cmd[0] {
+	case ntagCmdRead:
+		res, err = t.read(cmd[1:])
+	case ntagCmdFastRead:
+		res, err = t.fastRead(cmd[1:])
+	case ntagCmdWrite:
+		err = t.write(cmd[1:])
+	case ntagCmdGetVersion:
+		res = []byte{0x00, 0x04, 0x04, 0x02, 0x01, 0x00, t.spec.storageSize, 0x03}
+	case ntagCmdReadSig:
+		res = make([]byte, 32)
+	case ntagCmdPwdAuth:
+		res, err = t.pwdAuth(cmd[1:])
+	case ntagCmdHalt:
+		t.halted = true
+		return nil, StatusTimeout
+	default:
+		err = errNAK
+	}
+
+	// A NAK sends the tag back to the idle state
+	if errors.Is(err, errNAK) {
+		t.halted = true
+		t.authenticated = false
+	}
+	return res, err
+}
+
+func (t *NTAG) read(args []byte) ([]byte, error) {
+	if len(args) != 1 || int(args[0]) >= t.spec.totalPages || t.readProtected(int(args[0])) {
+		return nil, errNAK
+	}
+
+	// READ returns four pages, rolling over to page 0 at the end of memory
+	res := make([]byte, 0, 4*ntagPageSize)
+	for i := 0; i < 4; i++ {
+		res = append(res, t.readablePage((int(args[0])+i)%t.spec.totalPages)...)
+	}
+	return res, nil
+}
+
+func (t *NTAG) fastRead(args []byte) ([]byte, error) {
+	if len(args) != 2 || args[0] > args[1] || int(args[1]) >= t.spec.totalPages {
+		return nil, errNAK
+	}
+
+	res := make([]byte, 0, (int(args[1])-int(args[0])+1)*ntagPageSize)
+	for page := int(args[0]); page <= int(args[1]); page++ {
+		if t.readProtected(page) {
+			return nil, errNAK
+		}
+		res = append(res, t.readablePage(page)...)
+	}
+	return res, nil
+}
+
+func (t *NTAG) write(args []byte) error {
+	if len(args) != 1+ntagPageSize {
+		return errNAK
+	}
+	page, data := int(args[0]), args[1:]
+	if page < 2 || page >= t.spec.totalPages || t.writeProtected(page) || t.locked(page) {
+		return errNAK
+	}
+
+	switch page {
+	case 2:
+		// Only the static lock bytes are writable, and bits can only be set
+		t.page(2)[2] |= data[2]
+		t.page(2)[3] |= data[3]
+	case 3:
+		// The capability container is one-time programmable
+		for i := range data {
+			t.page(3)[i] |= data[i]
+		}
+	case t.spec.dynamicLockPage():
+		for i := 0; i < 3; i++ {
+			t.page(page)[i] |= data[i]
+		}
+	default:
+		copy(t.page(page), data)
+	}
+	return nil
+}
+
+func (t *NTAG) pwdAuth(args []byte) ([]byte, error) {
+	if len(args) != 4 {
+		return nil, errNAK
+	}
+	pwd := t.page(t.spec.pwdPage())
+	for i := range pwd {
+		if pwd[i] != args[i] {
+			return nil, errNAK
+		}
+	}
+	t.authenticated = true
+	return append([]byte(nil), t.page(t.spec.packPage())[:2]...), nil
+}
+
+// page returns the slice of memory backing a page
+func (t *NTAG) page(page int) []byte {
+	return t.memory[page*ntagPageSize : (page+1)*ntagPageSize]
+}
+
+// readablePage returns a page as seen over the air; PWD and PACK read as zero
+func (t *NTAG) readablePage(page int) []byte {
+	if page == t.spec.pwdPage() || page == t.spec.packPage() || t.readProtected(page) {
+		return make([]byte, ntagPageSize)
+	}
+	return append([]byte(nil), t.page(page)...)
+}
+
+// auth0 returns the first page protected by the password
+func (t *NTAG) auth0() int {
+	return int(t.page(t.spec.cfg0Page())[3])
+}
+
+// readProtected reports whether reading a page requires PWD_AUTH
+func (t *NTAG) readProtected(page int) bool {
+	prot := t.page(t.spec.cfg1Page())[0]&0x80 != 0
+	return prot && !t.authenticated && page >= t.auth0()
+}
+
+// writeProtected reports whether writing a page requires PWD_AUTH
+func (t *NTAG) writeProtected(page int) bool {
+	return !t.authenticated && page >= t.auth0()
+}
+
+// locked reports whether a page is locked by the static or dynamic lock bits
+func (t *NTAG) locked(page int) bool {
+	lock0, lock1 := t.page(2)[2], t.page(2)[3]
+	switch {
+	case page == 3:
+		return lock0&0x08 != 0
+	case page >= 4 && page <= 7:
+		return lock0&(1<<page) != 0
+	case page >= 8 && page <= 15:
+		return lock1&(1<<(page-8)) != 0
+	case page >= 16 && page < t.spec.dynamicLockPage():
+		dynamic := t.page(t.spec.dynamicLockPage())
+		bit := (page - 16) / t.spec.lockPages
+		bits := uint16(dynamic[0]) | uint16(dynamic[1])<<8
+		return bit < 16 && bits&(1<<bit) != 0
+	default:
+		return false
+	}
+}
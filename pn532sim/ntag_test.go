@@ -0,0 +1,189 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package pn532sim
+
+import (
+	"testing"
+
+	"github.com/ZaparooProject/go-pn532"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNTAG_DeviceRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		tag        *NTAG
+		name       string
+		totalPages uint8
+	}{
+		{name: "NTAG213", tag: NewNTAG213(testUID7), totalPages: 45},
+		{name: "NTAG215", tag: NewNTAG215(testUID7), totalPages: 135},
+		{name: "NTAG216", tag: NewNTAG216(testUID7), totalPages: 231},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			device, _ := newTestDevice(t, WithTags(tt.tag))
+			detected, err := device.DetectTag()
+			require.NoError(t, err)
+			tag, err := device.CreateTag(detected)
+			require.NoError(t, err)
+			ntag, ok := tag.(*pn532.NTAGTag)
+			require.True(t, ok)
+
+			require.NoError(t, ntag.DetectType())
+			assert.Equal(t, tt.totalPages, ntag.GetTotalPages())
+
+			msg := &pn532.NDEFMessage{Records: []pn532.NDEFRecord{
+				{Type: pn532.NDEFTypeURI, URI: "https://zaparoo.org"},
+			}}
+			require.NoError(t, ntag.WriteNDEF(msg))
+
+			read, err := ntag.ReadNDEF()
+			require.NoError(t, err)
+			require.Len(t, read.Records, 1)
+			assert.Equal(t, "https://zaparoo.org", read.Records[0].URI)
+		})
+	}
+}
+
+func TestNTAG_FactoryMemory(t *testing.T) {
+	t.Parallel()
+
+	tag := NewNTAG213(testUID7)
+	res, err := tag.Exchange([]byte{ntagCmdRead, 0x00})
+	require.NoError(t, err)
+
+	// UID with both check bytes, internal byte and capability container
+	bcc0 := byte(0x88) ^ testUID7[0] ^ testUID7[1] ^ testUID7[2]
+	bcc1 := testUID7[3] ^ testUID7[4] ^ testUID7[5] ^ testUID7[6]
+	assert.Equal(t, []byte{testUID7[0], testUID7[1], testUID7[2], bcc0}, res[0:4])
+	assert.Equal(t, []byte{testUID7[3], testUID7[4], testUID7[5], testUID7[6]}, res[4:8])
+	assert.Equal(t, []byte{bcc1, 0x48, 0x00, 0x00}, res[8:12])
+	assert.Equal(t, []byte{0xE1, 0x10, 0x12, 0x00}, res[12:16])
+
+	version, err := tag.Exchange([]byte{ntagCmdGetVersion})
+	require.NoError(t, err)
+	assert.Equal(t, byte(0x0F), version[6])
+}
+
+func TestNTAG_WriteRules(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		setup   func(*NTAG)
+		name    string
+		write   []byte
+		want    []byte
+		page    int
+		wantNAK bool
+	}{
+		{
+			name:    "UID pages are read-only",
+			write:   []byte{ntagCmdWrite, 0x00, 0x01, 0x02, 0x03, 0x04},
+			wantNAK: true,
+		},
+		{
+			name:  "capability container is OTP",
+			write: []byte{ntagCmdWrite, 0x03, 0x00, 0x01, 0x00, 0x0F},
+			page:  3,
+			want:  []byte{0xE1, 0x11, 0x12, 0x0F},
+		},
+		{
+			name: "static lock bit blocks writes",
+			setup: func(n *NTAG) {
+				// Lock bit L4 covers page 4
+				_, _ = n.Exchange([]byte{ntagCmdWrite, 0x02, 0x00, 0x00, 0x10, 0x00})
+			},
+			write:   []byte{ntagCmdWrite, 0x04, 0x01, 0x02, 0x03, 0x04},
+			wantNAK: true,
+		},
+		{
+			name:  "user memory is writable",
+			write: []byte{ntagCmdWrite, 0x10, 0x01, 0x02, 0x03, 0x04},
+			page:  0x10,
+			want:  []byte{0x01, 0x02, 0x03, 0x04},
+		},
+		{
+			name:    "out of range page",
+			write:   []byte{ntagCmdWrite, 45, 0x01, 0x02, 0x03, 0x04},
+			wantNAK: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tag := NewNTAG213(testUID7)
+			if tt.setup != nil {
+				tt.setup(tag)
+			}
+
+			_, err := tag.Exchange(tt.write)
+			if tt.wantNAK {
+				require.ErrorIs(t, err, StatusMifareAuth)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, tag.Memory()[tt.page*4:tt.page*4+4])
+		})
+	}
+}
+
+func TestNTAG_PasswordProtection(t *testing.T) {
+	t.Parallel()
+
+	tag := NewNTAG213(testUID7)
+	password := []byte{0x12, 0x34, 0x56, 0x78}
+	require.NoError(t, tag.SetPage(43, password))
+	require.NoError(t, tag.SetPage(44, []byte{0xAB, 0xCD, 0x00, 0x00}))
+	require.NoError(t, tag.SetPage(42, []byte{0x80, 0x05, 0x00, 0x00})) // PROT: reads need auth too
+	require.NoError(t, tag.SetPage(41, []byte{0x04, 0x00, 0x00, 0x10})) // AUTH0 = page 16
+
+	_, err := tag.Exchange([]byte{ntagCmdRead, 0x10})
+	require.ErrorIs(t, err, StatusMifareAuth, "protected read")
+
+	// The NAK halted the tag until it is activated again
+	_, err = tag.Exchange([]byte{ntagCmdRead, 0x04})
+	require.ErrorIs(t, err, StatusTimeout)
+	tag.Activate()
+
+	_, err = tag.Exchange([]byte{ntagCmdPwdAuth, 0x00, 0x00, 0x00, 0x00})
+	require.ErrorIs(t, err, StatusMifareAuth, "wrong password")
+	tag.Activate()
+
+	pack, err := tag.Exchange(append([]byte{ntagCmdPwdAuth}, password...))
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0xAB, 0xCD}, pack)
+
+	_, err = tag.Exchange([]byte{ntagCmdWrite, 0x10, 0x01, 0x02, 0x03, 0x04})
+	require.NoError(t, err)
+
+	// The password itself never reads back
+	res, err := tag.Exchange([]byte{ntagCmdRead, 43})
+	require.NoError(t, err)
+	assert.Equal(t, make([]byte, 8), res[:8])
+}
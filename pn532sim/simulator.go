@@ -0,0 +1,490 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+// Package pn532sim emulates a PN532 NFC controller and the tags in its RF
+// field. A Simulator implements pn532.Transport, so a pn532.Device can be
+// driven end to end without reader hardware.
+package pn532sim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ZaparooProject/go-pn532"
+)
+
+// PN532 command codes handled by the simulator
+const (
+	cmdDiagnose            = 0x00
+	cmdGetFirmwareVersion  = 0x02
+	cmdGetGeneralStatus    = 0x04
+	cmdSetSerialBaudRate   = 0x10
+	cmdSAMConfiguration    = 0x14
+	cmdPowerDown           = 0x16
+	cmdRFConfiguration     = 0x32
+	cmdInDataExchange      = 0x40
+	cmdInCommunicateThru   = 0x42
+	cmdInDeselect          = 0x44
+	cmdInListPassiveTarget = 0x4A
+	cmdInRelease           = 0x52
+	cmdInSelect            = 0x54
+	cmdInAutoPoll          = 0x60
+)
+
+// portName identifies the simulator in transport errors
+const portName = "pn532sim"
+
+// maxTargets is the number of targets the PN532 can handle at once
+const maxTargets = 2
+
+// serialBaudRates maps the BR parameter of SetSerialBaudRate to baud rates
+var serialBaudRates = []int{
+	pn532.SerialBaudRate9600,
+	pn532.SerialBaudRate19200,
+	pn532.SerialBaudRate38400,
+	pn532.SerialBaudRate57600,
+	pn532.SerialBaudRate115200,
+	pn532.SerialBaudRate230400,
+	pn532.SerialBaudRate460800,
+	pn532.SerialBaudRate921600,
+	pn532.SerialBaudRate1288000,
+}
+
+var (
+	// ErrSyntax is returned when the emulated PN532 would answer a command
+	// with a syntax error frame, e.g. for unknown commands or bad parameters
+	ErrSyntax = errors.New("PN532 syntax error")
+
+	// ErrUnsupportedCommand is returned for command codes the simulator does not emulate
+	ErrUnsupportedCommand = errors.New("unsupported command")
+)
+
+// placement tracks a tag in the RF field and when it enters and leaves
+type placement struct {
+	tag       Tag
+	insertAt  time.Time
+	removeAt  time.Time
+	pollsLeft int
+}
+
+// present reports whether the tag is in the field at the given time
+func (p *placement) present(now time.Time) bool {
+	return !now.Before(p.insertAt)
+}
+
+// expired reports whether the tag has left the field at the given time
+func (p *placement) expired(now time.Time) bool {
+	return (!p.removeAt.IsZero() && !now.Before(p.removeAt)) || p.pollsLeft == 0
+}
+
+// target is a tag activated by InListPassiveTarget or InAutoPoll
+type target struct {
+	tag    Tag
+	number byte
+	gone   bool
+}
+
+// commandHandler emulates a single PN532 command
+type commandHandler func(s *Simulator, args []byte) ([]byte, error)
+
+var handlers = map[byte]commandHandler{
+	cmdDiagnose:            (*Simulator).diagnose,
+	cmdGetFirmwareVersion:  (*Simulator).getFirmwareVersion,
+	cmdGetGeneralStatus:    (*Simulator).getGeneralStatus,
+	cmdSetSerialBaudRate:   (*Simulator).setSerialBaudRate,
+	cmdSAMConfiguration:    (*Simulator).samConfiguration,
+	cmdPowerDown:           (*Simulator).powerDown,
+	cmdRFConfiguration:     (*Simulator).rfConfiguration,
+	cmdInDataExchange:      (*Simulator).inDataExchange,
+	cmdInCommunicateThru:   (*Simulator).inCommunicateThru,
+	cmdInDeselect:          (*Simulator).inDeselect,
+	cmdInListPassiveTarget: (*Simulator).inListPassiveTarget,
+	cmdInRelease:           (*Simulator).inRelease,
+	cmdInSelect:            (*Simulator).inSelect,
+	cmdInAutoPoll:          (*Simulator).inAutoPoll,
+}
+
+// Simulator emulates a PN532 connected through a host interface.
+// It implements pn532.Transport and is safe for concurrent use, so tags
+// can be placed and removed while a Device is polling.
+type Simulator struct {
+	now          func() time.Time
+	commandCount map[byte]int
+	field        []*placement
+	targets      []*target
+	delay        time.Duration
+	baudRate     int
+	hostBaudRate int
+	mu           sync.Mutex
+	lastError    byte
+	rfOn         bool
+	poweredDown  bool
+	closed       bool
+}
+
+// Option is a functional option for configuring a Simulator
+type Option func(*Simulator) error
+
+// WithClock sets the time source used to schedule tag insertion and removal
+func WithClock(now func() time.Time) Option {
+	return func(s *Simulator) error {
+		if now == nil {
+			return fmt.Errorf("%w: clock must not be nil", pn532.ErrInvalidParameter)
+		}
+		s.now = now
+		return nil
+	}
+}
+
+// WithCommandDelay makes every command take at least the given duration,
+// emulating the latency of a real host interface
+func WithCommandDelay(delay time.Duration) Option {
+	return func(s *Simulator) error {
+		if delay < 0 {
+			return fmt.Errorf("%w: negative command delay %v", pn532.ErrInvalidParameter, delay)
+		}
+		s.delay = delay
+		return nil
+	}
+}
+
+// WithTags places the given tags in the field when the simulator is created
+func WithTags(tags ...Tag) Option {
+	return func(s *Simulator) error {
+		for _, tag := range tags {
+			s.PlaceTag(tag)
+		}
+		return nil
+	}
+}
+
+// New creates a new simulated PN532 with an empty RF field
+func New(opts ...Option) (*Simulator, error) {
+	s := &Simulator{
+		now:          time.Now,
+		commandCount: make(map[byte]int),
+		baudRate:     pn532.DefaultSerialBaudRate,
+		hostBaudRate: pn532.DefaultSerialBaudRate,
+	}
+
+	for _, opt := range opts {
+		if err := opt(s); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// PlacementOption configures when a placed tag enters and leaves the field
+type PlacementOption func(*placement)
+
+// InsertAfter delays the tag's arrival in the field by the given duration
+func InsertAfter(delay time.Duration) PlacementOption {
+	return func(p *placement) {
+		p.insertAt = p.insertAt.Add(delay)
+	}
+}
+
+// RemoveAfter removes the tag from the field once the given duration has
+// passed since it arrived
+func RemoveAfter(dwell time.Duration) PlacementOption {
+	return func(p *placement) {
+		p.removeAt = p.insertAt.Add(dwell)
+	}
+}
+
+// RemoveAfterPolls removes the tag from the field after it has been reported
+// by the given number of InListPassiveTarget or InAutoPoll commands
+func RemoveAfterPolls(polls int) PlacementOption {
+	return func(p *placement) {
+		p.pollsLeft = polls
+	}
+}
+
+// PlaceTag puts a tag into the RF field. Placing a tag that is already in
+// the field replaces its schedule.
+func (s *Simulator) PlaceTag(tag Tag, opts ...PlacementOption) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.removePlacement(tag)
+	p := &placement{tag: tag, insertAt: s.now(), pollsLeft: -1}
+	for _, opt := range opts {
+		opt(p)
+	}
+	// RemoveAfter is relative to arrival, so apply it after InsertAfter
+	if !p.removeAt.IsZero() && p.removeAt.Before(p.insertAt) {
+		p.removeAt = p.insertAt
+	}
+	s.field = append(s.field, p)
+}
+
+// RemoveTag takes a tag out of the RF field. Commands addressed to it fail
+// with a timeout until the target is released.
+func (s *Simulator) RemoveTag(tag Tag) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.removePlacement(tag) {
+		s.markGone(tag)
+	}
+}
+
+// RemoveAllTags takes every tag out of the RF field
+func (s *Simulator) RemoveAllTags() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, p := range s.field {
+		s.markGone(p.tag)
+	}
+	s.field = nil
+}
+
+// Tags returns the tags currently in the RF field
+func (s *Simulator) Tags() []Tag {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.refreshField()
+	tags := make([]Tag, 0, len(s.field))
+	for _, p := range s.field {
+		if p.present(s.now()) {
+			tags = append(tags, p.tag)
+		}
+	}
+	return tags
+}
+
+// CommandCount returns how many times the given command code was received
+func (s *Simulator) CommandCount(cmd byte) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.commandCount[cmd]
+}
+
+// SendCommand implements pn532.Transport
+func (s *Simulator) SendCommand(cmd byte, args []byte) ([]byte, error) {
+	return s.SendCommandWithContext(context.Background(), cmd, args)
+}
+
+// SendCommandWithContext implements pn532.Transport
+func (s *Simulator) SendCommandWithContext(ctx context.Context, cmd byte, args []byte) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if s.delay > 0 {
+		select {
+		case <-time.After(s.delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil, pn532.NewTransportError("SendCommand", portName, pn532.ErrTransportClosed, pn532.ErrorTypePermanent)
+	}
+
+	// The PN532 cannot decode frames sent at the wrong baud rate
+	if s.hostBaudRate != s.baudRate {
+		return nil, pn532.NewNoACKError("SendCommand", portName)
+	}
+
+	// Any host interface activity wakes the PN532 from power down
+	s.poweredDown = false
+	s.commandCount[cmd]++
+	s.refreshField()
+
+	handler, ok := handlers[cmd]
+	if !ok {
+		return nil, syntaxError(cmd, fmt.Errorf("%w 0x%02X", ErrUnsupportedCommand, cmd))
+	}
+
+	return handler(s, args)
+}
+
+// Close implements pn532.Transport
+func (s *Simulator) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+// SetTimeout implements pn532.Transport
+func (*Simulator) SetTimeout(_ time.Duration) error {
+	return nil
+}
+
+// IsConnected implements pn532.Transport
+func (s *Simulator) IsConnected() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return !s.closed
+}
+
+// Type implements pn532.Transport
+func (*Simulator) Type() pn532.TransportType {
+	return pn532.TransportMock
+}
+
+// HasCapability implements pn532.TransportCapabilityChecker
+func (*Simulator) HasCapability(capability pn532.TransportCapability) bool {
+	return capability == pn532.CapabilityAutoPollNative
+}
+
+// BaudRate returns the host-side baud rate
+func (s *Simulator) BaudRate() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.hostBaudRate
+}
+
+// SetBaudRate switches the host-side baud rate. Commands fail with a
+// missing ACK until it matches the rate negotiated with SetSerialBaudRate.
+func (s *Simulator) SetBaudRate(baudRate int) error {
+	if !pn532.IsValidSerialBaudRate(baudRate) {
+		return fmt.Errorf("%w: unsupported baud rate %d", pn532.ErrInvalidParameter, baudRate)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hostBaudRate = baudRate
+	return nil
+}
+
+// syntaxError builds the error returned when the PN532 rejects a command
+func syntaxError(cmd byte, err error) error {
+	return pn532.NewTransportError(fmt.Sprintf("command 0x%02X", cmd), portName,
+		fmt.Errorf("%w: %w", ErrSyntax, err), pn532.ErrorTypePermanent)
+}
+
+// removePlacement drops a tag from the field and reports whether it was there
+func (s *Simulator) removePlacement(tag Tag) bool {
+	for i, p := range s.field {
+		if p.tag == tag {
+			s.field = append(s.field[:i], s.field[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// markGone halts a tag that left the field and marks its target as gone
+func (s *Simulator) markGone(tag Tag) {
+	tag.Halt()
+	for _, t := range s.targets {
+		if t.tag == tag {
+			t.gone = true
+		}
+	}
+}
+
+// refreshField removes tags whose scheduled stay in the field has ended
+func (s *Simulator) refreshField() {
+	now := s.now()
+	kept := s.field[:0]
+	for _, p := range s.field {
+		if p.present(now) && p.expired(now) {
+			s.markGone(p.tag)
+			continue
+		}
+		kept = append(kept, p)
+	}
+	s.field = kept
+}
+
+// releaseTargets halts and forgets all activated targets
+func (s *Simulator) releaseTargets() {
+	for _, t := range s.targets {
+		if !t.gone {
+			t.tag.Halt()
+		}
+	}
+	s.targets = nil
+}
+
+// findTarget returns the activated target with the given logical number
+func (s *Simulator) findTarget(number byte) *target {
+	for _, t := range s.targets {
+		if t.number == number {
+			return t
+		}
+	}
+	return nil
+}
+
+// activate lists the tags in the field answering to the given modulation,
+// activates up to limit of them and makes them the current targets
+func (s *Simulator) activate(modulation Modulation, limit int, match func(Tag) bool) []*target {
+	s.releaseTargets()
+	s.rfOn = true
+
+	now := s.now()
+	for _, p := range s.field {
+		if len(s.targets) >= limit {
+			break
+		}
+		if !p.present(now) || p.tag.Modulation() != modulation || (match != nil && !match(p.tag)) {
+			continue
+		}
+		p.tag.Activate()
+		if p.pollsLeft > 0 {
+			p.pollsLeft--
+		}
+		s.targets = append(s.targets, &target{tag: p.tag, number: byte(len(s.targets) + 1)})
+	}
+	return s.targets
+}
+
+// exchange forwards a command to a target and builds the status response
+func (s *Simulator) exchange(t *target, responseCode byte, data []byte, raw bool) []byte {
+	if t.gone {
+		s.lastError = byte(StatusTimeout)
+		return []byte{responseCode, byte(StatusTimeout)}
+	}
+
+	var res []byte
+	var err error
+	if raw {
+		res, err = t.tag.Transceive(data)
+	} else {
+		res, err = t.tag.Exchange(data)
+	}
+	if err != nil {
+		status := StatusTimeout
+		var tagStatus Status
+		if errors.As(err, &tagStatus) {
+			status = tagStatus
+		}
+		s.lastError = byte(status)
+		return []byte{responseCode, byte(status)}
+	}
+
+	s.lastError = 0x00
+	return append([]byte{responseCode, 0x00}, res...)
+}
@@ -0,0 +1,321 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package pn532sim
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ZaparooProject/go-pn532"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testUID7 = []byte{0x04, 0x12, 0x34, 0x56, 0x78, 0x9A, 0xBC}
+	testUID4 = []byte{0xDE, 0xAD, 0xBE, 0xEF}
+)
+
+// fakeClock is a manually advanced time source for placement schedules
+type fakeClock struct {
+	now time.Time
+	mu  sync.Mutex
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// newTestDevice creates an initialized device backed by a simulator
+func newTestDevice(t *testing.T, opts ...Option) (*pn532.Device, *Simulator) {
+	t.Helper()
+
+	sim, err := New(opts...)
+	require.NoError(t, err)
+	device, err := pn532.New(sim)
+	require.NoError(t, err)
+	require.NoError(t, device.Init())
+	t.Cleanup(func() { _ = device.Close() })
+
+	return device, sim
+}
+
+func TestNew_InvalidOptions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		opt  Option
+		name string
+	}{
+		{name: "nil clock", opt: WithClock(nil)},
+		{name: "negative delay", opt: WithCommandDelay(-time.Second)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := New(tt.opt)
+			require.ErrorIs(t, err, pn532.ErrInvalidParameter)
+		})
+	}
+}
+
+func TestSimulator_Init(t *testing.T) {
+	t.Parallel()
+
+	device, sim := newTestDevice(t)
+
+	version, err := device.GetFirmwareVersion()
+	require.NoError(t, err)
+	assert.Equal(t, "1.6", version.Version)
+	assert.Equal(t, 1, sim.CommandCount(cmdSAMConfiguration))
+}
+
+func TestSimulator_UnsupportedCommand(t *testing.T) {
+	t.Parallel()
+
+	sim, err := New()
+	require.NoError(t, err)
+
+	_, err = sim.SendCommand(0x7F, nil)
+	require.ErrorIs(t, err, ErrSyntax)
+	require.ErrorIs(t, err, ErrUnsupportedCommand)
+	assert.False(t, pn532.IsRetryable(err))
+}
+
+func TestSimulator_InvalidParameters(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		args []byte
+		cmd  byte
+	}{
+		{name: "SAM mode", cmd: cmdSAMConfiguration, args: []byte{0x05}},
+		{name: "too many targets", cmd: cmdInListPassiveTarget, args: []byte{0x03, 0x00}},
+		{name: "unknown BrTy", cmd: cmdInListPassiveTarget, args: []byte{0x01, 0x05}},
+		{name: "poll period", cmd: cmdInAutoPoll, args: []byte{0x01, 0x00, 0x10}},
+		{name: "missing Tg", cmd: cmdInDataExchange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			sim, err := New()
+			require.NoError(t, err)
+
+			_, err = sim.SendCommand(tt.cmd, tt.args)
+			require.ErrorIs(t, err, ErrSyntax)
+		})
+	}
+}
+
+func TestSimulator_Closed(t *testing.T) {
+	t.Parallel()
+
+	sim, err := New()
+	require.NoError(t, err)
+	require.NoError(t, sim.Close())
+	assert.False(t, sim.IsConnected())
+
+	_, err = sim.SendCommand(cmdGetFirmwareVersion, nil)
+	require.ErrorIs(t, err, pn532.ErrTransportClosed)
+}
+
+func TestSimulator_ContextCancelled(t *testing.T) {
+	t.Parallel()
+
+	sim, err := New(WithCommandDelay(time.Second))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err = sim.SendCommandWithContext(ctx, cmdGetFirmwareVersion, nil)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Zero(t, sim.CommandCount(cmdGetFirmwareVersion))
+}
+
+func TestSimulator_DetectTag(t *testing.T) {
+	t.Parallel()
+
+	device, _ := newTestDevice(t, WithTags(NewNTAG213(testUID7)))
+
+	detected, err := device.DetectTag()
+	require.NoError(t, err)
+	assert.Equal(t, pn532.TagTypeNTAG, detected.Type)
+	assert.Equal(t, testUID7, detected.UIDBytes)
+	assert.Equal(t, byte(1), detected.TargetNumber)
+}
+
+func TestSimulator_NoTag(t *testing.T) {
+	t.Parallel()
+
+	device, _ := newTestDevice(t)
+
+	_, err := device.DetectTag()
+	require.ErrorIs(t, err, pn532.ErrNoTagDetected)
+}
+
+func TestSimulator_MultipleTargets(t *testing.T) {
+	t.Parallel()
+
+	device, _ := newTestDevice(t, WithTags(NewNTAG213(testUID7), NewClassic1K(testUID4)))
+
+	tags, err := device.DetectTags(2, 0x00)
+	require.NoError(t, err)
+	require.Len(t, tags, 2)
+	assert.Equal(t, byte(1), tags[0].TargetNumber)
+	assert.Equal(t, byte(2), tags[1].TargetNumber)
+	assert.Equal(t, pn532.TagTypeMIFARE, tags[1].Type)
+}
+
+func TestSimulator_InListPassiveTargetUIDFilter(t *testing.T) {
+	t.Parallel()
+
+	sim, err := New(WithTags(NewNTAG213(testUID7), NewClassic1K(testUID4)))
+	require.NoError(t, err)
+
+	res, err := sim.SendCommand(cmdInListPassiveTarget, append([]byte{0x01, brTy106TypeA}, testUID4...))
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x4B, 0x01, 0x01, 0x00, 0x04, 0x08, 0x04}, res[:7])
+	assert.Equal(t, testUID4, res[7:])
+}
+
+func TestSimulator_PlacementSchedule(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	device, sim := newTestDevice(t, WithClock(clock.Now))
+	sim.PlaceTag(NewNTAG213(testUID7), InsertAfter(time.Second), RemoveAfter(2*time.Second))
+
+	_, err := device.DetectTag()
+	require.ErrorIs(t, err, pn532.ErrNoTagDetected, "tag not yet inserted")
+
+	clock.Advance(time.Second)
+	_, err = device.DetectTag()
+	require.NoError(t, err)
+	assert.Len(t, sim.Tags(), 1)
+
+	clock.Advance(2 * time.Second)
+	_, err = device.DetectTag()
+	require.ErrorIs(t, err, pn532.ErrNoTagDetected, "tag removed after dwell time")
+	assert.Empty(t, sim.Tags())
+}
+
+func TestSimulator_RemoveAfterPolls(t *testing.T) {
+	t.Parallel()
+
+	device, sim := newTestDevice(t)
+	sim.PlaceTag(NewNTAG213(testUID7), RemoveAfterPolls(2))
+
+	for i := 0; i < 2; i++ {
+		_, err := device.DetectTag()
+		require.NoError(t, err)
+	}
+	_, err := device.DetectTag()
+	require.ErrorIs(t, err, pn532.ErrNoTagDetected)
+}
+
+func TestSimulator_RemovedTargetTimesOut(t *testing.T) {
+	t.Parallel()
+
+	tag := NewNTAG213(testUID7)
+	device, sim := newTestDevice(t, WithTags(tag))
+
+	_, err := device.DetectTag()
+	require.NoError(t, err)
+
+	sim.RemoveTag(tag)
+	_, err = device.SendDataExchange([]byte{ntagCmdRead, 0x04})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "data exchange error: 01")
+
+	res, err := sim.SendCommand(cmdGetGeneralStatus, nil)
+	require.NoError(t, err)
+	assert.Equal(t, byte(StatusTimeout), res[1], "last error")
+}
+
+func TestSimulator_InRelease(t *testing.T) {
+	t.Parallel()
+
+	device, sim := newTestDevice(t, WithTags(NewNTAG213(testUID7)))
+
+	_, err := device.DetectTag()
+	require.NoError(t, err)
+
+	res, err := sim.SendCommand(cmdGetGeneralStatus, nil)
+	require.NoError(t, err)
+	assert.Equal(t, byte(1), res[3], "one active target")
+
+	require.NoError(t, device.InRelease(0))
+
+	res, err = sim.SendCommand(cmdGetGeneralStatus, nil)
+	require.NoError(t, err)
+	assert.Equal(t, byte(0), res[3], "targets released")
+
+	_, err = device.SendDataExchange([]byte{ntagCmdRead, 0x04})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "data exchange error: 27")
+}
+
+func TestSimulator_InAutoPoll(t *testing.T) {
+	t.Parallel()
+
+	device, _ := newTestDevice(t, WithTags(NewClassic1K(testUID4)))
+
+	results, err := device.InAutoPoll(1, 1, []pn532.AutoPollTarget{pn532.AutoPollGeneric106kbps})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, pn532.AutoPollGeneric106kbps, results[0].Type)
+	assert.Equal(t, append([]byte{0x01, 0x00, 0x04, 0x08, 0x04}, testUID4...), results[0].TargetData)
+}
+
+func TestSimulator_SerialBaudRate(t *testing.T) {
+	t.Parallel()
+
+	device, sim := newTestDevice(t)
+
+	require.NoError(t, device.SetSerialBaudRate(460800))
+	assert.Equal(t, 460800, sim.BaudRate())
+
+	_, err := device.GetFirmwareVersion()
+	require.NoError(t, err)
+
+	// A host that falls out of step with the chip gets no ACK
+	require.NoError(t, sim.SetBaudRate(pn532.DefaultSerialBaudRate))
+	_, err = sim.SendCommand(cmdGetFirmwareVersion, nil)
+	require.ErrorIs(t, err, pn532.ErrNoACK)
+}
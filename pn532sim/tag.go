@@ -0,0 +1,102 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package pn532sim
+
+import (
+	"fmt"
+)
+
+// Modulation identifies the RF technology a virtual tag answers to
+type Modulation int
+
+const (
+	// ModulationISO14443A is ISO/IEC 14443 Type A at 106 kbps (NTAG, MIFARE)
+	ModulationISO14443A Modulation = iota
+	// ModulationFeliCa is FeliCa at 212 or 424 kbps
+	ModulationFeliCa
+)
+
+// Status is a PN532 error code as reported in the status byte of
+// InDataExchange and InCommunicateThru responses
+type Status byte
+
+// PN532 error codes used by the virtual tags (PN532 user manual, table 6)
+const (
+	// StatusTimeout means the target did not answer
+	StatusTimeout Status = 0x01
+	// StatusCRC means the target answered with a CRC error
+	StatusCRC Status = 0x02
+	// StatusInvalidParameter means the command parameters were invalid
+	StatusInvalidParameter Status = 0x10
+	// StatusMifareAuth is a MIFARE authentication error. The PN532 also
+	// reports a 4-bit NAK from a MIFARE or Type 2 tag with this code.
+	StatusMifareAuth Status = 0x14
+	// StatusWrongContext means the command is not acceptable in the current
+	// context, e.g. the target number is unknown
+	StatusWrongContext Status = 0x27
+)
+
+// Error implements error
+func (s Status) Error() string {
+	return fmt.Sprintf("PN532 status 0x%02X", byte(s))
+}
+
+// Tag is a virtual tag that can be placed in the simulator's RF field.
+// Implementations must be safe for concurrent use.
+type Tag interface {
+	// UID returns the tag identifier (NFCID1 for Type A, IDm for FeliCa)
+	UID() []byte
+
+	// Modulation returns the RF technology the tag answers to
+	Modulation() Modulation
+
+	// TargetData returns the target data the PN532 reports when the tag is
+	// activated, excluding the leading logical target number (Tg)
+	TargetData() []byte
+
+	// Activate is called when the reader activates or re-selects the tag
+	Activate()
+
+	// Halt is called when the reader releases the tag or it leaves the field
+	Halt()
+
+	// Exchange handles a command sent through InDataExchange. Errors that
+	// are not a Status are reported as StatusTimeout.
+	Exchange(cmd []byte) ([]byte, error)
+
+	// Transceive handles a raw frame sent through InCommunicateThru
+	Transceive(frame []byte) ([]byte, error)
+}
+
+// typeATargetData builds ISO14443A target data: SENS_RES, SEL_RES and NFCID1
+func typeATargetData(atq [2]byte, sak byte, uid []byte) []byte {
+	data := make([]byte, 0, 4+len(uid))
+	data = append(data, atq[0], atq[1], sak, byte(len(uid)))
+	return append(data, uid...)
+}
+
+// normalizeUID copies uid into a slice of exactly size bytes, zero-padding
+// short UIDs and truncating long ones
+func normalizeUID(uid []byte, size int) []byte {
+	out := make([]byte, size)
+	copy(out, uid)
+	return out
+}
@@ -20,14 +20,98 @@
 
 package polling
 
-import "time"
+import (
+	"time"
+
+	"github.com/ZaparooProject/go-pn532"
+)
 
 // Config holds polling configuration options
 type Config struct {
+	// LowPower powers the PN532 down between polls while no card is
+	// present, nil polls continuously
+	LowPower *LowPowerConfig
+	// Presence decides card removal by counting missed polls instead of
+	// CardRemovalTimeout, nil uses the removal timer. With MaxTags above one
+	// every UID has its own window, the adaptive miss rate is learned once
+	// for the reader.
+	Presence *PresenceConfig
+	// Strategy finds the tags on each poll, nil picks one from Targets and
+	// TagTypes, by default ListPassiveTargetStrategy
+	Strategy PollStrategy
+	// Supervisor restarts the poll loop after it failed, nil ends polling
+	// with the error
+	Supervisor *SupervisorConfig
+	// Health monitors failing polls and recovers the reader, nil relies on
+	// MaxPollErrors
+	Health *HealthConfig
+	// TagTypes are the tag types the session reports, other tags are
+	// ignored. nil reports every tag. The order is the priority when a poll
+	// finds several tags: single tag mode reports the first tag of the
+	// earliest type, multi-tag mode reports arrivals in type order.
+	TagTypes []pn532.TagType
+	// Targets polls with InAutoPoll for these target types, see
+	// AutoPollStrategy. nil derives the poll command from TagTypes.
+	Targets            []pn532.AutoPollTarget
 	PollInterval       time.Duration
 	CardRemovalTimeout time.Duration
+	// MaxPollErrors is the number of consecutive failed polls after which
+	// Session.Start gives up and returns ErrTooManyPollErrors, e.g. because
+	// the reader was unplugged. Zero keeps polling forever, or uses
+	// DefaultMaxPollErrors with a Supervisor.
+	MaxPollErrors int
+	// MaxTags is how many tags are tracked at once. The PN532 lists at most
+	// two ISO14443A targets. Above one the session tracks a set of present
+	// tags and reports arrivals and departures per UID, OnCardChanged and
+	// ReadNDEF are not used in that mode.
+	MaxTags int
+	// EventBuffer is the capacity of the Session.Events channel, zero uses
+	// DefaultEventBuffer
+	EventBuffer int
+	// ReadTimeout is how long an automatic NDEF read may take before its
+	// EventRead is emitted with a deadline error, zero uses
+	// DefaultReadTimeout. Tag reads can't be interrupted, so the read itself
+	// keeps running: polling is not resumed and no further events are
+	// emitted until it has finished, however long that takes.
+	ReadTimeout time.Duration
+	// EventPolicy decides what happens when the Events channel is full
+	EventPolicy EventPolicy
+	// RandomUIDs decides how tags with a random UID are reported
+	RandomUIDs RandomUIDPolicy
+	// PollCount is how many InAutoPoll cycles a poll may take, zero uses 1
+	PollCount byte
+	// PollPeriod is the InAutoPoll time per target type in units of 150ms,
+	// zero uses 1
+	PollPeriod byte
+	// ReadNDEF makes the session read the NDEF message of every new card
+	// and report it as an EventRead
+	ReadNDEF bool
 }
 
+// RandomUIDPolicy decides how the session reports tags with a random UID,
+// see pn532.UIDInfo. Phones and many ISO14443-4 cards pick a new random UID
+// on every activation.
+type RandomUIDPolicy int
+
+const (
+	// RandomUIDReport reports random UID tags like any other tag, so every
+	// tap of a phone is a new card
+	RandomUIDReport RandomUIDPolicy = iota
+	// RandomUIDIgnore ignores tags with a random UID
+	RandomUIDIgnore
+	// RandomUIDGroup reports every random UID tag with the UID RandomUID,
+	// so a phone on the reader stays the same card. UIDBytes keeps the UID
+	// the tag sent.
+	RandomUIDGroup
+)
+
+// RandomUID is the UID reported for random UID tags with RandomUIDGroup
+const RandomUID = "random"
+
+// DefaultReadTimeout is the automatic NDEF read timeout used when
+// Config.ReadTimeout is not set
+const DefaultReadTimeout = 2 * time.Second
+
 // DefaultConfig returns the default polling configuration
 func DefaultConfig() *Config {
 	return &Config{
@@ -35,3 +119,94 @@ func DefaultConfig() *Config {
 		CardRemovalTimeout: 300 * time.Millisecond,
 	}
 }
+
+// DefaultSleepInterval is the low-power sleep used when
+// LowPowerConfig.SleepInterval is not set
+const DefaultSleepInterval = 500 * time.Millisecond
+
+// LowPowerConfig configures low-power polling. While no card is present the
+// session puts the PN532 into PowerDown after each poll and wakes it again
+// after SleepInterval, so the reader is active for roughly
+// poll time / (poll time + SleepInterval) of the time instead of polling every
+// Config.PollInterval. Once a card is found the session polls at
+// Config.PollInterval until it is removed.
+type LowPowerConfig struct {
+	// SleepInterval is how long the PN532 stays powered down between polls,
+	// zero uses DefaultSleepInterval
+	SleepInterval time.Duration
+	// WakeupSources are the PowerDown wake-up flags (pn532.Wakeup*). Zero
+	// enables the host interfaces, which is how the session wakes the chip.
+	// Adding pn532.WakeupRF also wakes it when an external RF field, e.g. a
+	// phone, comes close.
+	WakeupSources byte
+}
+
+// Presence filter defaults for zero PresenceConfig fields
+const (
+	DefaultPresenceWindow  = 10
+	DefaultMissThreshold   = 3
+	DefaultRedetectHits    = 2
+	DefaultRedetectHoldoff = time.Second
+)
+
+// PresenceConfig replaces the card removal timer with a filter that counts
+// missed polls, for readers whose antenna loses a card now and then.
+// Removal is decided per poll, Config.CardRemovalTimeout is not used in
+// single tag mode.
+type PresenceConfig struct {
+	// Window is the number of recent polls misses are counted in, zero uses
+	// DefaultPresenceWindow
+	Window int
+	// MissThreshold is how many of the last Window polls must miss the card
+	// before it counts as removed, zero uses DefaultMissThreshold
+	MissThreshold int
+	// RedetectHits is how many consecutive polls must see a card that was
+	// just removed before it counts as present again, zero uses
+	// DefaultRedetectHits
+	RedetectHits int
+	// RedetectHoldoff is how long after a removal RedetectHits applies to
+	// the same card, zero uses DefaultRedetectHoldoff
+	RedetectHoldoff time.Duration
+	// Adaptive raises MissThreshold, up to Window, for readers that often
+	// miss a card that is present
+	Adaptive bool
+}
+
+// SupervisorConfig configures the restart of a failed poll loop. Once
+// Config.MaxPollErrors polls in a row have failed the supervisor waits,
+// reinitializes the PN532 and starts polling again. The wait starts at
+// InitialBackoff and doubles with every restart that doesn't lead to a
+// successful poll, up to MaxBackoff.
+type SupervisorConfig struct {
+	// InitialBackoff is the wait before the first restart, zero uses
+	// DefaultInitialBackoff
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between restarts, zero uses DefaultMaxBackoff
+	MaxBackoff time.Duration
+	// MaxRestarts is how many restarts in a row may fail before the session
+	// gives up with ErrRestartsExhausted, zero restarts forever
+	MaxRestarts int
+}
+
+// Health monitor defaults for zero HealthConfig fields
+const (
+	DefaultFailureThreshold = 3
+	DefaultProbeTimeout     = time.Second
+)
+
+// HealthConfig configures reader health monitoring. Once FailureThreshold
+// polls in a row have failed, or right away for a permanent error as
+// categorized by pn532.GetErrorType, the monitor probes the PN532 and runs
+// the next recovery step: RF field reset, re-initialization, then transport
+// reopen. A successful poll marks the reader healthy and starts over with the
+// first step. If polls still fail after the last step the reader is reported
+// dead and Session.Start returns ErrReaderDead. Config.MaxPollErrors is not
+// used with a HealthConfig.
+type HealthConfig struct {
+	// ProbeTimeout bounds the probe and each recovery step, zero uses
+	// DefaultProbeTimeout
+	ProbeTimeout time.Duration
+	// FailureThreshold is how many polls in a row may fail before recovery
+	// starts, zero uses DefaultFailureThreshold
+	FailureThreshold int
+}
@@ -22,6 +22,9 @@ package polling
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -33,28 +36,25 @@ type DeviceCallbacks struct {
 	OnCardDetected func(tag *pn532.DetectedTag) error
 	OnCardRemoved  func()
 	OnCardChanged  func(tag *pn532.DetectedTag) error
+	// OnReaderLost is called when polling stops on a fatal error, see
+	// DeviceActor
+	OnReaderLost func(err error)
 }
 
-// DeviceMetrics tracks operational metrics for DeviceActor
-type DeviceMetrics struct {
-	PollCycles      int64         // Total number of polling cycles
-	PollErrors      int64         // Number of polling errors
-	CardsDetected   int64         // Number of cards detected
-	CallbackErrors  int64         // Number of callback errors
-	LastPollLatency time.Duration // Duration of last polling operation
-}
-
-// DeviceActor minimal implementation to make the test pass
+// DeviceActor runs the polling engine in the background and reports every
+// poll that finds a card through DeviceCallbacks.OnCardDetected. Once no
+// card was seen for Config.CardRemovalTimeout it calls OnCardRemoved.
+// Polling stops when the transport is closed, the device is gone or
+// Config.MaxPollErrors polls failed in a row, and OnReaderLost is called.
 type DeviceActor struct {
 	device    *pn532.Device
 	config    *Config
+	engine    *engine
+	cancel    context.CancelFunc
 	callbacks DeviceCallbacks
-	stopChan  chan struct{}
-	// Atomic counters for metrics
-	pollCycles      int64
-	cardsDetected   int64
-	pollErrors      int64
-	lastPollLatency int64 // in nanoseconds
+	mu        sync.Mutex
+	// cardPresent is only used by the poll loop
+	cardPresent bool
 	// Adaptive polling state
 	currentInterval   int64 // Current polling interval in nanoseconds
 	lastCardDetection int64 // Timestamp of last card detection
@@ -62,74 +62,114 @@ type DeviceActor struct {
 	running int64 // 0 = stopped, 1 = running
 }
 
-// NewDeviceActor creates a new device actor (minimal implementation to pass test)
+// NewDeviceActor creates a new device actor
 func NewDeviceActor(device *pn532.Device, config *Config, callbacks DeviceCallbacks) *DeviceActor {
 	now := time.Now().UnixNano()
 	return &DeviceActor{
 		device:            device,
 		config:            config,
+		engine:            newEngine(device, config),
 		callbacks:         callbacks,
-		stopChan:          make(chan struct{}),
 		currentInterval:   config.PollInterval.Nanoseconds(),
 		lastCardDetection: now,
 	}
 }
 
-// Start minimal implementation to pass test
+// Start starts polling in the background. Polling runs until Stop, it is
+// not bound to ctx.
 func (da *DeviceActor) Start(_ context.Context) error {
 	// Only start if not already running
-	if atomic.CompareAndSwapInt64(&da.running, 0, 1) {
-		// Start continuous polling in a goroutine
-		go da.pollLoop()
+	if !atomic.CompareAndSwapInt64(&da.running, 0, 1) {
+		return nil
 	}
+
+	loopCtx, cancel := context.WithCancel(context.Background())
+	da.mu.Lock()
+	da.cancel = cancel
+	da.mu.Unlock()
+
+	go func() {
+		defer func() {
+			cancel()
+			// Mark as not running when goroutine exits
+			atomic.StoreInt64(&da.running, 0)
+		}()
+		err := da.engine.supervise(loopCtx, da.pollLoop, nil)
+		if err == nil || loopCtx.Err() != nil {
+			return
+		}
+		da.cardGone()
+		if da.callbacks.OnReaderLost != nil {
+			da.callbacks.OnReaderLost(err)
+		}
+	}()
 	return nil
 }
 
-// pollLoop runs continuous polling until stopped
-func (da *DeviceActor) pollLoop() {
+// isFatalPollError reports whether a poll error means the reader is gone
+func isFatalPollError(err error) bool {
+	return errors.Is(err, pn532.ErrTransportClosed) || errors.Is(err, pn532.ErrDeviceNotFound)
+}
+
+// cardGone reports the removal of the present card, if any
+func (da *DeviceActor) cardGone() {
+	if !da.cardPresent {
+		return
+	}
+	da.cardPresent = false
+	if da.callbacks.OnCardRemoved != nil {
+		da.callbacks.OnCardRemoved()
+	}
+}
+
+// pollLoop runs continuous polling until ctx is done or too many polls failed
+func (da *DeviceActor) pollLoop(ctx context.Context) error {
 	ticker := time.NewTicker(da.config.PollInterval)
-	defer func() {
-		ticker.Stop()
-		// Mark as not running when goroutine exits
-		atomic.StoreInt64(&da.running, 0)
-	}()
+	defer ticker.Stop()
 
+	limit := pollErrorLimit(da.config)
+	failures := 0
 	for {
 		select {
 		case <-ticker.C:
-			if da.device == nil || da.callbacks.OnCardDetected == nil {
-				continue
-			}
-
-			start := time.Now()
-			detectedTags, err := da.device.InitiatorListPassiveTargets(1, pn532.TagTypeAny, nil)
-			pollDuration := time.Since(start)
-
-			// Track poll cycle
-			atomic.AddInt64(&da.pollCycles, 1)
-			atomic.StoreInt64(&da.lastPollLatency, pollDuration.Nanoseconds())
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if da.device == nil || da.callbacks.OnCardDetected == nil {
+			continue
+		}
 
-			if err != nil {
-				// Track poll error
-				atomic.AddInt64(&da.pollErrors, 1)
+		start := time.Now()
+		detectedTags, err := da.engine.poll(ctx, 1)
+		switch {
+		case err != nil:
+			if isFatalPollError(err) {
+				return fmt.Errorf("reader lost: %w", err)
 			}
-
-			if err == nil && len(detectedTags) > 0 {
-				// Track card detected and update timestamp
-				atomic.AddInt64(&da.cardsDetected, 1)
-				atomic.StoreInt64(&da.lastCardDetection, start.UnixNano())
-				_ = da.callbacks.OnCardDetected(detectedTags[0])
+			failures++
+			if limit > 0 && failures >= limit {
+				return fmt.Errorf("%w (%d): %w", ErrTooManyPollErrors, failures, err)
 			}
+		case len(detectedTags) > 0:
+			failures = 0
+			da.cardPresent = true
+			atomic.StoreInt64(&da.lastCardDetection, start.UnixNano())
+			if cbErr := da.callbacks.OnCardDetected(detectedTags[0]); cbErr != nil {
+				da.engine.callbackErrors.Add(1)
+			}
+		default:
+			failures = 0
+			lastSeen := time.Unix(0, atomic.LoadInt64(&da.lastCardDetection))
+			if time.Since(lastSeen) >= da.config.CardRemovalTimeout {
+				da.cardGone()
+			}
+		}
 
-			// Adaptive polling: adjust interval based on card presence
-			da.adjustPollInterval()
+		// Adaptive polling: adjust interval based on card presence
+		da.adjustPollInterval()
 
-			// Update ticker with new interval
-			newInterval := time.Duration(atomic.LoadInt64(&da.currentInterval))
-			ticker.Reset(newInterval)
-		case <-da.stopChan:
-			return
-		}
+		// Update ticker with new interval
+		ticker.Reset(time.Duration(atomic.LoadInt64(&da.currentInterval)))
 	}
 }
 
@@ -153,25 +193,20 @@ func (da *DeviceActor) adjustPollInterval() {
 	}
 }
 
-// Stop stops the device actor and waits for the polling goroutine to exit
+// Stop stops the device actor's polling goroutine
 func (da *DeviceActor) Stop(_ context.Context) error {
-	select {
-	case da.stopChan <- struct{}{}:
-		// Successfully signaled stop
-	default:
-		// Channel might be closed or goroutine already stopped
+	da.mu.Lock()
+	defer da.mu.Unlock()
+	if da.cancel != nil {
+		da.cancel()
+		da.cancel = nil
 	}
 	return nil
 }
 
 // GetMetrics returns current operational metrics
 func (da *DeviceActor) GetMetrics() DeviceMetrics {
-	return DeviceMetrics{
-		PollCycles:      atomic.LoadInt64(&da.pollCycles),
-		PollErrors:      atomic.LoadInt64(&da.pollErrors),
-		CardsDetected:   atomic.LoadInt64(&da.cardsDetected),
-		LastPollLatency: time.Duration(atomic.LoadInt64(&da.lastPollLatency)),
-	}
+	return da.engine.metrics()
 }
 
 // GetCurrentPollInterval returns the current adaptive polling interval
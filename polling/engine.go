@@ -0,0 +1,271 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package polling
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/ZaparooProject/go-pn532"
+)
+
+// Supervisor defaults for zero SupervisorConfig fields
+const (
+	DefaultMaxPollErrors  = 5
+	DefaultInitialBackoff = 100 * time.Millisecond
+	DefaultMaxBackoff     = 5 * time.Second
+)
+
+// ErrRestartsExhausted is returned by a supervised session once
+// SupervisorConfig.MaxRestarts restarts in a row have not recovered the reader
+var ErrRestartsExhausted = errors.New("poll loop restarts exhausted")
+
+// DeviceMetrics tracks operational metrics of a polling loop
+type DeviceMetrics struct {
+	PollCycles      int64         // Total number of polling cycles
+	PollErrors      int64         // Number of polling errors
+	CardsDetected   int64         // Number of cards detected
+	CallbackErrors  int64         // Number of callback errors
+	Restarts        int64         // Number of supervisor restarts
+	TimeoutErrors   int64         // Poll errors categorized as timeouts
+	TransientErrors int64         // Poll errors categorized as transient
+	PermanentErrors int64         // Poll errors categorized as permanent
+	RecoverySteps   int64         // Number of health monitor recovery steps
+	Recoveries      int64         // Number of times the reader recovered
+	LastPollLatency time.Duration // Duration of last polling operation
+}
+
+// engine is the polling core shared by Session and DeviceActor. It runs the
+// poll strategy, keeps the metrics and supervises the poll loop.
+type engine struct {
+	device          *pn532.Device
+	strategy        PollStrategy
+	supervisor      *SupervisorConfig
+	tagTypes        []pn532.TagType
+	randomUIDs      RandomUIDPolicy
+	pollCycles      atomic.Int64
+	pollErrors      atomic.Int64
+	cardsDetected   atomic.Int64
+	callbackErrors  atomic.Int64
+	restarts        atomic.Int64
+	timeoutErrors   atomic.Int64
+	transientErrors atomic.Int64
+	permanentErrors atomic.Int64
+	recoverySteps   atomic.Int64
+	recoveries      atomic.Int64
+	lastPollLatency atomic.Int64
+	polledOK        atomic.Bool
+}
+
+// newEngine creates the engine for a device and config
+func newEngine(device *pn532.Device, config *Config) *engine {
+	return &engine{
+		device:     device,
+		strategy:   strategyFor(config),
+		supervisor: config.Supervisor,
+		tagTypes:   config.TagTypes,
+		randomUIDs: config.RandomUIDs,
+	}
+}
+
+// poll runs one poll of the strategy and records it in the metrics
+func (e *engine) poll(ctx context.Context, maxTags int) ([]*pn532.DetectedTag, error) {
+	limit := maxTags
+	if len(e.tagTypes) > 0 || e.randomUIDs == RandomUIDIgnore {
+		// List as many tags as possible so a preferred type isn't crowded out
+		limit = max(maxTags, maxListedTargets)
+	}
+
+	start := time.Now()
+	tags, err := e.strategy.Poll(ctx, e.device, limit)
+	e.pollCycles.Add(1)
+	e.lastPollLatency.Store(int64(time.Since(start)))
+
+	if err != nil {
+		e.pollErrors.Add(1)
+		switch pn532.GetErrorType(err) {
+		case pn532.ErrorTypeTimeout:
+			e.timeoutErrors.Add(1)
+		case pn532.ErrorTypeTransient:
+			e.transientErrors.Add(1)
+		case pn532.ErrorTypePermanent:
+			e.permanentErrors.Add(1)
+		}
+		return nil, err
+	}
+	e.polledOK.Store(true)
+	tags = filterTags(groupRandomUIDs(tags, e.randomUIDs), e.tagTypes)
+	if len(tags) > maxTags {
+		tags = tags[:maxTags]
+	}
+	e.cardsDetected.Add(int64(len(tags)))
+	return tags, nil
+}
+
+// groupRandomUIDs applies a RandomUIDPolicy to the tags of a poll
+func groupRandomUIDs(tags []*pn532.DetectedTag, policy RandomUIDPolicy) []*pn532.DetectedTag {
+	if policy == RandomUIDReport {
+		return tags
+	}
+
+	result := make([]*pn532.DetectedTag, 0, len(tags))
+	for _, tag := range tags {
+		if !tag.UIDInfo().Random {
+			result = append(result, tag)
+			continue
+		}
+		if policy == RandomUIDGroup {
+			grouped := *tag
+			grouped.UID = RandomUID
+			result = append(result, &grouped)
+		}
+	}
+	return result
+}
+
+// filterTags keeps the tags of the given types, ordered by the position of
+// their type. No types keeps every tag.
+func filterTags(tags []*pn532.DetectedTag, types []pn532.TagType) []*pn532.DetectedTag {
+	if len(types) == 0 {
+		return tags
+	}
+	priority := func(tag *pn532.DetectedTag) int {
+		for i, tagType := range types {
+			if tagType == pn532.TagTypeAny || tagType == tag.Type {
+				return i
+			}
+		}
+		return -1
+	}
+
+	filtered := make([]*pn532.DetectedTag, 0, len(tags))
+	for _, tag := range tags {
+		if priority(tag) >= 0 {
+			filtered = append(filtered, tag)
+		}
+	}
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return priority(filtered[i]) < priority(filtered[j])
+	})
+	return filtered
+}
+
+// metrics returns a snapshot of the metrics
+func (e *engine) metrics() DeviceMetrics {
+	return DeviceMetrics{
+		PollCycles:      e.pollCycles.Load(),
+		PollErrors:      e.pollErrors.Load(),
+		CardsDetected:   e.cardsDetected.Load(),
+		CallbackErrors:  e.callbackErrors.Load(),
+		Restarts:        e.restarts.Load(),
+		TimeoutErrors:   e.timeoutErrors.Load(),
+		TransientErrors: e.transientErrors.Load(),
+		PermanentErrors: e.permanentErrors.Load(),
+		RecoverySteps:   e.recoverySteps.Load(),
+		Recoveries:      e.recoveries.Load(),
+		LastPollLatency: time.Duration(e.lastPollLatency.Load()),
+	}
+}
+
+// pollErrorLimit returns the number of consecutive poll errors that end the
+// poll loop, zero polls forever. Supervised loops always end so they can be
+// restarted.
+func pollErrorLimit(config *Config) int {
+	if config.MaxPollErrors <= 0 && config.Supervisor != nil {
+		return DefaultMaxPollErrors
+	}
+	return config.MaxPollErrors
+}
+
+// supervise runs the poll loop and, with a SupervisorConfig, restarts it
+// with backoff after it ended on poll errors, reinitializing the device
+// first. onRestart is called with the cause before each restart.
+func (e *engine) supervise(
+	ctx context.Context,
+	run func(context.Context) error,
+	onRestart func(cause error),
+) error {
+	backoff := e.initialBackoff()
+	attempts := 0
+	for {
+		e.polledOK.Store(false)
+		err := run(ctx)
+		if e.supervisor == nil || ctx.Err() != nil || !errors.Is(err, ErrTooManyPollErrors) {
+			return err
+		}
+		// A loop that polled successfully before failing starts a new series
+		if e.polledOK.Load() {
+			attempts, backoff = 0, e.initialBackoff()
+		}
+
+		for {
+			attempts++
+			if e.supervisor.MaxRestarts > 0 && attempts > e.supervisor.MaxRestarts {
+				return fmt.Errorf("%w (%d): %w", ErrRestartsExhausted, e.supervisor.MaxRestarts, err)
+			}
+			if onRestart != nil {
+				onRestart(err)
+			}
+			e.restarts.Add(1)
+
+			if waitErr := sleepContext(ctx, backoff); waitErr != nil {
+				return waitErr
+			}
+			backoff = min(backoff*2, e.maxBackoff())
+
+			initErr := e.device.InitContext(ctx)
+			if initErr == nil {
+				break
+			}
+			err = fmt.Errorf("failed to reinitialize reader: %w", initErr)
+		}
+	}
+}
+
+func (e *engine) initialBackoff() time.Duration {
+	if e.supervisor == nil || e.supervisor.InitialBackoff <= 0 {
+		return DefaultInitialBackoff
+	}
+	return e.supervisor.InitialBackoff
+}
+
+func (e *engine) maxBackoff() time.Duration {
+	if e.supervisor == nil || e.supervisor.MaxBackoff <= 0 {
+		return DefaultMaxBackoff
+	}
+	return e.supervisor.MaxBackoff
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,434 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package polling
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pn532 "github.com/ZaparooProject/go-pn532"
+	"github.com/ZaparooProject/go-pn532/pn532sim"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const simCmdSAMConfiguration = 0x14
+
+var simIDm = []byte{0x01, 0x2E, 0x4C, 0xA1, 0xB2, 0xC3, 0xD4, 0xE5}
+
+func TestPollStrategies(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		strategy  PollStrategy
+		name      string
+		wantTypes []pn532.TagType
+		felica    bool
+	}{
+		{name: "ListPassiveTarget", strategy: ListPassiveTargetStrategy{}, wantTypes: []pn532.TagType{pn532.TagTypeNTAG}},
+		{
+			name:      "ListPassiveTarget_Ignores_FeliCa",
+			strategy:  ListPassiveTargetStrategy{},
+			felica:    true,
+			wantTypes: []pn532.TagType{},
+		},
+		{name: "AutoPoll", strategy: AutoPollStrategy{}, wantTypes: []pn532.TagType{pn532.TagTypeNTAG}},
+		{
+			name:      "AutoPoll_FeliCa",
+			strategy:  AutoPollStrategy{},
+			felica:    true,
+			wantTypes: []pn532.TagType{pn532.TagTypeFeliCa},
+		},
+		{
+			name:      "AutoPoll_Targets",
+			strategy:  AutoPollStrategy{Targets: []pn532.AutoPollTarget{pn532.AutoPollFeliCa212}, Period: 2},
+			wantTypes: []pn532.TagType{},
+		},
+		{name: "FeliCa", strategy: FeliCaStrategy{}, felica: true, wantTypes: []pn532.TagType{pn532.TagTypeFeliCa}},
+		{name: "FeliCa_Ignores_NTAG", strategy: FeliCaStrategy{}, wantTypes: []pn532.TagType{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var tag pn532sim.Tag = pn532sim.NewNTAG213(simUIDA)
+			if tt.felica {
+				tag = pn532sim.NewFeliCa(simIDm)
+			}
+			sim, err := pn532sim.New(pn532sim.WithTags(tag))
+			require.NoError(t, err)
+			device, err := pn532.New(sim)
+			require.NoError(t, err)
+			require.NoError(t, device.Init())
+
+			tags, err := tt.strategy.Poll(context.Background(), device, 1)
+			require.NoError(t, err)
+			types := make([]pn532.TagType, 0, len(tags))
+			for _, found := range tags {
+				types = append(types, found.Type)
+			}
+			assert.Equal(t, tt.wantTypes, types)
+		})
+	}
+}
+
+func TestSession_Strategy(t *testing.T) {
+	t.Parallel()
+
+	session, sim := newSimSession(t, &Config{
+		PollInterval:       5 * time.Millisecond,
+		CardRemovalTimeout: 50 * time.Millisecond,
+		Strategy:           AutoPollStrategy{},
+	})
+	events := session.Events()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = session.Start(ctx) }()
+
+	sim.PlaceTag(pn532sim.NewNTAG213(simUIDA))
+	detected := nextSessionEvent(t, events)
+	assert.Equal(t, EventDetected, detected.Type)
+	assert.Equal(t, "04a1a2a3a4a5a6", detected.UID)
+	assert.Positive(t, sim.CommandCount(0x60))
+	assert.Zero(t, sim.CommandCount(simCmdInListPassiveTarget))
+}
+
+// failingStrategy fails the first polls and then lists tags normally
+func failingStrategy(failures int32) PollStrategy {
+	var left atomic.Int32
+	left.Store(failures)
+	return PollFunc(func(ctx context.Context, device *pn532.Device, maxTags int) ([]*pn532.DetectedTag, error) {
+		if left.Add(-1) >= 0 {
+			return nil, pn532.ErrTransportTimeout
+		}
+		return ListPassiveTargetStrategy{}.Poll(ctx, device, maxTags)
+	})
+}
+
+func TestSession_SupervisorRestarts(t *testing.T) {
+	t.Parallel()
+
+	session, sim := newSimSession(t, &Config{
+		PollInterval:       5 * time.Millisecond,
+		CardRemovalTimeout: 50 * time.Millisecond,
+		MaxPollErrors:      2,
+		Strategy:           failingStrategy(4),
+		Supervisor:         &SupervisorConfig{InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond},
+	})
+	events := session.Events()
+	sim.PlaceTag(pn532sim.NewNTAG213(simUIDA))
+	inits := sim.CommandCount(simCmdSAMConfiguration)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = session.Start(ctx) }()
+
+	var types []EventType
+	for len(types) == 0 || types[len(types)-1] != EventDetected {
+		event := nextSessionEvent(t, events)
+		types = append(types, event.Type)
+		if event.Type == EventRestart {
+			require.ErrorIs(t, event.Err, ErrTooManyPollErrors)
+		}
+	}
+	assert.Equal(t, []EventType{
+		EventError, EventError, EventRestart, EventError, EventError, EventRestart, EventDetected,
+	}, types)
+	assert.Equal(t, inits+2, sim.CommandCount(simCmdSAMConfiguration), "device reinitialized per restart")
+
+	metrics := session.Metrics()
+	assert.Equal(t, int64(2), metrics.Restarts)
+	assert.Equal(t, int64(4), metrics.PollErrors)
+	assert.Greater(t, metrics.PollCycles, int64(4))
+	assert.Positive(t, metrics.CardsDetected)
+}
+
+func TestSession_SupervisorGivesUp(t *testing.T) {
+	t.Parallel()
+
+	session, sim := newSimSession(t, &Config{
+		PollInterval: 5 * time.Millisecond,
+		Supervisor: &SupervisorConfig{
+			InitialBackoff: time.Millisecond,
+			MaxBackoff:     time.Millisecond,
+			MaxRestarts:    2,
+		},
+	})
+	events := session.Events()
+	// Polls and reinitialization fail once the reader is gone
+	require.NoError(t, sim.Close())
+
+	err := session.Start(context.Background())
+	require.ErrorIs(t, err, ErrRestartsExhausted)
+	require.ErrorIs(t, err, pn532.ErrTransportClosed)
+	assert.Equal(t, int64(2), session.Metrics().Restarts)
+	assert.Equal(t, int64(DefaultMaxPollErrors), session.Metrics().PollErrors,
+		"a failed reinitialization doesn't poll again")
+
+	// Start has returned, so every event is buffered
+	restarts := 0
+	for len(events) > 0 {
+		event := <-events
+		if event.Type == EventRestart {
+			restarts++
+		}
+		if event.Type == EventReaderLost {
+			require.ErrorIs(t, event.Err, ErrRestartsExhausted)
+		}
+	}
+	assert.Equal(t, 2, restarts)
+}
+
+func TestSession_CallbackErrorMetrics(t *testing.T) {
+	t.Parallel()
+
+	session, sim := newSimSession(t, &Config{
+		PollInterval:       5 * time.Millisecond,
+		CardRemovalTimeout: 50 * time.Millisecond,
+	})
+	session.OnCardDetected = func(*pn532.DetectedTag) error {
+		return errors.New("rejected")
+	}
+	sim.PlaceTag(pn532sim.NewNTAG213(simUIDA))
+
+	err := session.Start(context.Background())
+	require.ErrorContains(t, err, "rejected")
+	metrics := session.Metrics()
+	assert.Equal(t, int64(1), metrics.CallbackErrors)
+	assert.Equal(t, int64(1), metrics.CardsDetected)
+	assert.Equal(t, int64(1), metrics.PollCycles)
+}
+
+func TestStrategyFor(t *testing.T) {
+	t.Parallel()
+
+	feliCaFirst := []pn532.AutoPollTarget{pn532.AutoPollFeliCa212, pn532.AutoPollFeliCa424, pn532.AutoPollGeneric106kbps}
+	tests := []struct {
+		config *Config
+		want   PollStrategy
+		name   string
+	}{
+		{name: "Default", config: &Config{}, want: ListPassiveTargetStrategy{}},
+		{
+			name:   "Strategy",
+			config: &Config{Strategy: FeliCaStrategy{}, TagTypes: []pn532.TagType{pn532.TagTypeNTAG}},
+			want:   FeliCaStrategy{},
+		},
+		{
+			name:   "Targets",
+			config: &Config{Targets: []pn532.AutoPollTarget{pn532.AutoPollISO14443A}, PollCount: 3, PollPeriod: 2},
+			want:   AutoPollStrategy{Targets: []pn532.AutoPollTarget{pn532.AutoPollISO14443A}, PollCount: 3, Period: 2},
+		},
+		{
+			name:   "ISO14443A_Types",
+			config: &Config{TagTypes: []pn532.TagType{pn532.TagTypeMIFARE, pn532.TagTypeNTAG}},
+			want:   ListPassiveTargetStrategy{},
+		},
+		{
+			name:   "FeliCa_Only",
+			config: &Config{TagTypes: []pn532.TagType{pn532.TagTypeFeliCa}},
+			want:   AutoPollStrategy{Targets: feliCaTargets},
+		},
+		{
+			name:   "FeliCa_Priority",
+			config: &Config{TagTypes: []pn532.TagType{pn532.TagTypeFeliCa, pn532.TagTypeNTAG, pn532.TagTypeMIFARE}},
+			want:   AutoPollStrategy{Targets: feliCaFirst},
+		},
+		{
+			name:   "Any",
+			config: &Config{TagTypes: []pn532.TagType{pn532.TagTypeAny}},
+			want:   AutoPollStrategy{Targets: defaultAutoPollTargets},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, strategyFor(tt.config))
+		})
+	}
+}
+
+func TestFilterTags(t *testing.T) {
+	t.Parallel()
+
+	ntag := &pn532.DetectedTag{UID: "ntag", Type: pn532.TagTypeNTAG}
+	mifare := &pn532.DetectedTag{UID: "mifare", Type: pn532.TagTypeMIFARE}
+	felica := &pn532.DetectedTag{UID: "felica", Type: pn532.TagTypeFeliCa}
+	tags := []*pn532.DetectedTag{ntag, mifare, felica}
+
+	tests := []struct {
+		name  string
+		types []pn532.TagType
+		want  []*pn532.DetectedTag
+	}{
+		{name: "No_Types", want: tags},
+		{name: "Filter", types: []pn532.TagType{pn532.TagTypeNTAG}, want: []*pn532.DetectedTag{ntag}},
+		{
+			name:  "Priority",
+			types: []pn532.TagType{pn532.TagTypeFeliCa, pn532.TagTypeNTAG, pn532.TagTypeMIFARE},
+			want:  []*pn532.DetectedTag{felica, ntag, mifare},
+		},
+		{
+			name:  "Any",
+			types: []pn532.TagType{pn532.TagTypeMIFARE, pn532.TagTypeAny},
+			want:  []*pn532.DetectedTag{mifare, ntag, felica},
+		},
+		{name: "None_Match", types: []pn532.TagType{pn532.TagTypeUnknown}, want: []*pn532.DetectedTag{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, filterTags(tags, tt.types))
+		})
+	}
+}
+
+func TestSession_TagTypes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Priority", func(t *testing.T) {
+		t.Parallel()
+
+		session, sim := newSimSession(t, &Config{
+			PollInterval:       5 * time.Millisecond,
+			CardRemovalTimeout: 50 * time.Millisecond,
+			TagTypes:           []pn532.TagType{pn532.TagTypeMIFARE, pn532.TagTypeNTAG},
+		})
+		events := session.Events()
+		sim.PlaceTag(pn532sim.NewNTAG213(simUIDA))
+		sim.PlaceTag(pn532sim.NewClassic1K([]byte{0xC1, 0xC2, 0xC3, 0xC4}))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() { _ = session.Start(ctx) }()
+
+		detected := nextSessionEvent(t, events)
+		assert.Equal(t, EventDetected, detected.Type)
+		assert.Equal(t, pn532.TagTypeMIFARE, detected.Tag.Type)
+		assert.Equal(t, "c1c2c3c4", detected.UID)
+	})
+
+	t.Run("Ignores_Other_Types", func(t *testing.T) {
+		t.Parallel()
+
+		session, sim := newSimSession(t, &Config{
+			PollInterval:       5 * time.Millisecond,
+			CardRemovalTimeout: 50 * time.Millisecond,
+			TagTypes:           []pn532.TagType{pn532.TagTypeFeliCa},
+		})
+		events := session.Events()
+		sim.PlaceTag(pn532sim.NewNTAG213(simUIDA))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() { _ = session.Start(ctx) }()
+
+		require.Eventually(t, func() bool { return session.Metrics().PollCycles >= 3 },
+			time.Second, 5*time.Millisecond)
+		assert.Zero(t, session.Metrics().CardsDetected)
+		assert.Zero(t, sim.CommandCount(simCmdInListPassiveTarget))
+
+		sim.PlaceTag(pn532sim.NewFeliCa(simIDm))
+		detected := nextSessionEvent(t, events)
+		assert.Equal(t, EventDetected, detected.Type)
+		assert.Equal(t, pn532.TagTypeFeliCa, detected.Tag.Type)
+	})
+}
+
+func TestGroupRandomUIDs(t *testing.T) {
+	t.Parallel()
+
+	fixed := &pn532.DetectedTag{UID: "04a1a2a3a4a5a6", UIDBytes: simUIDA, Type: pn532.TagTypeNTAG}
+	random := &pn532.DetectedTag{UID: "08010203", UIDBytes: []byte{0x08, 0x01, 0x02, 0x03}, Type: pn532.TagTypeMIFARE}
+	tags := []*pn532.DetectedTag{random, fixed}
+
+	assert.Equal(t, tags, groupRandomUIDs(tags, RandomUIDReport))
+	assert.Equal(t, []*pn532.DetectedTag{fixed}, groupRandomUIDs(tags, RandomUIDIgnore))
+
+	grouped := groupRandomUIDs(tags, RandomUIDGroup)
+	require.Len(t, grouped, 2)
+	assert.Equal(t, RandomUID, grouped[0].UID)
+	assert.Equal(t, random.UIDBytes, grouped[0].UIDBytes)
+	assert.Equal(t, "08010203", random.UID, "the polled tag is left alone")
+	assert.Same(t, fixed, grouped[1])
+}
+
+func TestSession_RandomUIDs(t *testing.T) {
+	t.Parallel()
+
+	randomUIDs := [][]byte{{0x08, 0x01, 0x02, 0x03}, {0x08, 0x04, 0x05, 0x06}}
+	tests := []struct {
+		name   string
+		want   []string
+		policy RandomUIDPolicy
+	}{
+		{name: "Report", policy: RandomUIDReport, want: []string{"08010203", "08040506"}},
+		{name: "Group", policy: RandomUIDGroup, want: []string{RandomUID}},
+		{name: "Ignore", policy: RandomUIDIgnore, want: []string{"04a1a2a3a4a5a6"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			session, sim := newSimSession(t, &Config{
+				PollInterval:       5 * time.Millisecond,
+				CardRemovalTimeout: time.Second,
+				RandomUIDs:         tt.policy,
+				EventBuffer:        32,
+			})
+			events := session.Events()
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() { _ = session.Start(ctx) }()
+
+			// A phone answers with a new random UID on every tap
+			var phone pn532sim.Tag
+			for _, uid := range randomUIDs {
+				if phone != nil {
+					sim.RemoveTag(phone)
+				}
+				phone = pn532sim.NewClassic1K(uid)
+				sim.PlaceTag(phone)
+				polls := session.Metrics().PollCycles
+				require.Eventually(t, func() bool { return session.Metrics().PollCycles >= polls+3 },
+					time.Second, time.Millisecond)
+			}
+			if tt.policy == RandomUIDIgnore {
+				sim.PlaceTag(pn532sim.NewNTAG213(simUIDA))
+				require.Eventually(t, func() bool { return session.Metrics().CardsDetected > 0 },
+					time.Second, time.Millisecond)
+			}
+			cancel()
+
+			var uids []string
+			for len(events) > 0 {
+				if event := <-events; event.Type == EventDetected || event.Type == EventChanged {
+					uids = append(uids, event.UID)
+				}
+			}
+			assert.Equal(t, tt.want, uids)
+		})
+	}
+}
@@ -0,0 +1,123 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package polling
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ZaparooProject/go-pn532"
+)
+
+// DefaultEventBuffer is the Events channel capacity used when
+// Config.EventBuffer is not set
+const DefaultEventBuffer = 16
+
+// EventType identifies what a session observed
+type EventType int
+
+const (
+	// EventDetected is sent when a card is placed on the reader
+	EventDetected EventType = iota
+	// EventRemoved is sent when the card leaves the reader
+	EventRemoved
+	// EventChanged is sent when a different card replaces the current one
+	EventChanged
+	// EventError is sent for a failed poll, polling continues
+	EventError
+	// EventReaderLost is sent when polling stops after Config.MaxPollErrors
+	// consecutive failures, or once a supervisor gives up. Actor-based
+	// sessions also send it when the transport is closed or the device is gone.
+	EventReaderLost
+	// EventRead carries the NDEF message of a new card, or the error reading
+	// it, if Config.ReadNDEF is enabled
+	EventRead
+	// EventRestart is sent when the supervisor restarts a failed poll loop,
+	// Err is the failure
+	EventRestart
+	// EventHealth is sent when the reader health changes, see
+	// Config.Health. Err is the poll failure that caused the change.
+	EventHealth
+	// EventRecovery is sent for every recovery step of the health monitor,
+	// Err is set if the step failed
+	EventRecovery
+)
+
+// String returns a human-readable name for the event type
+func (t EventType) String() string {
+	switch t {
+	case EventDetected:
+		return "detected"
+	case EventRemoved:
+		return "removed"
+	case EventChanged:
+		return "changed"
+	case EventError:
+		return "error"
+	case EventReaderLost:
+		return "reader_lost"
+	case EventRead:
+		return "read"
+	case EventRestart:
+		return "restart"
+	case EventHealth:
+		return "health"
+	case EventRecovery:
+		return "recovery"
+	default:
+		return fmt.Sprintf("EventType(%d)", int(t))
+	}
+}
+
+// Event is a single entry in the Session event stream
+type Event struct {
+	Time time.Time
+	// Err is set for EventError, EventReaderLost, EventRestart, failed
+	// EventRead and EventRecovery, and degrading EventHealth
+	Err error
+	// Tag is set for EventDetected, EventChanged and EventRead
+	Tag *pn532.DetectedTag
+	// Message is the NDEF message of a successful EventRead
+	Message *pn532.NDEFMessage
+	// Reader is the stable ID of the device, see pn532.Device.StableID
+	Reader string
+	// UID of the card the event is about, empty for reader events
+	UID  string
+	Type EventType
+	// Health is the new reader health of an EventHealth
+	Health HealthState
+	// Recovery is the step of an EventRecovery
+	Recovery RecoveryStep
+}
+
+// EventPolicy decides what happens when the Events channel is full
+type EventPolicy int
+
+const (
+	// EventPolicyDropOldest discards the oldest buffered event to make room,
+	// polling never waits for the consumer
+	EventPolicyDropOldest EventPolicy = iota
+	// EventPolicyDropNewest discards the new event, polling never waits for
+	// the consumer
+	EventPolicyDropNewest
+	// EventPolicyBlock makes polling wait until the consumer catches up
+	EventPolicyBlock
+)
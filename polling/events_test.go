@@ -0,0 +1,329 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package polling
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pn532 "github.com/ZaparooProject/go-pn532"
+	"github.com/ZaparooProject/go-pn532/pn532sim"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	simUIDA = []byte{0x04, 0xA1, 0xA2, 0xA3, 0xA4, 0xA5, 0xA6}
+	simUIDB = []byte{0x04, 0xB1, 0xB2, 0xB3, 0xB4, 0xB5, 0xB6}
+)
+
+// newSimSession creates a session on a simulated reader
+func newSimSession(t *testing.T, config *Config) (*Session, *pn532sim.Simulator) {
+	t.Helper()
+
+	sim, err := pn532sim.New()
+	require.NoError(t, err)
+	device, err := pn532.New(sim)
+	require.NoError(t, err)
+	require.NoError(t, device.Init())
+	t.Cleanup(func() { _ = device.Close() })
+
+	return NewSession(device, config), sim
+}
+
+// nextSessionEvent waits for the next event on the stream
+func nextSessionEvent(t *testing.T, events <-chan Event) Event {
+	t.Helper()
+
+	select {
+	case event, ok := <-events:
+		require.True(t, ok, "event stream closed")
+		return event
+	case <-time.After(2 * time.Second):
+		require.FailNow(t, "timed out waiting for session event")
+		return Event{}
+	}
+}
+
+func TestSession_Events(t *testing.T) {
+	t.Parallel()
+
+	session, sim := newSimSession(t, &Config{
+		PollInterval:       5 * time.Millisecond,
+		CardRemovalTimeout: 300 * time.Millisecond,
+	})
+	events := session.Events()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- session.Start(ctx) }()
+
+	tagA := pn532sim.NewNTAG213(simUIDA)
+	sim.PlaceTag(tagA)
+	detected := nextSessionEvent(t, events)
+	assert.Equal(t, EventDetected, detected.Type)
+	assert.Equal(t, "04a1a2a3a4a5a6", detected.UID)
+	require.NotNil(t, detected.Tag)
+	assert.Equal(t, pn532.TagTypeNTAG, detected.Tag.Type)
+	assert.False(t, detected.Time.IsZero())
+
+	// Swapped faster than the removal timeout
+	tagB := pn532sim.NewNTAG213(simUIDB)
+	sim.RemoveTag(tagA)
+	sim.PlaceTag(tagB)
+	changed := nextSessionEvent(t, events)
+	assert.Equal(t, EventChanged, changed.Type)
+	assert.Equal(t, "04b1b2b3b4b5b6", changed.UID)
+
+	sim.RemoveTag(tagB)
+	removed := nextSessionEvent(t, events)
+	assert.Equal(t, EventRemoved, removed.Type)
+	assert.Equal(t, "04b1b2b3b4b5b6", removed.UID)
+	assert.Nil(t, removed.Tag)
+
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+	require.NoError(t, session.Close())
+	_, ok := <-events
+	assert.False(t, ok, "Close ends the event stream")
+	_, ok = <-session.Events()
+	assert.False(t, ok)
+	assert.Zero(t, session.DroppedEvents())
+}
+
+func TestSession_EventPolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		wantUIDs    []string
+		policy      EventPolicy
+		wantDropped uint64
+	}{
+		{name: "drop oldest", policy: EventPolicyDropOldest, wantUIDs: []string{"2", "3"}, wantDropped: 1},
+		{name: "drop newest", policy: EventPolicyDropNewest, wantUIDs: []string{"1", "2"}, wantDropped: 1},
+		{name: "block", policy: EventPolicyBlock, wantUIDs: []string{"1", "2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			device, _ := createMockDeviceWithTransport(t)
+			session := NewSession(device, &Config{EventBuffer: 2, EventPolicy: tt.policy})
+			events := session.Events()
+
+			// A blocked send gives up once the context is done
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+			for _, uid := range []string{"1", "2", "3"} {
+				session.emit(ctx, Event{Type: EventDetected, UID: uid})
+			}
+
+			require.NoError(t, session.Close())
+			var uids []string
+			for event := range events {
+				uids = append(uids, event.UID)
+			}
+			assert.Equal(t, tt.wantUIDs, uids)
+			assert.Equal(t, tt.wantDropped, session.DroppedEvents())
+		})
+	}
+}
+
+func TestSession_EventsReaderLost(t *testing.T) {
+	t.Parallel()
+
+	device, mockTransport := createMockDeviceWithTransport(t)
+	mockTransport.SetError(0x4A, errors.New("device unplugged"))
+	session := NewSession(device, &Config{
+		PollInterval:       time.Millisecond,
+		CardRemovalTimeout: 10 * time.Millisecond,
+		MaxPollErrors:      2,
+	})
+	events := session.Events()
+
+	err := session.Start(context.Background())
+	require.ErrorIs(t, err, ErrTooManyPollErrors)
+
+	var types []EventType
+	for len(events) > 0 {
+		event := <-events
+		types = append(types, event.Type)
+		require.Error(t, event.Err)
+	}
+	assert.Equal(t, []EventType{EventError, EventError, EventReaderLost}, types)
+}
+
+func TestSession_ActorEvents(t *testing.T) {
+	t.Parallel()
+
+	sim, err := pn532sim.New()
+	require.NoError(t, err)
+	device, err := pn532.New(sim)
+	require.NoError(t, err)
+	require.NoError(t, device.Init())
+	t.Cleanup(func() { _ = device.Close() })
+
+	// Default poll error handling, no MaxPollErrors or Supervisor
+	session := NewActorBasedSession(device, &Config{
+		PollInterval:       5 * time.Millisecond,
+		CardRemovalTimeout: 50 * time.Millisecond,
+	})
+	events := session.Events()
+	require.NoError(t, session.Start(context.Background()))
+	t.Cleanup(func() { _ = session.Close() })
+
+	tagA := pn532sim.NewNTAG213(simUIDA)
+	sim.PlaceTag(tagA)
+	detected := nextSessionEvent(t, events)
+	assert.Equal(t, EventDetected, detected.Type)
+	assert.Equal(t, "04a1a2a3a4a5a6", detected.UID)
+
+	sim.RemoveTag(tagA)
+	event := detected
+	for event.Type == EventDetected {
+		event = nextSessionEvent(t, events)
+	}
+	assert.Equal(t, EventRemoved, event.Type)
+	assert.Equal(t, "04a1a2a3a4a5a6", event.UID, "removal names the tag")
+	assert.False(t, session.GetState().Present)
+
+	require.NoError(t, sim.Close())
+	lost := nextSessionEvent(t, events)
+	assert.Equal(t, EventReaderLost, lost.Type)
+	require.ErrorIs(t, lost.Err, pn532.ErrTransportClosed)
+}
+
+func TestSession_EventsNotRequested(t *testing.T) {
+	t.Parallel()
+
+	device, _ := createMockDeviceWithTransport(t)
+	session := NewSession(device, nil)
+	session.emit(context.Background(), Event{Type: EventDetected})
+
+	assert.Zero(t, session.DroppedEvents())
+	require.NoError(t, session.Close())
+}
+
+func TestEventType_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "detected", EventDetected.String())
+	assert.Equal(t, "reader_lost", EventReaderLost.String())
+	assert.Equal(t, "restart", EventRestart.String())
+	assert.Equal(t, "health", EventHealth.String())
+	assert.Equal(t, "recovery", EventRecovery.String())
+	assert.Equal(t, "EventType(9)", EventType(9).String())
+}
+
+// newTextTag returns a simulated NTAG holding a single text record
+func newTextTag(t *testing.T, uid []byte, text string) *pn532sim.NTAG {
+	t.Helper()
+
+	ntag := pn532sim.NewNTAG213(uid)
+	session, sim := newSimSession(t, nil)
+	sim.PlaceTag(ntag)
+	detected, err := session.GetDevice().DetectTag()
+	require.NoError(t, err)
+	tag, err := session.GetDevice().CreateTag(detected)
+	require.NoError(t, err)
+	require.NoError(t, tag.WriteNDEF(&pn532.NDEFMessage{Records: []pn532.NDEFRecord{
+		{Type: pn532.NDEFTypeText, Text: text},
+	}}))
+	sim.RemoveAllTags()
+	return ntag
+}
+
+func TestSession_ReadNDEF(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		wantErr  error
+		newTag   func(t *testing.T) *pn532sim.NTAG
+		name     string
+		wantText string
+		simOpts  []pn532sim.Option
+		timeout  time.Duration
+	}{
+		{
+			name:     "NDEF message",
+			newTag:   func(t *testing.T) *pn532sim.NTAG { return newTextTag(t, simUIDA, "hello") },
+			wantText: "hello",
+		},
+		{
+			name:   "blank tag",
+			newTag: func(*testing.T) *pn532sim.NTAG { return pn532sim.NewNTAG213(simUIDB) },
+		},
+		{
+			name:    "timeout",
+			newTag:  func(t *testing.T) *pn532sim.NTAG { return newTextTag(t, simUIDA, "hello") },
+			simOpts: []pn532sim.Option{pn532sim.WithCommandDelay(20 * time.Millisecond)},
+			timeout: 10 * time.Millisecond,
+			wantErr: context.DeadlineExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ntag := tt.newTag(t)
+			sim, err := pn532sim.New(append(tt.simOpts, pn532sim.WithTags(ntag))...)
+			require.NoError(t, err)
+			device, err := pn532.New(sim)
+			require.NoError(t, err)
+			require.NoError(t, device.Init())
+			defer func() { _ = device.Close() }()
+
+			session := NewSession(device, &Config{
+				PollInterval:       5 * time.Millisecond,
+				CardRemovalTimeout: time.Second,
+				ReadNDEF:           true,
+				ReadTimeout:        tt.timeout,
+			})
+			events := session.Events()
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() { _ = session.Start(ctx) }()
+
+			assert.Equal(t, EventDetected, nextSessionEvent(t, events).Type)
+			read := nextSessionEvent(t, events)
+			require.Equal(t, EventRead, read.Type)
+			assert.Equal(t, ntag.UID(), read.Tag.UIDBytes)
+
+			switch {
+			case tt.wantErr != nil:
+				require.ErrorIs(t, read.Err, tt.wantErr)
+			case tt.wantText == "":
+				require.Error(t, read.Err)
+				assert.Nil(t, read.Message)
+			default:
+				require.NoError(t, read.Err)
+				require.NotNil(t, read.Message)
+				require.Len(t, read.Message.Records, 1)
+				assert.Equal(t, tt.wantText, read.Message.Records[0].Text)
+			}
+		})
+	}
+}
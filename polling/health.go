@@ -0,0 +1,238 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package polling
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/ZaparooProject/go-pn532"
+)
+
+// ErrReaderDead is returned by Session.Start once every recovery step of the
+// health monitor has failed
+var ErrReaderDead = errors.New("reader is not responding")
+
+// HealthState is the reader health reported by the health monitor
+type HealthState int32
+
+const (
+	// HealthOK means the last poll succeeded
+	HealthOK HealthState = iota
+	// HealthDegraded means polls are failing but recovery hasn't started
+	HealthDegraded
+	// HealthRecovering means recovery steps are running
+	HealthRecovering
+	// HealthDead means every recovery step failed, polling has stopped
+	HealthDead
+)
+
+// String returns a human-readable name for the health state
+func (h HealthState) String() string {
+	switch h {
+	case HealthOK:
+		return "ok"
+	case HealthDegraded:
+		return "degraded"
+	case HealthRecovering:
+		return "recovering"
+	case HealthDead:
+		return "dead"
+	default:
+		return fmt.Sprintf("HealthState(%d)", int(h))
+	}
+}
+
+// RecoveryStep is an action the health monitor takes on a failing reader
+type RecoveryStep int
+
+const (
+	// RecoveryProbe checks whether the PN532 answers at all with
+	// GetGeneralStatus and a Diagnose communication test
+	RecoveryProbe RecoveryStep = iota
+	// RecoveryRFReset switches the RF field off and on
+	RecoveryRFReset
+	// RecoveryReinit initializes the PN532 again
+	RecoveryReinit
+	// RecoveryReopen reopens the transport, or hard resets the PN532 if the
+	// transport can't be reopened, and initializes it again
+	RecoveryReopen
+)
+
+// String returns a human-readable name for the recovery step
+func (r RecoveryStep) String() string {
+	switch r {
+	case RecoveryProbe:
+		return "probe"
+	case RecoveryRFReset:
+		return "rf_reset"
+	case RecoveryReinit:
+		return "reinit"
+	case RecoveryReopen:
+		return "reopen"
+	default:
+		return fmt.Sprintf("RecoveryStep(%d)", int(r))
+	}
+}
+
+// recoverySteps are run in order, one per series of failed polls
+var recoverySteps = []RecoveryStep{RecoveryRFReset, RecoveryReinit, RecoveryReopen}
+
+// probeData is echoed by the Diagnose communication test
+var probeData = []byte("pn532")
+
+// healthMonitor tracks consecutive poll failures and escalates recovery.
+// It is driven by the polling goroutine, only state is read elsewhere.
+type healthMonitor struct {
+	config   HealthConfig
+	failures int
+	step     int
+	state    atomic.Int32
+}
+
+// newHealthMonitor creates a monitor, filling in defaults
+func newHealthMonitor(config HealthConfig) *healthMonitor {
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = DefaultFailureThreshold
+	}
+	if config.ProbeTimeout <= 0 {
+		config.ProbeTimeout = DefaultProbeTimeout
+	}
+	return &healthMonitor{config: config}
+}
+
+// HealthState returns the reader health, HealthOK without Config.Health
+func (s *Session) HealthState() HealthState {
+	if s.health == nil {
+		return HealthOK
+	}
+	return HealthState(s.health.state.Load())
+}
+
+// setHealth changes the health state and reports the change
+func (s *Session) setHealth(ctx context.Context, state HealthState, cause error) {
+	previous := HealthState(s.health.state.Swap(int32(state)))
+	if previous == state {
+		return
+	}
+	if previous == HealthRecovering && state == HealthOK {
+		s.engine.recoveries.Add(1)
+	}
+	s.emit(ctx, Event{Type: EventHealth, Health: state, Err: cause})
+}
+
+// healthPollSucceeded marks the reader healthy after a successful poll
+func (s *Session) healthPollSucceeded(ctx context.Context) {
+	if s.health == nil {
+		return
+	}
+	s.health.failures = 0
+	s.health.step = 0
+	s.setHealth(ctx, HealthOK, nil)
+}
+
+// healthPollFailed counts a failed poll and runs the next recovery step once
+// Config.Health.FailureThreshold polls in a row failed, or right away for a
+// permanent error. It returns an ErrReaderDead error once all steps failed.
+func (s *Session) healthPollFailed(ctx context.Context, err error) error {
+	h := s.health
+	h.failures++
+	if HealthState(h.state.Load()) == HealthOK {
+		s.setHealth(ctx, HealthDegraded, err)
+	}
+	if h.failures < h.config.FailureThreshold && pn532.GetErrorType(err) != pn532.ErrorTypePermanent {
+		return nil
+	}
+	h.failures = 0
+
+	if h.step >= len(recoverySteps) {
+		s.setHealth(ctx, HealthDead, err)
+		return fmt.Errorf("%w: %w", ErrReaderDead, err)
+	}
+	s.setHealth(ctx, HealthRecovering, err)
+
+	probeErr := s.runRecoveryStep(ctx, RecoveryProbe)
+	// The RF field can only be reset if the PN532 still answers
+	if probeErr != nil && recoverySteps[h.step] == RecoveryRFReset {
+		h.step++
+	}
+	_ = s.runRecoveryStep(ctx, recoverySteps[h.step])
+	h.step++
+	return nil
+}
+
+// runRecoveryStep runs a single step bounded by Config.Health.ProbeTimeout
+// and reports it as an EventRecovery
+func (s *Session) runRecoveryStep(ctx context.Context, step RecoveryStep) error {
+	stepCtx, cancel := context.WithTimeout(ctx, s.health.config.ProbeTimeout)
+	defer cancel()
+
+	var err error
+	switch step {
+	case RecoveryProbe:
+		err = s.probeReader(stepCtx)
+	case RecoveryRFReset:
+		err = s.device.ResetRFFieldContext(stepCtx)
+	case RecoveryReinit:
+		err = s.device.InitContext(stepCtx)
+	case RecoveryReopen:
+		err = s.reopenReader(stepCtx)
+	}
+	if step != RecoveryProbe {
+		s.engine.recoverySteps.Add(1)
+	}
+
+	s.emit(ctx, Event{Type: EventRecovery, Recovery: step, Err: err})
+	return err
+}
+
+// probeReader checks that the PN532 answers status and echo requests
+func (s *Session) probeReader(ctx context.Context) error {
+	if _, err := s.device.GetGeneralStatusContext(ctx); err != nil {
+		return fmt.Errorf("status probe failed: %w", err)
+	}
+	result, err := s.device.DiagnoseContext(ctx, pn532.DiagnoseCommunicationTest, probeData)
+	if err != nil {
+		return fmt.Errorf("communication test failed: %w", err)
+	}
+	if !result.Success {
+		return errors.New("communication test failed: echo mismatch")
+	}
+	return nil
+}
+
+// reopenReader reopens the transport, falling back to a hard reset, and
+// initializes the PN532 again
+func (s *Session) reopenReader(ctx context.Context) error {
+	err := s.device.ReopenTransport()
+	if errors.Is(err, pn532.ErrDeviceNotSupported) {
+		err = s.device.HardReset()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to reset transport: %w", err)
+	}
+	if err := s.device.InitContext(ctx); err != nil {
+		return fmt.Errorf("failed to initialize after transport reset: %w", err)
+	}
+	return nil
+}
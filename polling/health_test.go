@@ -0,0 +1,211 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package polling
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ZaparooProject/go-pn532"
+	"github.com/ZaparooProject/go-pn532/pn532sim"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// simCmdRFConfiguration is the RFConfiguration command code
+const simCmdRFConfiguration = 0x32
+
+// healthStep is an EventHealth or EventRecovery reduced for comparison
+type healthStep struct {
+	Type     EventType
+	Recovery RecoveryStep
+	Health   HealthState
+	Failed   bool
+}
+
+// healthSteps keeps the health monitor events of a buffered event stream
+func healthSteps(events <-chan Event) []healthStep {
+	var steps []healthStep
+	for len(events) > 0 {
+		event := <-events
+		switch event.Type {
+		case EventHealth:
+			steps = append(steps, healthStep{Type: event.Type, Health: event.Health})
+		case EventRecovery:
+			steps = append(steps, healthStep{Type: event.Type, Recovery: event.Recovery, Failed: event.Err != nil})
+		case EventReaderLost:
+			steps = append(steps, healthStep{Type: event.Type})
+		default:
+		}
+	}
+	return steps
+}
+
+func TestSession_HealthRecovers(t *testing.T) {
+	t.Parallel()
+
+	session, sim := newSimSession(t, &Config{
+		PollInterval:       5 * time.Millisecond,
+		CardRemovalTimeout: 50 * time.Millisecond,
+		Strategy:           failingStrategy(3),
+		Health:             &HealthConfig{},
+		EventBuffer:        64,
+	})
+	events := session.Events()
+	sim.PlaceTag(pn532sim.NewNTAG213(simUIDA))
+	rfConfigs := sim.CommandCount(simCmdRFConfiguration)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- session.Start(ctx) }()
+
+	require.Eventually(t, func() bool { return session.Metrics().CardsDetected > 0 },
+		2*time.Second, 5*time.Millisecond)
+	cancel()
+	<-done
+
+	assert.Equal(t, []healthStep{
+		{Type: EventHealth, Health: HealthDegraded},
+		{Type: EventHealth, Health: HealthRecovering},
+		{Type: EventRecovery, Recovery: RecoveryProbe},
+		{Type: EventRecovery, Recovery: RecoveryRFReset},
+		{Type: EventHealth, Health: HealthOK},
+	}, healthSteps(events))
+	assert.Equal(t, rfConfigs+2, sim.CommandCount(simCmdRFConfiguration), "RF field switched off and on")
+	assert.Equal(t, HealthOK, session.HealthState())
+
+	metrics := session.Metrics()
+	assert.Equal(t, int64(3), metrics.TimeoutErrors)
+	assert.Zero(t, metrics.PermanentErrors)
+	assert.Equal(t, int64(1), metrics.RecoverySteps)
+	assert.Equal(t, int64(1), metrics.Recoveries)
+}
+
+func TestSession_HealthEscalatesToDead(t *testing.T) {
+	t.Parallel()
+
+	session, _ := newSimSession(t, &Config{
+		PollInterval: time.Millisecond,
+		Strategy: PollFunc(func(context.Context, *pn532.Device, int) ([]*pn532.DetectedTag, error) {
+			return nil, errors.New("antenna broken")
+		}),
+		Health:      &HealthConfig{FailureThreshold: 10},
+		EventBuffer: 64,
+	})
+	events := session.Events()
+
+	err := session.Start(context.Background())
+	require.ErrorIs(t, err, ErrReaderDead)
+	require.ErrorContains(t, err, "antenna broken")
+	assert.Equal(t, HealthDead, session.HealthState())
+
+	// Permanent errors escalate on every poll, the simulator can't be reopened
+	assert.Equal(t, []healthStep{
+		{Type: EventHealth, Health: HealthDegraded},
+		{Type: EventHealth, Health: HealthRecovering},
+		{Type: EventRecovery, Recovery: RecoveryProbe},
+		{Type: EventRecovery, Recovery: RecoveryRFReset},
+		{Type: EventRecovery, Recovery: RecoveryProbe},
+		{Type: EventRecovery, Recovery: RecoveryReinit},
+		{Type: EventRecovery, Recovery: RecoveryProbe},
+		{Type: EventRecovery, Recovery: RecoveryReopen, Failed: true},
+		{Type: EventHealth, Health: HealthDead},
+		{Type: EventReaderLost},
+	}, healthSteps(events))
+
+	metrics := session.Metrics()
+	assert.Equal(t, int64(4), metrics.PermanentErrors)
+	assert.Equal(t, int64(3), metrics.RecoverySteps)
+	assert.Zero(t, metrics.Recoveries)
+}
+
+func TestSession_HealthSkipsRFResetWhenProbeFails(t *testing.T) {
+	t.Parallel()
+
+	session, sim := newSimSession(t, &Config{
+		PollInterval: time.Millisecond,
+		Health:       &HealthConfig{FailureThreshold: 1},
+		EventBuffer:  64,
+	})
+	events := session.Events()
+	require.NoError(t, sim.Close())
+
+	err := session.Start(context.Background())
+	require.ErrorIs(t, err, ErrReaderDead)
+	require.ErrorIs(t, err, pn532.ErrTransportClosed)
+
+	assert.Equal(t, []healthStep{
+		{Type: EventHealth, Health: HealthDegraded},
+		{Type: EventHealth, Health: HealthRecovering},
+		{Type: EventRecovery, Recovery: RecoveryProbe, Failed: true},
+		{Type: EventRecovery, Recovery: RecoveryReinit, Failed: true},
+		{Type: EventRecovery, Recovery: RecoveryProbe, Failed: true},
+		{Type: EventRecovery, Recovery: RecoveryReopen, Failed: true},
+		{Type: EventHealth, Health: HealthDead},
+		{Type: EventReaderLost},
+	}, healthSteps(events))
+	assert.Equal(t, int64(2), session.Metrics().RecoverySteps)
+}
+
+func TestHealthState_String(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		want  string
+		state HealthState
+	}{
+		{state: HealthOK, want: "ok"},
+		{state: HealthDegraded, want: "degraded"},
+		{state: HealthRecovering, want: "recovering"},
+		{state: HealthDead, want: "dead"},
+		{state: HealthState(9), want: "HealthState(9)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, tt.state.String())
+		})
+	}
+}
+
+func TestRecoveryStep_String(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		want string
+		step RecoveryStep
+	}{
+		{step: RecoveryProbe, want: "probe"},
+		{step: RecoveryRFReset, want: "rf_reset"},
+		{step: RecoveryReinit, want: "reinit"},
+		{step: RecoveryReopen, want: "reopen"},
+		{step: RecoveryStep(9), want: "RecoveryStep(9)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, tt.step.String())
+		})
+	}
+}
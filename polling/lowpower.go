@@ -0,0 +1,165 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package polling
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ZaparooProject/go-pn532"
+)
+
+// defaultWakeupSources wakes the PN532 on any host interface activity
+const defaultWakeupSources = pn532.WakeupHSU | pn532.WakeupSPI | pn532.WakeupI2C
+
+// PowerStats reports how a low-power session spent its time
+type PowerStats struct {
+	// SleepTime is the total time the PN532 was powered down
+	SleepTime time.Duration
+	// ActiveTime is the total time the PN532 was awake
+	ActiveTime time.Duration
+	// LastWakeLatency is how long the first command after the last sleep took
+	LastWakeLatency time.Duration
+	// MaxWakeLatency is the slowest wake-up seen
+	MaxWakeLatency time.Duration
+	// TotalWakeLatency is the sum of all wake-up latencies
+	TotalWakeLatency time.Duration
+	// Sleeps is the number of times the PN532 was powered down
+	Sleeps uint64
+}
+
+// DutyCycle returns the fraction of time the PN532 was awake
+func (p PowerStats) DutyCycle() float64 {
+	total := p.ActiveTime + p.SleepTime
+	if total == 0 {
+		return 1
+	}
+	return float64(p.ActiveTime) / float64(total)
+}
+
+// MeanWakeLatency returns the average wake-up latency
+func (p PowerStats) MeanWakeLatency() time.Duration {
+	if p.Sleeps == 0 {
+		return 0
+	}
+	return p.TotalWakeLatency / time.Duration(p.Sleeps)
+}
+
+// PowerStats returns low-power statistics, all zero unless Config.LowPower
+// is set
+func (s *Session) PowerStats() PowerStats {
+	s.powerMutex.Lock()
+	defer s.powerMutex.Unlock()
+
+	stats := s.powerStats
+	if !s.awakeSince.IsZero() {
+		stats.ActiveTime += time.Since(s.awakeSince)
+	}
+	return stats
+}
+
+// shouldPowerDown reports whether the session can sleep before the next poll
+func (s *Session) shouldPowerDown() bool {
+	if s.config.LowPower == nil || s.isPaused.Load() {
+		return false
+	}
+
+	s.stateMutex.RLock()
+	defer s.stateMutex.RUnlock()
+	return !s.state.Present && len(s.presentTags) == 0
+}
+
+// sleepBetweenPolls powers the PN532 down for the sleep interval and wakes
+// it again. A reader that refuses to power down is polled as usual.
+func (s *Session) sleepBetweenPolls(ctx context.Context) error {
+	lowPower := s.config.LowPower
+	sources := lowPower.WakeupSources
+	if sources == 0 {
+		sources = defaultWakeupSources
+	}
+	interval := lowPower.SleepInterval
+	if interval <= 0 {
+		interval = DefaultSleepInterval
+	}
+
+	if err := s.device.PowerDownContext(ctx, sources, 0x00); err != nil {
+		s.handlePollingError(ctx, err)
+		return s.countPollingError(ctx, err)
+	}
+	sleepStart := time.Now()
+	s.recordSleep(sleepStart)
+
+	timer := time.NewTimer(interval)
+	defer safeTimerStop(timer)
+	select {
+	case <-timer.C:
+	case <-s.pauseChan:
+		// Writers wake the chip with their first command
+		if err := s.handlePauseSignal(ctx); err != nil {
+			return err
+		}
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return s.wake(ctx, sleepStart)
+}
+
+// wake brings the PN532 out of PowerDown with a cheap command and records
+// how long that took
+func (s *Session) wake(ctx context.Context, sleepStart time.Time) error {
+	wakeStart := time.Now()
+	_, err := s.device.GetFirmwareVersionContext(ctx)
+	latency := time.Since(wakeStart)
+	s.recordWake(wakeStart.Sub(sleepStart), latency)
+
+	if err != nil {
+		err = fmt.Errorf("failed to wake reader: %w", err)
+		s.handlePollingError(ctx, err)
+		return s.countPollingError(ctx, err)
+	}
+	return nil
+}
+
+// recordSleep closes the current active period
+func (s *Session) recordSleep(now time.Time) {
+	s.powerMutex.Lock()
+	defer s.powerMutex.Unlock()
+
+	if !s.awakeSince.IsZero() {
+		s.powerStats.ActiveTime += now.Sub(s.awakeSince)
+	}
+	s.awakeSince = time.Time{}
+	s.powerStats.Sleeps++
+}
+
+// recordWake accounts a finished sleep and its wake-up latency
+func (s *Session) recordWake(slept, latency time.Duration) {
+	s.powerMutex.Lock()
+	defer s.powerMutex.Unlock()
+
+	s.awakeSince = time.Now()
+	s.powerStats.SleepTime += slept
+	s.powerStats.LastWakeLatency = latency
+	s.powerStats.TotalWakeLatency += latency
+	s.powerStats.MaxWakeLatency = max(s.powerStats.MaxWakeLatency, latency)
+}
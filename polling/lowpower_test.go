@@ -0,0 +1,117 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package polling
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ZaparooProject/go-pn532/pn532sim"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	simCmdPowerDown           = 0x16
+	simCmdInListPassiveTarget = 0x4A
+)
+
+func TestSession_LowPower(t *testing.T) {
+	t.Parallel()
+
+	session, sim := newSimSession(t, &Config{
+		PollInterval:       5 * time.Millisecond,
+		CardRemovalTimeout: 50 * time.Millisecond,
+		LowPower:           &LowPowerConfig{SleepInterval: 20 * time.Millisecond},
+	})
+	events := session.Events()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = session.Start(ctx) }()
+
+	require.Eventually(t, func() bool {
+		return session.PowerStats().Sleeps >= 3
+	}, 2*time.Second, 5*time.Millisecond)
+
+	stats := session.PowerStats()
+	assert.Positive(t, stats.SleepTime)
+	assert.Positive(t, stats.LastWakeLatency)
+	assert.GreaterOrEqual(t, stats.MaxWakeLatency, stats.MeanWakeLatency())
+	assert.Less(t, stats.DutyCycle(), 1.0)
+	assert.GreaterOrEqual(t, sim.CommandCount(simCmdPowerDown), 3)
+
+	// A present card is polled at the normal rate without sleeping
+	tag := pn532sim.NewNTAG213(simUIDA)
+	sim.PlaceTag(tag)
+	assert.Equal(t, EventDetected, nextSessionEvent(t, events).Type)
+
+	sleeps := sim.CommandCount(simCmdPowerDown)
+	polls := sim.CommandCount(simCmdInListPassiveTarget)
+	time.Sleep(40 * time.Millisecond)
+	assert.Equal(t, sleeps, sim.CommandCount(simCmdPowerDown))
+	assert.Greater(t, sim.CommandCount(simCmdInListPassiveTarget), polls+2)
+
+	sim.RemoveTag(tag)
+	assert.Equal(t, EventRemoved, nextSessionEvent(t, events).Type)
+	require.Eventually(t, func() bool {
+		return sim.CommandCount(simCmdPowerDown) > sleeps
+	}, time.Second, 5*time.Millisecond, "sleeping resumes once the card is gone")
+}
+
+func TestSession_LowPowerWakeFailure(t *testing.T) {
+	t.Parallel()
+
+	device, mockTransport := createMockDeviceWithTransport(t)
+	mockTransport.SetResponse(simCmdPowerDown, []byte{0x17, 0x00})
+	mockTransport.SetResponse(simCmdInListPassiveTarget, []byte{0x4B, 0x00})
+	mockTransport.SetError(0x02, errors.New("no response after power down"))
+
+	session := NewSession(device, &Config{
+		PollInterval:       time.Millisecond,
+		CardRemovalTimeout: 10 * time.Millisecond,
+		MaxPollErrors:      1,
+		LowPower:           &LowPowerConfig{SleepInterval: time.Millisecond},
+	})
+
+	err := session.Start(context.Background())
+	require.ErrorIs(t, err, ErrTooManyPollErrors)
+	assert.Contains(t, err.Error(), "failed to wake reader")
+	assert.Equal(t, uint64(1), session.PowerStats().Sleeps)
+}
+
+func TestPowerStats(t *testing.T) {
+	t.Parallel()
+
+	assert.InDelta(t, 1.0, PowerStats{}.DutyCycle(), 0.001)
+	assert.Zero(t, PowerStats{}.MeanWakeLatency())
+
+	stats := PowerStats{
+		ActiveTime:       10 * time.Millisecond,
+		SleepTime:        90 * time.Millisecond,
+		TotalWakeLatency: 6 * time.Millisecond,
+		Sleeps:           3,
+	}
+	assert.InDelta(t, 0.1, stats.DutyCycle(), 0.001)
+	assert.Equal(t, 2*time.Millisecond, stats.MeanWakeLatency())
+}
@@ -0,0 +1,156 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package polling
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ZaparooProject/go-pn532"
+)
+
+// maxListedTargets is the number of targets InListPassiveTarget can return
+const maxListedTargets = 2
+
+// PresentTags returns the tags currently on the reader in multi-tag mode,
+// ordered by arrival
+func (s *Session) PresentTags() []PresentTag {
+	s.stateMutex.RLock()
+	defer s.stateMutex.RUnlock()
+
+	tags := make([]PresentTag, 0, len(s.presentTags))
+	for _, present := range s.presentTags {
+		tags = append(tags, *present)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].FirstSeen.Equal(tags[j].FirstSeen) {
+			return tags[i].Tag.UID < tags[j].Tag.UID
+		}
+		return tags[i].FirstSeen.Before(tags[j].FirstSeen)
+	})
+	return tags
+}
+
+// executeMultiTagCycle lists up to Config.MaxTags targets and updates the set
+// of present tags
+func (s *Session) executeMultiTagCycle(ctx context.Context) error {
+	maxTags := min(s.config.MaxTags, maxListedTargets)
+	tags, err := s.engine.poll(ctx, maxTags)
+	if err != nil {
+		err = fmt.Errorf("tag detection failed: %w", err)
+		s.handleMultiTagError(ctx, err)
+		return s.countPollingError(ctx, err)
+	}
+
+	s.pollSucceeded(ctx)
+	return s.updatePresentTags(ctx, tags, time.Now())
+}
+
+// handleMultiTagError reports a failed poll and, like the single tag mode,
+// treats serious device errors as all tags leaving
+func (s *Session) handleMultiTagError(ctx context.Context, err error) {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return
+	}
+
+	s.emit(ctx, Event{Type: EventError, Err: err})
+
+	s.stateMutex.Lock()
+	departed := make([]string, 0, len(s.presentTags))
+	for uid := range s.presentTags {
+		departed = append(departed, uid)
+		delete(s.presentTags, uid)
+	}
+	clear(s.tagPresence)
+	s.stateMutex.Unlock()
+
+	sort.Strings(departed)
+	s.notifyDeparted(ctx, departed)
+}
+
+// updatePresentTags records the tags seen in a poll. Without a presence
+// filter a tag departs once it has not been seen for
+// Config.CardRemovalTimeout, which rides out polls that miss one of several
+// tags in the field. With one every UID is filtered on its own.
+func (s *Session) updatePresentTags(ctx context.Context, tags []*pn532.DetectedTag, now time.Time) error {
+	var arrived []*pn532.DetectedTag
+	var departed []string
+
+	s.stateMutex.Lock()
+	if s.presence != nil {
+		arrived, departed = s.filterPresentTagsLocked(tags, now)
+	} else {
+		arrived, departed = s.timeoutPresentTagsLocked(tags, now)
+	}
+	s.stateMutex.Unlock()
+
+	// Departures first, so a swapped token reads as leave then arrive
+	sort.Strings(departed)
+	s.notifyDeparted(ctx, departed)
+
+	for _, tag := range arrived {
+		s.emit(ctx, Event{Type: EventDetected, Tag: tag, UID: tag.UID})
+		if s.OnCardDetected == nil {
+			continue
+		}
+		if err := s.safeCallCallback(s.OnCardDetected, tag, "OnCardDetected"); err != nil {
+			return fmt.Errorf("callback error during polling: %w", err)
+		}
+	}
+	return nil
+}
+
+// timeoutPresentTagsLocked updates the present tags, removing those not
+// seen for Config.CardRemovalTimeout. The caller must hold stateMutex.
+func (s *Session) timeoutPresentTagsLocked(
+	tags []*pn532.DetectedTag,
+	now time.Time,
+) (arrived []*pn532.DetectedTag, departed []string) {
+	for _, tag := range tags {
+		if present, ok := s.presentTags[tag.UID]; ok {
+			present.LastSeen = now
+			present.Tag = tag
+			continue
+		}
+		s.presentTags[tag.UID] = &PresentTag{Tag: tag, FirstSeen: now, LastSeen: now}
+		arrived = append(arrived, tag)
+	}
+	for uid, present := range s.presentTags {
+		if now.Sub(present.LastSeen) >= s.config.CardRemovalTimeout {
+			departed = append(departed, uid)
+			delete(s.presentTags, uid)
+		}
+	}
+	return arrived, departed
+}
+
+// notifyDeparted reports tags that left the reader
+func (s *Session) notifyDeparted(ctx context.Context, uids []string) {
+	for _, uid := range uids {
+		s.emit(ctx, Event{Type: EventRemoved, UID: uid})
+		if s.OnCardRemoved != nil {
+			s.OnCardRemoved()
+		}
+	}
+}
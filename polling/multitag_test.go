@@ -0,0 +1,169 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package polling
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ZaparooProject/go-pn532"
+	"github.com/ZaparooProject/go-pn532/pn532sim"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSession_MultiTag(t *testing.T) {
+	t.Parallel()
+
+	session, sim := newSimSession(t, &Config{
+		PollInterval:       5 * time.Millisecond,
+		CardRemovalTimeout: 50 * time.Millisecond,
+		MaxTags:            2,
+	})
+	events := session.Events()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = session.Start(ctx) }()
+
+	tokenA := pn532sim.NewNTAG213(simUIDA)
+	tokenB := pn532sim.NewNTAG213(simUIDB)
+	sim.PlaceTag(tokenA)
+	sim.PlaceTag(tokenB)
+
+	arrived := map[string]bool{}
+	for len(arrived) < 2 {
+		event := nextSessionEvent(t, events)
+		require.Equal(t, EventDetected, event.Type)
+		arrived[event.UID] = true
+	}
+	assert.True(t, arrived["04a1a2a3a4a5a6"])
+	assert.True(t, arrived["04b1b2b3b4b5b6"])
+	require.Len(t, session.PresentTags(), 2)
+
+	// Only the lifted token departs
+	sim.RemoveTag(tokenA)
+	removed := nextSessionEvent(t, events)
+	assert.Equal(t, EventRemoved, removed.Type)
+	assert.Equal(t, "04a1a2a3a4a5a6", removed.UID)
+
+	present := session.PresentTags()
+	require.Len(t, present, 1)
+	assert.Equal(t, "04b1b2b3b4b5b6", present[0].Tag.UID)
+	assert.False(t, session.GetState().Present, "single tag state is not used")
+}
+
+func TestSession_MultiTagRemovalTimeout(t *testing.T) {
+	t.Parallel()
+
+	device, _ := createMockDeviceWithTransport(t)
+	session := NewSession(device, &Config{CardRemovalTimeout: 100 * time.Millisecond, MaxTags: 2})
+	events := session.Events()
+
+	tagA := &pn532.DetectedTag{UID: "aa", Type: pn532.TagTypeNTAG}
+	tagB := &pn532.DetectedTag{UID: "bb", Type: pn532.TagTypeNTAG}
+	start := time.Now()
+	ctx := context.Background()
+
+	require.NoError(t, session.updatePresentTags(ctx, []*pn532.DetectedTag{tagA, tagB}, start))
+	// A poll that misses a tag is not a departure yet
+	require.NoError(t, session.updatePresentTags(ctx, []*pn532.DetectedTag{tagB}, start.Add(60*time.Millisecond)))
+	require.Len(t, session.PresentTags(), 2)
+
+	require.NoError(t, session.updatePresentTags(ctx, []*pn532.DetectedTag{tagB}, start.Add(120*time.Millisecond)))
+	require.Len(t, session.PresentTags(), 1)
+
+	var got []string
+	for len(events) > 0 {
+		event := <-events
+		got = append(got, event.Type.String()+":"+event.UID)
+	}
+	assert.Equal(t, []string{"detected:aa", "detected:bb", "removed:aa"}, got)
+}
+
+func TestSession_MultiTagPresence(t *testing.T) {
+	t.Parallel()
+
+	device, _ := createMockDeviceWithTransport(t)
+	session := NewSession(device, &Config{
+		// Not used with a presence filter
+		CardRemovalTimeout: time.Millisecond,
+		MaxTags:            2,
+		Presence:           &PresenceConfig{Window: 4, MissThreshold: 2, RedetectHits: 2, RedetectHoldoff: time.Second},
+	})
+	events := session.Events()
+
+	tagA := &pn532.DetectedTag{UID: "aa", Type: pn532.TagTypeNTAG}
+	tagB := &pn532.DetectedTag{UID: "bb", Type: pn532.TagTypeNTAG}
+	start := time.Now()
+	ctx := context.Background()
+	polls := [][]*pn532.DetectedTag{
+		{tagA, tagB},
+		// One miss of A is below the threshold
+		{tagB},
+		{tagA, tagB},
+		// The second miss in the window removes A only
+		{tagB},
+		// A just left, so it needs two sightings to return
+		{tagA, tagB},
+		{tagA, tagB},
+	}
+	wantPresent := []int{2, 2, 2, 1, 1, 2}
+	for i, poll := range polls {
+		require.NoError(t, session.updatePresentTags(ctx, poll, start.Add(time.Duration(i)*10*time.Millisecond)))
+		require.Len(t, session.PresentTags(), wantPresent[i], "poll %d", i)
+	}
+
+	var got []string
+	for len(events) > 0 {
+		event := <-events
+		got = append(got, event.Type.String()+":"+event.UID)
+	}
+	assert.Equal(t, []string{"detected:aa", "detected:bb", "removed:aa", "detected:aa"}, got)
+}
+
+func TestSession_MultiTagDeviceError(t *testing.T) {
+	t.Parallel()
+
+	device, mockTransport := createMockDeviceWithTransport(t)
+	session := NewSession(device, &Config{
+		PollInterval:       time.Millisecond,
+		CardRemovalTimeout: time.Second,
+		MaxTags:            2,
+		MaxPollErrors:      1,
+	})
+	events := session.Events()
+
+	tag := &pn532.DetectedTag{UID: "aa", Type: pn532.TagTypeNTAG}
+	require.NoError(t, session.updatePresentTags(context.Background(), []*pn532.DetectedTag{tag}, time.Now()))
+	mockTransport.SetError(0x4A, errors.New("device unplugged"))
+
+	require.ErrorIs(t, session.Start(context.Background()), ErrTooManyPollErrors)
+	assert.Empty(t, session.PresentTags())
+
+	var got []EventType
+	for len(events) > 0 {
+		got = append(got, (<-events).Type)
+	}
+	assert.Equal(t, []EventType{EventDetected, EventError, EventRemoved, EventReaderLost}, got)
+}
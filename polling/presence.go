@@ -0,0 +1,308 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package polling
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/ZaparooProject/go-pn532"
+)
+
+// missRateWeight is the weight of a single poll in the observed miss rate
+const missRateWeight = 0.05
+
+// PresenceStats reports the presence filter state of a session
+type PresenceStats struct {
+	// MissRate is the observed rate of polls missing a present card
+	MissRate float64
+	// MissThreshold is the threshold currently in effect
+	MissThreshold int
+}
+
+// missRate is the observed rate of polls missing a present card. A session
+// learns one rate for its reader and shares it between its filters.
+type missRate struct {
+	value float64
+	mu    sync.Mutex
+}
+
+// add folds the polls since the last sighting of a card into the rate, the
+// misses followed by the hit that showed they were spurious
+func (r *missRate) add(misses int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for range misses {
+		r.value += missRateWeight * (1 - r.value)
+	}
+	r.value -= missRateWeight * r.value
+}
+
+// get returns the current rate
+func (r *missRate) get() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.value
+}
+
+// presenceFilter decides the presence of a card from a sliding window of
+// polls. It is driven by the polling goroutine, only the shared miss rate
+// is read from other goroutines.
+type presenceFilter struct {
+	lastRemovedAt  time.Time
+	rate           *missRate
+	lastRemovedUID string
+	pendingUID     string
+	misses         []bool
+	config         PresenceConfig
+	next           int
+	pendingHits    int
+	// unconfirmedMisses are the misses since the card was last seen, they
+	// only count toward the miss rate once the card is seen again
+	unconfirmedMisses int
+}
+
+// newPresenceFilter creates a filter, filling in defaults. Filters given the
+// same rate learn it together, nil starts a new one.
+func newPresenceFilter(config PresenceConfig, rate *missRate) *presenceFilter {
+	if config.Window <= 0 {
+		config.Window = DefaultPresenceWindow
+	}
+	if config.MissThreshold <= 0 {
+		config.MissThreshold = DefaultMissThreshold
+	}
+	config.MissThreshold = min(config.MissThreshold, config.Window)
+	if config.RedetectHits <= 0 {
+		config.RedetectHits = DefaultRedetectHits
+	}
+	if config.RedetectHoldoff <= 0 {
+		config.RedetectHoldoff = DefaultRedetectHoldoff
+	}
+	if rate == nil {
+		rate = &missRate{}
+	}
+	return &presenceFilter{config: config, rate: rate, misses: make([]bool, config.Window)}
+}
+
+// Stats returns the current miss rate and threshold
+func (f *presenceFilter) Stats() PresenceStats {
+	rate := f.rate.get()
+	return PresenceStats{MissRate: rate, MissThreshold: f.threshold(rate)}
+}
+
+// threshold returns the miss threshold, raised by the expected number of
+// misses in a window when adaptive
+func (f *presenceFilter) threshold(rate float64) int {
+	threshold := f.config.MissThreshold
+	if f.config.Adaptive {
+		threshold += int(math.Ceil(rate * float64(f.config.Window)))
+	}
+	return min(threshold, f.config.Window)
+}
+
+// record adds a poll of a present card to the window
+func (f *presenceFilter) record(missed bool) {
+	f.misses[f.next] = missed
+	f.next = (f.next + 1) % len(f.misses)
+}
+
+// hit records a poll that saw the present card, which shows the misses
+// before it were spurious
+func (f *presenceFilter) hit() {
+	f.record(false)
+	f.rate.add(f.unconfirmedMisses)
+	f.unconfirmedMisses = 0
+}
+
+// miss records a poll that did not see the present card and reports whether
+// the card now counts as removed
+func (f *presenceFilter) miss() bool {
+	f.record(true)
+	f.unconfirmedMisses++
+
+	count := 0
+	for _, missed := range f.misses {
+		if missed {
+			count++
+		}
+	}
+	return count >= f.threshold(f.rate.get())
+}
+
+// removed clears the window and remembers the card for hysteresis. The
+// misses that ended in the removal were real and don't count toward the
+// miss rate.
+func (f *presenceFilter) removed(uid string, now time.Time) {
+	clear(f.misses)
+	f.unconfirmedMisses = 0
+	f.lastRemovedUID = uid
+	f.lastRemovedAt = now
+	f.pendingUID = ""
+	f.pendingHits = 0
+}
+
+// noCard records a poll without any card while none is present
+func (f *presenceFilter) noCard() {
+	f.pendingUID = ""
+	f.pendingHits = 0
+}
+
+// confirmArrival reports whether a newly seen card counts as present. A card
+// that was removed within RedetectHoldoff needs RedetectHits consecutive
+// sightings, any other card is present right away.
+func (f *presenceFilter) confirmArrival(uid string, now time.Time) bool {
+	if uid != f.lastRemovedUID || now.Sub(f.lastRemovedAt) >= f.config.RedetectHoldoff {
+		f.noCard()
+		return true
+	}
+
+	if f.pendingUID != uid {
+		f.pendingUID = uid
+		f.pendingHits = 0
+	}
+	f.pendingHits++
+	if f.pendingHits < f.config.RedetectHits {
+		return false
+	}
+	f.noCard()
+	return true
+}
+
+// filterPresentTagsLocked updates the present tags with a presence filter
+// per UID: a tag departs once enough polls have missed it, and one that just
+// departed needs RedetectHits sightings to return. Filters are dropped once
+// their tag is gone and past RedetectHoldoff. The caller must hold
+// stateMutex.
+func (s *Session) filterPresentTagsLocked(
+	tags []*pn532.DetectedTag,
+	now time.Time,
+) (arrived []*pn532.DetectedTag, departed []string) {
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		seen[tag.UID] = true
+		filter, ok := s.tagPresence[tag.UID]
+		if !ok {
+			filter = newPresenceFilter(*s.config.Presence, s.presence.rate)
+			s.tagPresence[tag.UID] = filter
+		}
+		if present, ok := s.presentTags[tag.UID]; ok {
+			filter.hit()
+			present.LastSeen = now
+			present.Tag = tag
+			continue
+		}
+		if filter.confirmArrival(tag.UID, now) {
+			s.presentTags[tag.UID] = &PresentTag{Tag: tag, FirstSeen: now, LastSeen: now}
+			arrived = append(arrived, tag)
+		}
+	}
+
+	for uid, filter := range s.tagPresence {
+		if seen[uid] {
+			continue
+		}
+		if _, ok := s.presentTags[uid]; !ok {
+			filter.noCard()
+			if now.Sub(filter.lastRemovedAt) >= filter.config.RedetectHoldoff {
+				delete(s.tagPresence, uid)
+			}
+			continue
+		}
+		if filter.miss() {
+			filter.removed(uid, now)
+			delete(s.presentTags, uid)
+			departed = append(departed, uid)
+		}
+	}
+	return arrived, departed
+}
+
+// PresenceStats returns the presence filter state, zero without
+// Config.Presence
+func (s *Session) PresenceStats() PresenceStats {
+	if s.presence == nil {
+		return PresenceStats{}
+	}
+	return s.presence.Stats()
+}
+
+// armRemovalLocked starts the removal timer for the present card, with a
+// presence filter removal is decided by the polls instead. The caller must
+// hold stateMutex.
+func (s *Session) armRemovalLocked(ctx context.Context, grace bool) {
+	callback := func() {
+		s.handleCardRemoval(ctx)
+	}
+	switch {
+	case s.presence != nil:
+		s.state.DetectionState = StateTagDetected
+		if grace {
+			s.state.DetectionState = StatePostReadGrace
+		}
+		s.state.LastSeenTime = time.Now()
+		safeTimerStop(s.state.RemovalTimer)
+		s.state.RemovalTimer = nil
+	case grace:
+		s.state.TransitionToPostReadGrace(s.config.CardRemovalTimeout, callback)
+	default:
+		s.state.TransitionToDetected(s.config.CardRemovalTimeout, callback)
+	}
+}
+
+// observeMiss feeds a poll without a card to the presence filter and removes
+// the card once enough polls have missed it
+func (s *Session) observeMiss(ctx context.Context) {
+	if s.presence == nil {
+		return
+	}
+
+	s.stateMutex.RLock()
+	present := s.state.Present
+	s.stateMutex.RUnlock()
+
+	if !present {
+		s.presence.noCard()
+		return
+	}
+	if s.presence.miss() {
+		s.handleCardRemoval(ctx)
+	}
+}
+
+// observeTag feeds a poll that found a tag to the presence filter and
+// reports whether the tag should be processed
+func (s *Session) observeTag(detectedTag *pn532.DetectedTag) bool {
+	if s.presence == nil {
+		return true
+	}
+
+	s.stateMutex.RLock()
+	present := s.state.Present
+	s.stateMutex.RUnlock()
+
+	if present {
+		s.presence.hit()
+		return true
+	}
+	return s.presence.confirmArrival(detectedTag.UID, time.Now())
+}
@@ -0,0 +1,215 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package polling
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ZaparooProject/go-pn532"
+	"github.com/ZaparooProject/go-pn532/pn532sim"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPresenceFilter_Miss(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		polls   string
+		config  PresenceConfig
+		removed bool
+	}{
+		{name: "Threshold_Reached", config: PresenceConfig{Window: 5, MissThreshold: 3}, polls: "xxx", removed: true},
+		{name: "Below_Threshold", config: PresenceConfig{Window: 5, MissThreshold: 3}, polls: "xx", removed: false},
+		{name: "Misses_Within_Window", config: PresenceConfig{Window: 5, MissThreshold: 3}, polls: "x.x.x", removed: true},
+		{name: "Misses_Spread_Out", config: PresenceConfig{Window: 3, MissThreshold: 2}, polls: "x..x..x", removed: false},
+		{name: "Defaults", config: PresenceConfig{}, polls: "xxx", removed: true},
+		{name: "Threshold_Capped_At_Window", config: PresenceConfig{Window: 2, MissThreshold: 5}, polls: "xx", removed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			filter := newPresenceFilter(tt.config, nil)
+			removed := false
+			for _, poll := range tt.polls {
+				if poll == '.' {
+					filter.hit()
+					continue
+				}
+				removed = filter.miss()
+			}
+			assert.Equal(t, tt.removed, removed)
+		})
+	}
+}
+
+func TestPresenceFilter_Redetect(t *testing.T) {
+	t.Parallel()
+
+	filter := newPresenceFilter(PresenceConfig{RedetectHits: 3, RedetectHoldoff: time.Second}, nil)
+	now := time.Now()
+	filter.removed("aa", now)
+
+	assert.True(t, filter.confirmArrival("bb", now), "other cards are present right away")
+	assert.False(t, filter.confirmArrival("aa", now))
+	assert.False(t, filter.confirmArrival("aa", now))
+	filter.noCard()
+	assert.False(t, filter.confirmArrival("aa", now), "sightings must be consecutive")
+	assert.False(t, filter.confirmArrival("aa", now))
+	assert.True(t, filter.confirmArrival("aa", now))
+
+	filter.removed("aa", now)
+	assert.True(t, filter.confirmArrival("aa", now.Add(time.Second)), "holdoff expired")
+}
+
+func TestPresenceFilter_Adaptive(t *testing.T) {
+	t.Parallel()
+
+	fixed := newPresenceFilter(PresenceConfig{Window: 10, MissThreshold: 2}, nil)
+	adaptive := newPresenceFilter(PresenceConfig{Window: 10, MissThreshold: 2, Adaptive: true}, nil)
+	for i := range 200 {
+		for _, filter := range []*presenceFilter{fixed, adaptive} {
+			if i%3 == 0 {
+				_ = filter.miss()
+			} else {
+				filter.hit()
+			}
+		}
+	}
+
+	assert.InDelta(t, 1.0/3, adaptive.Stats().MissRate, 0.05)
+	assert.Equal(t, 2, fixed.Stats().MissThreshold)
+	assert.Equal(t, 6, adaptive.Stats().MissThreshold)
+
+	// The usual miss rate removes the card at the fixed threshold only
+	assert.True(t, fixed.miss())
+	assert.False(t, adaptive.miss())
+}
+
+func TestPresenceFilter_RealRemovals(t *testing.T) {
+	t.Parallel()
+
+	filter := newPresenceFilter(PresenceConfig{Window: 10, MissThreshold: 2, Adaptive: true}, nil)
+	now := time.Now()
+	for range 50 {
+		require.True(t, filter.confirmArrival("aa", now.Add(time.Minute)))
+		filter.hit()
+		filter.hit()
+		for removed := false; !removed; {
+			removed = filter.miss()
+		}
+		filter.removed("aa", now)
+	}
+
+	// Misses that end in a removal don't raise the threshold
+	assert.Zero(t, filter.Stats().MissRate)
+	assert.Equal(t, 2, filter.Stats().MissThreshold)
+}
+
+func TestSession_MultiTagPresenceAdaptive(t *testing.T) {
+	t.Parallel()
+
+	device, _ := createMockDeviceWithTransport(t)
+	session := NewSession(device, &Config{
+		MaxTags:  2,
+		Presence: &PresenceConfig{Window: 10, MissThreshold: 4, Adaptive: true},
+	})
+	ctx := context.Background()
+	now := time.Now()
+	tagA := &pn532.DetectedTag{UID: "aa", Type: pn532.TagTypeNTAG}
+	tagB := &pn532.DetectedTag{UID: "bb", Type: pn532.TagTypeNTAG}
+
+	// The reader misses every third poll of a tag that stays
+	for i := range 150 {
+		polled := []*pn532.DetectedTag{tagA}
+		if i%3 == 2 {
+			polled = nil
+		}
+		require.NoError(t, session.updatePresentTags(ctx, polled, now))
+	}
+	stats := session.PresenceStats()
+	assert.InDelta(t, 1.0/3, stats.MissRate, 0.05)
+	assert.Equal(t, 8, stats.MissThreshold)
+
+	// A new tag starts with the rate learned for the reader
+	require.NoError(t, session.updatePresentTags(ctx, []*pn532.DetectedTag{tagA, tagB}, now))
+	for range 5 {
+		require.NoError(t, session.updatePresentTags(ctx, []*pn532.DetectedTag{tagA}, now))
+	}
+	assert.Len(t, session.PresentTags(), 2)
+}
+
+func TestSession_Presence(t *testing.T) {
+	t.Parallel()
+
+	session, sim := newSimSession(t, &Config{
+		PollInterval: 5 * time.Millisecond,
+		// Not used with a presence filter
+		CardRemovalTimeout: time.Millisecond,
+		Presence:           &PresenceConfig{Window: 20, MissThreshold: 20, RedetectHits: 3},
+	})
+	events := session.Events()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = session.Start(ctx) }()
+
+	tag := pn532sim.NewNTAG213(simUIDA)
+	sim.PlaceTag(tag)
+	assert.Equal(t, EventDetected, nextSessionEvent(t, events).Type)
+
+	// A brief dropout doesn't remove the card
+	polls := sim.CommandCount(simCmdInListPassiveTarget)
+	sim.RemoveTag(tag)
+	require.Eventually(t, func() bool {
+		return sim.CommandCount(simCmdInListPassiveTarget) >= polls+3
+	}, time.Second, time.Millisecond)
+	sim.PlaceTag(tag)
+	time.Sleep(20 * time.Millisecond)
+	assert.True(t, session.GetState().Present)
+	select {
+	case event := <-events:
+		assert.Failf(t, "unexpected event", "got %s", event.Type)
+	default:
+	}
+
+	sim.RemoveTag(tag)
+	removed := nextSessionEvent(t, events)
+	assert.Equal(t, EventRemoved, removed.Type)
+	assert.Equal(t, "04a1a2a3a4a5a6", removed.UID)
+
+	sim.PlaceTag(tag)
+	assert.Equal(t, EventDetected, nextSessionEvent(t, events).Type)
+	assert.Equal(t, 20, session.PresenceStats().MissThreshold)
+	assert.Positive(t, session.PresenceStats().MissRate)
+}
+
+func TestSession_PresenceStatsWithoutFilter(t *testing.T) {
+	t.Parallel()
+
+	session := NewSession(nil, DefaultConfig())
+	assert.Equal(t, PresenceStats{}, session.PresenceStats())
+}
@@ -42,10 +42,25 @@ type Session struct {
 	resumeChan     chan struct{}
 	ackChan        chan struct{}
 	actor          *DeviceActor
+	engine         *engine
+	presentTags    map[string]*PresentTag
+	presence       *presenceFilter
+	tagPresence    map[string]*presenceFilter
+	health         *healthMonitor
+	events         chan Event
+	closing        chan struct{}
+	awakeSince     time.Time
 	state          CardState
+	powerStats     PowerStats
 	stateMutex     sync.RWMutex
 	writeMutex     sync.Mutex
+	eventsMutex    sync.RWMutex
+	powerMutex     sync.Mutex
+	pollErrors     int
+	droppedEvents  atomic.Uint64
+	closeOnce      sync.Once
 	isPaused       atomic.Bool
+	eventsClosed   bool
 }
 
 // NewSession creates a new card monitoring session
@@ -53,14 +68,25 @@ func NewSession(device *pn532.Device, config *Config) *Session {
 	if config == nil {
 		config = DefaultConfig()
 	}
-	return &Session{
-		device:     device,
-		config:     config,
-		state:      CardState{},
-		pauseChan:  make(chan struct{}, 1),
-		resumeChan: make(chan struct{}, 1),
-		ackChan:    make(chan struct{}, 1),
+	session := &Session{
+		device:      device,
+		config:      config,
+		state:       CardState{},
+		pauseChan:   make(chan struct{}, 1),
+		resumeChan:  make(chan struct{}, 1),
+		ackChan:     make(chan struct{}, 1),
+		closing:     make(chan struct{}),
+		presentTags: make(map[string]*PresentTag),
+		engine:      newEngine(device, config),
 	}
+	if config.Presence != nil {
+		session.presence = newPresenceFilter(*config.Presence, nil)
+		session.tagPresence = make(map[string]*presenceFilter)
+	}
+	if config.Health != nil {
+		session.health = newHealthMonitor(*config.Health)
+	}
+	return session
 }
 
 // NewActorBasedSession creates a session using DeviceActor underneath
@@ -70,17 +96,18 @@ func NewActorBasedSession(device *pn532.Device, config *Config) *Session {
 	// Create DeviceActor with callbacks that delegate to session callbacks
 	callbacks := DeviceCallbacks{
 		OnCardDetected: func(tag *pn532.DetectedTag) error {
+			session.actorCardSeen(tag)
+			session.emit(context.Background(), Event{Type: EventDetected, Tag: tag, UID: tag.UID})
 			if session.OnCardDetected != nil {
 				return session.OnCardDetected(tag)
 			}
 			return nil
 		},
 		OnCardRemoved: func() {
-			if session.OnCardRemoved != nil {
-				session.OnCardRemoved()
-			}
+			session.handleCardRemoval(context.Background())
 		},
 		OnCardChanged: func(tag *pn532.DetectedTag) error {
+			session.emit(context.Background(), Event{Type: EventChanged, Tag: tag, UID: tag.UID})
 			if session.OnCardChanged != nil {
 				return session.OnCardChanged(tag)
 			}
@@ -88,10 +115,26 @@ func NewActorBasedSession(device *pn532.Device, config *Config) *Session {
 		},
 	}
 
+	callbacks.OnReaderLost = func(err error) {
+		session.emit(context.Background(), Event{Type: EventReaderLost, Err: err})
+	}
+
 	session.actor = NewDeviceActor(device, config, callbacks)
 	return session
 }
 
+// actorCardSeen records the card found by the device actor so its removal
+// can be reported with the UID
+func (s *Session) actorCardSeen(tag *pn532.DetectedTag) {
+	s.stateMutex.Lock()
+	defer s.stateMutex.Unlock()
+	s.state.Present = true
+	s.state.LastUID = tag.UID
+	s.state.LastType = string(tag.Type)
+	s.state.LastSeenTime = time.Now()
+	s.state.DetectionState = StateTagDetected
+}
+
 // Start begins continuous monitoring for cards
 func (s *Session) Start(ctx context.Context) error {
 	// If we have an actor, delegate to it instead of using direct polling
@@ -99,7 +142,21 @@ func (s *Session) Start(ctx context.Context) error {
 		return s.actor.Start(ctx)
 	}
 	// Fall back to direct polling for regular sessions
-	return s.continuousPolling(ctx)
+	err := s.engine.supervise(ctx, s.continuousPolling, func(cause error) {
+		s.emit(ctx, Event{Type: EventRestart, Err: cause})
+	})
+	if errors.Is(err, ErrTooManyPollErrors) || errors.Is(err, ErrRestartsExhausted) || errors.Is(err, ErrReaderDead) {
+		s.emit(ctx, Event{Type: EventReaderLost, Err: err})
+	}
+	return err
+}
+
+// Metrics returns the polling metrics of the session
+func (s *Session) Metrics() DeviceMetrics {
+	if s.actor != nil {
+		return s.actor.GetMetrics()
+	}
+	return s.engine.metrics()
 }
 
 // GetState returns the current card state
@@ -119,8 +176,99 @@ func (s *Session) GetDeviceActor() *DeviceActor {
 	return s.actor
 }
 
+// Events returns the session event stream. The channel is created on the
+// first call, sessions nobody listens to don't buffer events. It is closed
+// by Close. Config.EventPolicy decides whether a slow consumer makes polling
+// wait or loses events, see DroppedEvents.
+func (s *Session) Events() <-chan Event {
+	s.eventsMutex.Lock()
+	defer s.eventsMutex.Unlock()
+
+	if s.events == nil {
+		size := s.config.EventBuffer
+		if size <= 0 {
+			size = DefaultEventBuffer
+		}
+		s.events = make(chan Event, size)
+		if s.eventsClosed {
+			close(s.events)
+		}
+	}
+	return s.events
+}
+
+// DroppedEvents returns how many events were discarded because the Events
+// channel was full
+func (s *Session) DroppedEvents() uint64 {
+	return s.droppedEvents.Load()
+}
+
+// emit sends an event to the Events channel, if anyone asked for it
+func (s *Session) emit(ctx context.Context, event Event) {
+	s.eventsMutex.RLock()
+	defer s.eventsMutex.RUnlock()
+	if s.events == nil || s.eventsClosed {
+		return
+	}
+
+	event.Time = time.Now()
+	if s.device != nil {
+		event.Reader = s.device.StableID()
+	}
+
+	switch s.config.EventPolicy {
+	case EventPolicyBlock:
+		select {
+		case s.events <- event:
+		case <-ctx.Done():
+		case <-s.closing:
+		}
+	case EventPolicyDropNewest:
+		select {
+		case s.events <- event:
+		default:
+			s.droppedEvents.Add(1)
+		}
+	default:
+		s.sendDroppingOldest(event)
+	}
+}
+
+// sendDroppingOldest sends an event, discarding buffered events until it fits
+func (s *Session) sendDroppingOldest(event Event) {
+	for {
+		select {
+		case s.events <- event:
+			return
+		default:
+		}
+		select {
+		case <-s.events:
+			s.droppedEvents.Add(1)
+		default:
+		}
+	}
+}
+
+// closeEvents closes the Events channel once no emitter can use it
+func (s *Session) closeEvents() {
+	s.closeOnce.Do(func() {
+		// Unblock emitters waiting under EventPolicyBlock first
+		close(s.closing)
+
+		s.eventsMutex.Lock()
+		defer s.eventsMutex.Unlock()
+		s.eventsClosed = true
+		if s.events != nil {
+			close(s.events)
+		}
+	})
+}
+
 // Close cleans up the monitor resources
 func (s *Session) Close() error {
+	defer s.closeEvents()
+
 	// Stop any running removal timer
 	s.stateMutex.Lock()
 	if s.state.RemovalTimer != nil {
@@ -313,11 +461,17 @@ func (s *Session) WriteToTag(
 	return writeFn(writeCtx, tag)
 }
 
-// continuousPolling runs continuous InAutoPoll monitoring
+// continuousPolling polls with the configured PollStrategy until ctx is done
 func (s *Session) continuousPolling(ctx context.Context) error {
 	ticker := time.NewTicker(s.config.PollInterval)
 	defer ticker.Stop()
 
+	if s.config.LowPower != nil {
+		s.powerMutex.Lock()
+		s.awakeSince = time.Now()
+		s.powerMutex.Unlock()
+	}
+
 	for {
 		if err := s.handleContextAndPause(ctx); err != nil {
 			return err
@@ -327,6 +481,13 @@ func (s *Session) continuousPolling(ctx context.Context) error {
 			return err
 		}
 
+		if s.shouldPowerDown() {
+			if err := s.sleepBetweenPolls(ctx); err != nil {
+				return err
+			}
+			continue
+		}
+
 		if err := s.waitForNextPollOrPause(ctx, ticker); err != nil {
 			return err
 		}
@@ -335,15 +496,26 @@ func (s *Session) continuousPolling(ctx context.Context) error {
 
 // executeSinglePollingCycle performs one polling cycle and processes results
 func (s *Session) executeSinglePollingCycle(ctx context.Context) error {
+	if s.config.MaxTags > 1 {
+		return s.executeMultiTagCycle(ctx)
+	}
+
 	detectedTag, err := s.performSinglePoll(ctx)
 	if err != nil {
 		if !errors.Is(err, ErrNoTagInPoll) {
-			s.handlePollingError(err)
+			s.handlePollingError(ctx, err)
+			return s.countPollingError(ctx, err)
 		}
+		s.pollSucceeded(ctx)
+		s.observeMiss(ctx)
+		return nil
+	}
+	s.pollSucceeded(ctx)
+	if !s.observeTag(detectedTag) {
 		return nil
 	}
 
-	if err := s.processPollingResults(detectedTag); err != nil {
+	if err := s.processPollingResults(ctx, detectedTag); err != nil {
 		return fmt.Errorf("callback error during polling: %w", err)
 	}
 	return nil
@@ -398,7 +570,7 @@ func (s *Session) waitForResume(ctx context.Context) error {
 func (s *Session) performSinglePoll(ctx context.Context) (*pn532.DetectedTag, error) {
 	// Use immediate polling without timeout to avoid double delay
 	// The polling interval is handled in the main loop
-	tags, err := s.device.InListPassiveTargetContext(ctx, 1, 0x00)
+	tags, err := s.engine.poll(ctx, 1)
 	if err != nil {
 		return nil, fmt.Errorf("tag detection failed: %w", err)
 	}
@@ -412,7 +584,7 @@ func (s *Session) performSinglePoll(ctx context.Context) (*pn532.DetectedTag, er
 }
 
 // handlePollingError handles errors from polling operations
-func (s *Session) handlePollingError(err error) {
+func (s *Session) handlePollingError(ctx context.Context, err error) {
 	if errors.Is(err, context.DeadlineExceeded) {
 		// Timeout is normal - timer will handle removal detection
 		return
@@ -422,35 +594,74 @@ func (s *Session) handlePollingError(err error) {
 		return
 	}
 
+	s.emit(ctx, Event{Type: EventError, Err: err})
+
 	// For serious device errors, trigger immediate card removal
 	// This handles cases like device disconnection
-	s.handleCardRemoval()
+	s.handleCardRemoval(ctx)
+}
+
+// pollSucceeded resets the failure tracking after a successful poll
+func (s *Session) pollSucceeded(ctx context.Context) {
+	s.pollErrors = 0
+	s.healthPollSucceeded(ctx)
+}
+
+// countPollingError tracks consecutive polling failures and stops polling
+// once Config.MaxPollErrors is reached, see pollErrorLimit. With
+// Config.Health the health monitor takes over.
+func (s *Session) countPollingError(ctx context.Context, err error) error {
+	if s.health != nil && ctx.Err() == nil {
+		return s.healthPollFailed(ctx, err)
+	}
+
+	limit := pollErrorLimit(s.config)
+	if ctx.Err() != nil || limit <= 0 {
+		return nil
+	}
+
+	s.pollErrors++
+	if s.pollErrors >= limit {
+		failures := s.pollErrors
+		s.pollErrors = 0
+		return fmt.Errorf("%w (%d): %w", ErrTooManyPollErrors, failures, err)
+	}
+	return nil
 }
 
 // handleCardRemoval handles card removal state changes
-func (s *Session) handleCardRemoval() {
+func (s *Session) handleCardRemoval(ctx context.Context) {
 	s.stateMutex.Lock()
 	wasPresent := s.state.Present
+	uid := s.state.LastUID
 	if wasPresent {
 		s.state.TransitionToIdle()
 	}
 	s.stateMutex.Unlock()
 
-	// Call callback outside the lock to avoid potential deadlocks
-	if wasPresent && s.OnCardRemoved != nil {
+	if !wasPresent {
+		return
+	}
+	if s.presence != nil {
+		s.presence.removed(uid, time.Now())
+	}
+
+	// Notify outside the lock to avoid potential deadlocks
+	s.emit(ctx, Event{Type: EventRemoved, UID: uid})
+	if s.OnCardRemoved != nil {
 		s.OnCardRemoved()
 	}
 }
 
 // processPollingResults processes the detected tag and returns any callback errors
-func (s *Session) processPollingResults(detectedTag *pn532.DetectedTag) error {
+func (s *Session) processPollingResults(ctx context.Context, detectedTag *pn532.DetectedTag) error {
 	if detectedTag == nil {
 		// No tag detected - removal handled by timer, nothing to do here
 		return nil
 	}
 
 	// Card present - handle state transitions
-	cardChanged, err := s.updateCardState(detectedTag)
+	cardChanged, err := s.updateCardState(ctx, detectedTag)
 	if err != nil {
 		return err
 	}
@@ -459,21 +670,19 @@ func (s *Session) processPollingResults(detectedTag *pn532.DetectedTag) error {
 	s.stateMutex.Lock()
 	shouldTransition := s.state.DetectionState != StateReading
 	if shouldTransition {
-		s.state.TransitionToDetected(s.config.CardRemovalTimeout, func() {
-			s.handleCardRemoval()
-		})
+		s.armRemovalLocked(ctx, false)
 	}
 	s.stateMutex.Unlock()
 
 	if cardChanged || s.shouldTestCard(detectedTag.UID) {
-		s.testAndRecordCard(detectedTag)
+		s.testAndRecordCard(ctx, detectedTag)
 	}
 
 	return nil
 }
 
 // safeCallCallback executes a callback with panic recovery
-func (*Session) safeCallCallback(
+func (s *Session) safeCallCallback(
 	callback func(*pn532.DetectedTag) error,
 	tag *pn532.DetectedTag,
 	callbackName string,
@@ -488,13 +697,14 @@ func (*Session) safeCallCallback(
 		callbackErr = callback(tag)
 	}()
 	if callbackErr != nil {
+		s.engine.callbackErrors.Add(1)
 		return fmt.Errorf("%s callback failed: %w", callbackName, callbackErr)
 	}
 	return nil
 }
 
 // updateCardState updates the card state and returns whether the card changed and any callback error
-func (s *Session) updateCardState(detectedTag *pn532.DetectedTag) (bool, error) {
+func (s *Session) updateCardState(ctx context.Context, detectedTag *pn532.DetectedTag) (bool, error) {
 	currentUID := detectedTag.UID
 	cardType := string(detectedTag.Type)
 
@@ -504,7 +714,12 @@ func (s *Session) updateCardState(detectedTag *pn532.DetectedTag) (bool, error)
 	wasChanged := wasPresent && s.state.LastUID != currentUID
 	s.stateMutex.RUnlock()
 
-	// Call callbacks outside of any locks with panic recovery
+	// Notify outside of any locks, callbacks with panic recovery
+	if !wasPresent {
+		s.emit(ctx, Event{Type: EventDetected, Tag: detectedTag, UID: currentUID})
+	} else if wasChanged {
+		s.emit(ctx, Event{Type: EventChanged, Tag: detectedTag, UID: currentUID})
+	}
 	if !wasPresent && s.OnCardDetected != nil {
 		if err := s.safeCallCallback(s.OnCardDetected, detectedTag, "OnCardDetected"); err != nil {
 			return false, err
@@ -545,18 +760,71 @@ func (s *Session) shouldTestCard(currentUID string) bool {
 }
 
 // testAndRecordCard tests the card and records the result
-func (s *Session) testAndRecordCard(detectedTag *pn532.DetectedTag) {
+func (s *Session) testAndRecordCard(ctx context.Context, detectedTag *pn532.DetectedTag) {
 	s.stateMutex.Lock()
-	defer s.stateMutex.Unlock()
-
 	// Transition to reading state to prevent removal timer from firing during long reads
 	s.state.TransitionToReading()
-
 	// Mark as tested to prevent repeated testing
 	s.state.TestedUID = detectedTag.UID
+	s.stateMutex.Unlock()
+
+	if s.config.ReadNDEF {
+		s.readCard(ctx, detectedTag)
+	}
+
+	s.stateMutex.Lock()
+	defer s.stateMutex.Unlock()
+	if !s.state.Present {
+		return
+	}
 
 	// Transition to post-read grace period with shorter timeout
-	s.state.TransitionToPostReadGrace(s.config.CardRemovalTimeout, func() {
-		s.handleCardRemoval()
-	})
+	s.armRemovalLocked(ctx, true)
+}
+
+// readCard reads the NDEF message of a new card and reports it as an
+// EventRead. Tag reads can't be interrupted, so on timeout the error is
+// reported right away but readCard still blocks, and polling stays
+// suspended, until the read has finished.
+func (s *Session) readCard(ctx context.Context, detectedTag *pn532.DetectedTag) {
+	timeout := s.config.ReadTimeout
+	if timeout <= 0 {
+		timeout = DefaultReadTimeout
+	}
+	readCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type readResult struct {
+		message *pn532.NDEFMessage
+		err     error
+	}
+	done := make(chan readResult, 1)
+	go func() {
+		message, err := s.readNDEF(detectedTag)
+		done <- readResult{message: message, err: err}
+	}()
+
+	event := Event{Type: EventRead, Tag: detectedTag, UID: detectedTag.UID}
+	select {
+	case result := <-done:
+		event.Message, event.Err = result.message, result.err
+		s.emit(ctx, event)
+	case <-readCtx.Done():
+		event.Err = fmt.Errorf("NDEF read did not finish: %w", readCtx.Err())
+		s.emit(ctx, event)
+		<-done
+	}
+}
+
+// readNDEF reads the NDEF message of a detected tag
+func (s *Session) readNDEF(detectedTag *pn532.DetectedTag) (*pn532.NDEFMessage, error) {
+	tag, err := s.device.CreateTag(detectedTag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tag: %w", err)
+	}
+	message, err := tag.ReadNDEF()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read NDEF: %w", err)
+	}
+	return message, nil
 }
@@ -897,3 +897,24 @@ func TestSession_WriteToNextTag(t *testing.T) {
 		assert.False(t, session.isPaused.Load()) // Should be resumed after cancelled write
 	})
 }
+
+func TestSession_MaxPollErrors(t *testing.T) {
+	t.Parallel()
+
+	device, mockTransport := createMockDeviceWithTransport(t)
+	mockTransport.SetError(0x4A, errors.New("device unplugged"))
+
+	session := NewSession(device, &Config{
+		PollInterval:       time.Millisecond,
+		CardRemovalTimeout: 10 * time.Millisecond,
+		MaxPollErrors:      3,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	err := session.Start(ctx)
+	require.ErrorIs(t, err, ErrTooManyPollErrors)
+	assert.Contains(t, err.Error(), "device unplugged")
+	assert.Equal(t, 3, mockTransport.GetCallCount(0x4A))
+}
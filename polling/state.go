@@ -23,6 +23,8 @@ package polling
 import (
 	"errors"
 	"time"
+
+	"github.com/ZaparooProject/go-pn532"
 )
 
 // CardDetectionState represents the finite state machine for card detection
@@ -47,9 +49,20 @@ type CardState struct {
 	Present        bool
 }
 
+// PresentTag is a tag on the reader in multi-tag mode, see Config.MaxTags
+type PresentTag struct {
+	FirstSeen time.Time
+	LastSeen  time.Time
+	Tag       *pn532.DetectedTag
+}
+
 // ErrNoTagInPoll indicates no tag was detected during polling (not an error condition)
 var ErrNoTagInPoll = errors.New("no tag detected in polling cycle")
 
+// ErrTooManyPollErrors is returned by Session.Start once Config.MaxPollErrors
+// consecutive polls have failed
+var ErrTooManyPollErrors = errors.New("too many consecutive polling errors")
+
 // safeTimerStop safely stops a timer and drains its channel to prevent resource leaks
 func safeTimerStop(timer *time.Timer) {
 	if timer != nil {
@@ -0,0 +1,152 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package polling
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/ZaparooProject/go-pn532"
+)
+
+// PollStrategy finds the tags in the reader's field, it is called once per
+// poll with the number of tags the session tracks
+type PollStrategy interface {
+	Poll(ctx context.Context, device *pn532.Device, maxTags int) ([]*pn532.DetectedTag, error)
+}
+
+// PollFunc adapts a function to a PollStrategy
+type PollFunc func(ctx context.Context, device *pn532.Device, maxTags int) ([]*pn532.DetectedTag, error)
+
+// Poll calls f
+func (f PollFunc) Poll(ctx context.Context, device *pn532.Device, maxTags int) ([]*pn532.DetectedTag, error) {
+	return f(ctx, device, maxTags)
+}
+
+// ListPassiveTargetStrategy polls ISO14443A tags (NTAG, MIFARE) with a
+// single InListPassiveTarget. This is the default strategy.
+type ListPassiveTargetStrategy struct{}
+
+// Poll implements PollStrategy
+func (ListPassiveTargetStrategy) Poll(
+	ctx context.Context, device *pn532.Device, maxTags int,
+) ([]*pn532.DetectedTag, error) {
+	tags, err := device.InListPassiveTargetContext(ctx, byte(maxTags), 0x00)
+	if err != nil {
+		return nil, fmt.Errorf("InListPassiveTarget poll failed: %w", err)
+	}
+	return tags, nil
+}
+
+// defaultAutoPollTargets are polled by an AutoPollStrategy without Targets
+var defaultAutoPollTargets = []pn532.AutoPollTarget{
+	pn532.AutoPollGeneric106kbps,
+	pn532.AutoPollFeliCa212,
+	pn532.AutoPollFeliCa424,
+}
+
+// AutoPollStrategy polls with InAutoPoll, the PN532 cycles through the
+// target types itself so one poll covers ISO14443A and FeliCa tags
+type AutoPollStrategy struct {
+	// Targets are the target types to poll for, nil polls ISO14443A and
+	// FeliCa
+	Targets []pn532.AutoPollTarget
+	// Period is the time spent on each target type in units of 150ms, zero
+	// uses 1
+	Period byte
+	// PollCount is how many times the PN532 cycles through Targets before
+	// the poll returns empty, zero uses 1
+	PollCount byte
+}
+
+// Poll implements PollStrategy
+func (a AutoPollStrategy) Poll(ctx context.Context, device *pn532.Device, maxTags int) ([]*pn532.DetectedTag, error) {
+	targets := a.Targets
+	if len(targets) == 0 {
+		targets = defaultAutoPollTargets
+	}
+	period := max(a.Period, 1)
+	pollCount := max(a.PollCount, 1)
+
+	tags, err := device.DetectTagsAutoPollContext(ctx, byte(maxTags), pollCount, period, targets)
+	if err != nil {
+		return nil, fmt.Errorf("InAutoPoll poll failed: %w", err)
+	}
+	return tags, nil
+}
+
+// feliCaTargets are the target types polled by FeliCaStrategy
+var feliCaTargets = []pn532.AutoPollTarget{pn532.AutoPollFeliCa212, pn532.AutoPollFeliCa424}
+
+// FeliCaStrategy polls FeliCa tags at 212 and 424 kbps
+type FeliCaStrategy struct{}
+
+// Poll implements PollStrategy
+func (FeliCaStrategy) Poll(ctx context.Context, device *pn532.Device, maxTags int) ([]*pn532.DetectedTag, error) {
+	tags, err := device.DetectTagsAutoPollContext(ctx, byte(maxTags), 1, 1, feliCaTargets)
+	if err != nil {
+		return nil, fmt.Errorf("FeliCa poll failed: %w", err)
+	}
+	return tags, nil
+}
+
+// strategyFor picks the poll strategy of a config: Strategy if set, InAutoPoll
+// for Config.Targets, otherwise the cheapest command covering
+// Config.TagTypes. ISO14443A-only sessions use InListPassiveTarget, sessions
+// including FeliCa use InAutoPoll in the priority order of the types.
+func strategyFor(config *Config) PollStrategy {
+	switch {
+	case config.Strategy != nil:
+		return config.Strategy
+	case len(config.Targets) > 0:
+		return AutoPollStrategy{Targets: config.Targets, Period: config.PollPeriod, PollCount: config.PollCount}
+	}
+
+	var targets []pn532.AutoPollTarget
+	feliCa := false
+	for _, tagType := range config.TagTypes {
+		switch tagType {
+		case pn532.TagTypeFeliCa:
+			feliCa = true
+			targets = appendTargets(targets, feliCaTargets...)
+		case pn532.TagTypeNTAG, pn532.TagTypeMIFARE:
+			targets = appendTargets(targets, pn532.AutoPollGeneric106kbps)
+		case pn532.TagTypeAny, pn532.TagTypeUnknown:
+			targets = appendTargets(targets, defaultAutoPollTargets...)
+			feliCa = true
+		}
+	}
+	if !feliCa {
+		return ListPassiveTargetStrategy{}
+	}
+	return AutoPollStrategy{Targets: targets, Period: config.PollPeriod, PollCount: config.PollCount}
+}
+
+// appendTargets appends the targets that are not in list yet
+func appendTargets(list []pn532.AutoPollTarget, targets ...pn532.AutoPollTarget) []pn532.AutoPollTarget {
+	for _, target := range targets {
+		if !slices.Contains(list, target) {
+			list = append(list, target)
+		}
+	}
+	return list
+}
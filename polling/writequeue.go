@@ -0,0 +1,352 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package polling
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ZaparooProject/go-pn532"
+)
+
+// Write queue defaults for zero WriteQueueConfig fields
+const (
+	DefaultWriteAttempts   = 3
+	DefaultWriteRetryDelay = 50 * time.Millisecond
+)
+
+var (
+	// ErrWriteQueueClosed is returned by WriteQueue.Enqueue after Close
+	ErrWriteQueueClosed = errors.New("write queue is closed")
+	// ErrVerifyFailed means the message read back differs from the one written
+	ErrVerifyFailed = errors.New("written NDEF message does not match")
+)
+
+// WriteJob is a message to write to one tag
+type WriteJob struct {
+	Message *pn532.NDEFMessage
+	// ID identifies the job in its WriteResult
+	ID string
+}
+
+// WriteResult reports the outcome of a WriteJob
+type WriteResult struct {
+	Err error
+	// Tag is the tag the job was written to, nil if no tag was found
+	Tag      *pn532.DetectedTag
+	Job      WriteJob
+	Attempts int
+}
+
+// WriteQueueConfig configures a WriteQueue
+type WriteQueueConfig struct {
+	// RetryDelay is the pause before retrying a transient error, zero uses
+	// DefaultWriteRetryDelay
+	RetryDelay time.Duration
+	// MaxAttempts is how often a write is tried on the same tag, across
+	// all jobs, zero uses DefaultWriteAttempts
+	MaxAttempts int
+	// ResultBuffer is the capacity of the Results channel, zero uses
+	// DefaultEventBuffer
+	ResultBuffer int
+}
+
+// WriteQueue writes a queue of NDEF messages, each to the next distinct tag
+// presented to the reader. Every write is verified by reading the message
+// back, transient errors are retried on the same tag. A tag the queue has
+// written and verified is not used again, so the finished tag can stay on
+// the reader while the next one is presented. A tag a job failed on is
+// tried again by the following jobs until MaxAttempts writes to it have
+// failed in total.
+//
+// Polling is paused while a job waits for its tag and resumes whenever the
+// queue is empty.
+type WriteQueue struct {
+	session *Session
+	results chan WriteResult
+	wake    chan struct{}
+	used    map[string]struct{}
+	failed  map[string]int
+	jobs    []WriteJob
+	config  WriteQueueConfig
+	mu      sync.Mutex
+	closed  bool
+}
+
+// NewWriteQueue creates a write queue on the session's reader, nil config
+// uses the defaults
+func (s *Session) NewWriteQueue(config *WriteQueueConfig) *WriteQueue {
+	queue := &WriteQueue{
+		session: s,
+		wake:    make(chan struct{}, 1),
+		used:    make(map[string]struct{}),
+		failed:  make(map[string]int),
+	}
+	if config != nil {
+		queue.config = *config
+	}
+	if queue.config.MaxAttempts <= 0 {
+		queue.config.MaxAttempts = DefaultWriteAttempts
+	}
+	if queue.config.RetryDelay <= 0 {
+		queue.config.RetryDelay = DefaultWriteRetryDelay
+	}
+	if queue.config.ResultBuffer <= 0 {
+		queue.config.ResultBuffer = DefaultEventBuffer
+	}
+	queue.results = make(chan WriteResult, queue.config.ResultBuffer)
+	return queue
+}
+
+// Enqueue adds jobs to the end of the queue
+func (q *WriteQueue) Enqueue(jobs ...WriteJob) error {
+	for _, job := range jobs {
+		if job.Message == nil || len(job.Message.Records) == 0 {
+			return fmt.Errorf("write job %q: no NDEF records to write", job.ID)
+		}
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return ErrWriteQueueClosed
+	}
+	q.jobs = append(q.jobs, jobs...)
+	q.signal()
+	return nil
+}
+
+// Close stops accepting jobs, Run returns once the queued jobs are done
+func (q *WriteQueue) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	q.signal()
+}
+
+// Pending returns the number of jobs not yet started
+func (q *WriteQueue) Pending() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.jobs)
+}
+
+// Results returns the per job results, one for every job in queue order.
+// The channel is closed when Run returns.
+func (q *WriteQueue) Results() <-chan WriteResult {
+	return q.results
+}
+
+// Run processes jobs until the queue is closed and empty or ctx is done.
+// Jobs left when ctx ends are not reported.
+func (q *WriteQueue) Run(ctx context.Context) error {
+	defer close(q.results)
+
+	for {
+		job, ok, err := q.next(ctx)
+		if err != nil || !ok {
+			return err
+		}
+
+		if err := q.report(ctx, q.process(ctx, job)); err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
+
+// report sends a result, a started job is reported even if ctx ended
+// during it as long as the channel has room
+func (q *WriteQueue) report(ctx context.Context, result WriteResult) error {
+	select {
+	case q.results <- result:
+		return nil
+	default:
+	}
+	select {
+	case q.results <- result:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// signal wakes Run, the caller must hold mu
+func (q *WriteQueue) signal() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// next waits for the next job, ok is false once the queue is closed and empty
+func (q *WriteQueue) next(ctx context.Context) (job WriteJob, ok bool, err error) {
+	for {
+		q.mu.Lock()
+		if len(q.jobs) > 0 {
+			job = q.jobs[0]
+			q.jobs = q.jobs[1:]
+			q.mu.Unlock()
+			return job, true, nil
+		}
+		closed := q.closed
+		q.mu.Unlock()
+
+		if closed {
+			return WriteJob{}, false, nil
+		}
+		select {
+		case <-q.wake:
+		case <-ctx.Done():
+			return WriteJob{}, false, ctx.Err()
+		}
+	}
+}
+
+// process waits for an unused tag with polling paused and writes the job
+func (q *WriteQueue) process(ctx context.Context, job WriteJob) WriteResult {
+	result := WriteResult{Job: job}
+	s := q.session
+
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+
+	if err := s.pauseWithAck(ctx); err != nil {
+		result.Err = fmt.Errorf("failed to pause polling: %w", err)
+		return result
+	}
+	defer s.Resume()
+
+	detectedTag, err := q.waitForUnusedTag(ctx)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Tag = detectedTag
+
+	uid := detectedTag.UID
+	result.Attempts, result.Err = q.write(ctx, detectedTag, job.Message, q.config.MaxAttempts-q.failed[uid])
+	if result.Err != nil {
+		q.failed[uid] += result.Attempts
+		return result
+	}
+	q.used[uid] = struct{}{}
+	delete(q.failed, uid)
+	return result
+}
+
+// usable reports whether a tag may take the next job: it hasn't been
+// written yet and has attempts left
+func (q *WriteQueue) usable(uid string) bool {
+	_, used := q.used[uid]
+	return !used && q.failed[uid] < q.config.MaxAttempts
+}
+
+// waitForUnusedTag polls until a usable tag is presented
+func (q *WriteQueue) waitForUnusedTag(ctx context.Context) (*pn532.DetectedTag, error) {
+	ticker := time.NewTicker(q.session.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// List every target so a finished tag left on the reader doesn't
+		// hide the next one
+		tags, err := q.session.engine.poll(ctx, maxListedTargets)
+		if err != nil && !pn532.IsRetryable(err) {
+			return nil, fmt.Errorf("tag detection failed: %w", err)
+		}
+		for i, detectedTag := range tags {
+			if !q.usable(detectedTag.UID) {
+				continue
+			}
+			if i == 0 {
+				return detectedTag, nil
+			}
+			// Commands go to target 1, list the tag again on its own
+			selected, selectErr := q.session.device.SelectTagByUIDContext(ctx, detectedTag.UIDBytes)
+			if selectErr == nil {
+				return selected, nil
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// write writes and verifies the message, retrying transient errors up to
+// maxAttempts, and returns the number of attempts made
+func (q *WriteQueue) write(
+	ctx context.Context,
+	detectedTag *pn532.DetectedTag,
+	message *pn532.NDEFMessage,
+	maxAttempts int,
+) (int, error) {
+	want, err := pn532.BuildNDEFMessageEx(message.Records)
+	if err != nil {
+		return 0, fmt.Errorf("failed to build NDEF message: %w", err)
+	}
+
+	attempt := 0
+	for {
+		attempt++
+		err = q.writeOnce(detectedTag, message, want)
+		if err == nil || attempt >= maxAttempts ||
+			!(pn532.IsRetryable(err) || errors.Is(err, ErrVerifyFailed)) {
+			return attempt, err
+		}
+
+		select {
+		case <-time.After(q.config.RetryDelay):
+		case <-ctx.Done():
+			return attempt, fmt.Errorf("%w (last error: %w)", ctx.Err(), err)
+		}
+	}
+}
+
+// writeOnce writes the message and reads it back
+func (q *WriteQueue) writeOnce(detectedTag *pn532.DetectedTag, message *pn532.NDEFMessage, want []byte) error {
+	tag, err := q.session.device.CreateTag(detectedTag)
+	if err != nil {
+		return fmt.Errorf("failed to create tag: %w", err)
+	}
+	if err = tag.WriteNDEF(message); err != nil {
+		return fmt.Errorf("failed to write NDEF message: %w", err)
+	}
+
+	read, err := tag.ReadNDEF()
+	if err != nil {
+		return fmt.Errorf("failed to verify NDEF message: %w", err)
+	}
+	got, err := pn532.BuildNDEFMessageEx(read.Records)
+	if err != nil || !bytes.Equal(got, want) {
+		return ErrVerifyFailed
+	}
+	return nil
+}
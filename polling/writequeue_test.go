@@ -0,0 +1,309 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package polling
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pn532 "github.com/ZaparooProject/go-pn532"
+	"github.com/ZaparooProject/go-pn532/pn532sim"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	simCmdInDataExchange = 0x40
+	ntagCmdWrite         = 0xA2
+)
+
+// flakyTransport fails the next NTAG page writes with err, a transient
+// error when nil
+type flakyTransport struct {
+	*pn532sim.Simulator
+	err      error
+	failures atomic.Int32
+}
+
+func (f *flakyTransport) SendCommand(cmd byte, args []byte) ([]byte, error) {
+	return f.SendCommandWithContext(context.Background(), cmd, args)
+}
+
+func (f *flakyTransport) SendCommandWithContext(ctx context.Context, cmd byte, args []byte) ([]byte, error) {
+	isWrite := cmd == simCmdInDataExchange && len(args) > 1 && args[1] == ntagCmdWrite
+	if isWrite && f.failures.Add(-1) >= 0 {
+		if f.err != nil {
+			return nil, f.err
+		}
+		return nil, pn532.ErrTransportTimeout
+	}
+	return f.Simulator.SendCommandWithContext(ctx, cmd, args)
+}
+
+func textJob(id, text string) WriteJob {
+	return WriteJob{ID: id, Message: &pn532.NDEFMessage{Records: []pn532.NDEFRecord{
+		{Type: pn532.NDEFTypeText, Text: text},
+	}}}
+}
+
+// nextWriteResult waits for the next write queue result
+func nextWriteResult(t *testing.T, results <-chan WriteResult) WriteResult {
+	t.Helper()
+
+	select {
+	case result, ok := <-results:
+		require.True(t, ok, "results closed")
+		return result
+	case <-time.After(2 * time.Second):
+		require.FailNow(t, "timed out waiting for write result")
+		return WriteResult{}
+	}
+}
+
+// readText reads the text record of a simulated tag
+func readText(t *testing.T, session *Session) string {
+	t.Helper()
+
+	detected, err := session.GetDevice().DetectTag()
+	require.NoError(t, err)
+	tag, err := session.GetDevice().CreateTag(detected)
+	require.NoError(t, err)
+	message, err := tag.ReadNDEF()
+	require.NoError(t, err)
+	require.Len(t, message.Records, 1)
+	return message.Records[0].Text
+}
+
+func TestWriteQueue(t *testing.T) {
+	t.Parallel()
+
+	session, sim := newSimSession(t, &Config{
+		PollInterval:       5 * time.Millisecond,
+		CardRemovalTimeout: 50 * time.Millisecond,
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = session.Start(ctx) }()
+
+	queue := session.NewWriteQueue(nil)
+	require.NoError(t, queue.Enqueue(textJob("first", "one"), textJob("second", "two")))
+	assert.Equal(t, 2, queue.Pending())
+	done := make(chan error, 1)
+	go func() { done <- queue.Run(ctx) }()
+
+	tagA := pn532sim.NewNTAG213(simUIDA)
+	sim.PlaceTag(tagA)
+	first := nextWriteResult(t, queue.Results())
+	require.NoError(t, first.Err)
+	assert.Equal(t, "first", first.Job.ID)
+	assert.Equal(t, "04a1a2a3a4a5a6", first.Tag.UID)
+	assert.Equal(t, 1, first.Attempts)
+
+	// The written tag stays on the reader and is skipped
+	time.Sleep(30 * time.Millisecond)
+	select {
+	case result := <-queue.Results():
+		assert.Failf(t, "unexpected result", "%+v", result)
+	default:
+	}
+	assert.Zero(t, queue.Pending())
+
+	tagB := pn532sim.NewNTAG213(simUIDB)
+	sim.RemoveTag(tagA)
+	sim.PlaceTag(tagB)
+	second := nextWriteResult(t, queue.Results())
+	require.NoError(t, second.Err)
+	assert.Equal(t, "second", second.Job.ID)
+	assert.Equal(t, "04b1b2b3b4b5b6", second.Tag.UID)
+
+	queue.Close()
+	require.NoError(t, <-done)
+	_, ok := <-queue.Results()
+	assert.False(t, ok)
+	require.ErrorIs(t, queue.Enqueue(textJob("late", "three")), ErrWriteQueueClosed)
+
+	cancel()
+	sim.RemoveTag(tagB)
+	sim.PlaceTag(tagA)
+	assert.Equal(t, "one", readText(t, session))
+	sim.RemoveTag(tagA)
+	sim.PlaceTag(tagB)
+	assert.Equal(t, "two", readText(t, session))
+}
+
+func TestWriteQueue_UsedTagBesideNext(t *testing.T) {
+	t.Parallel()
+
+	session, sim := newSimSession(t, &Config{PollInterval: 5 * time.Millisecond})
+	tagA := pn532sim.NewNTAG213(simUIDA)
+	sim.PlaceTag(tagA)
+
+	queue := session.NewWriteQueue(nil)
+	require.NoError(t, queue.Enqueue(textJob("first", "one"), textJob("second", "two")))
+	queue.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- queue.Run(ctx) }()
+
+	first := nextWriteResult(t, queue.Results())
+	require.NoError(t, first.Err)
+	assert.Equal(t, "04a1a2a3a4a5a6", first.Tag.UID)
+
+	// The written tag stays on the reader and wins anticollision, the new
+	// one beside it is still found
+	tagB := pn532sim.NewNTAG213(simUIDB)
+	sim.PlaceTag(tagB)
+	second := nextWriteResult(t, queue.Results())
+	require.NoError(t, second.Err)
+	assert.Equal(t, "04b1b2b3b4b5b6", second.Tag.UID)
+	require.NoError(t, <-done)
+
+	sim.RemoveTag(tagA)
+	assert.Equal(t, "two", readText(t, session))
+	sim.RemoveTag(tagB)
+	sim.PlaceTag(tagA)
+	assert.Equal(t, "one", readText(t, session))
+}
+
+func TestWriteQueue_RetriesTransientErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		maxAttempts  int
+		wantAttempts int
+		failures     int32
+		wantErr      bool
+	}{
+		{name: "Recovers", failures: 1, maxAttempts: 3, wantAttempts: 2},
+		{name: "Gives_Up", failures: 100, maxAttempts: 2, wantAttempts: 2, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sim, err := pn532sim.New(pn532sim.WithTags(pn532sim.NewNTAG213(simUIDA)))
+			require.NoError(t, err)
+			transport := &flakyTransport{Simulator: sim}
+			device, err := pn532.New(transport)
+			require.NoError(t, err)
+			require.NoError(t, device.Init())
+			t.Cleanup(func() { _ = device.Close() })
+			session := NewSession(device, &Config{PollInterval: 5 * time.Millisecond})
+
+			queue := session.NewWriteQueue(&WriteQueueConfig{
+				MaxAttempts: tt.maxAttempts,
+				RetryDelay:  time.Millisecond,
+			})
+			require.NoError(t, queue.Enqueue(textJob("job", "retried")))
+			queue.Close()
+			transport.failures.Store(tt.failures)
+			require.NoError(t, queue.Run(context.Background()))
+
+			result := nextWriteResult(t, queue.Results())
+			assert.Equal(t, tt.wantAttempts, result.Attempts)
+			if tt.wantErr {
+				require.ErrorIs(t, result.Err, pn532.ErrTransportTimeout)
+				return
+			}
+			require.NoError(t, result.Err)
+			assert.Equal(t, "retried", readText(t, session))
+		})
+	}
+}
+
+func TestWriteQueue_FailedTagRetried(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		err        error
+		name       string
+		failures   int32
+		wantReused bool
+	}{
+		// A rejected write leaves attempts for the following job
+		{name: "Attempts_Left", err: errors.New("write rejected"), failures: 1, wantReused: true},
+		// Transient errors used up every attempt on the tag
+		{name: "Attempts_Exhausted", failures: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sim, err := pn532sim.New(pn532sim.WithTags(pn532sim.NewNTAG213(simUIDA)))
+			require.NoError(t, err)
+			transport := &flakyTransport{Simulator: sim, err: tt.err}
+			transport.failures.Store(tt.failures)
+			device, err := pn532.New(transport)
+			require.NoError(t, err)
+			require.NoError(t, device.Init())
+			t.Cleanup(func() { _ = device.Close() })
+			session := NewSession(device, &Config{PollInterval: 5 * time.Millisecond})
+
+			queue := session.NewWriteQueue(&WriteQueueConfig{MaxAttempts: 2, RetryDelay: time.Millisecond})
+			require.NoError(t, queue.Enqueue(textJob("first", "one"), textJob("second", "two")))
+			queue.Close()
+			ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+			defer cancel()
+			go func() { _ = queue.Run(ctx) }()
+
+			first := nextWriteResult(t, queue.Results())
+			require.Error(t, first.Err)
+			require.NotNil(t, first.Tag)
+
+			second := nextWriteResult(t, queue.Results())
+			if !tt.wantReused {
+				require.ErrorIs(t, second.Err, context.DeadlineExceeded)
+				assert.Nil(t, second.Tag)
+				return
+			}
+			require.NoError(t, second.Err)
+			assert.Equal(t, first.Tag.UID, second.Tag.UID)
+			assert.Equal(t, "two", readText(t, session))
+		})
+	}
+}
+
+func TestWriteQueue_Cancel(t *testing.T) {
+	t.Parallel()
+
+	session, _ := newSimSession(t, &Config{PollInterval: 5 * time.Millisecond})
+	queue := session.NewWriteQueue(nil)
+	require.Error(t, queue.Enqueue(WriteJob{ID: "empty"}))
+	require.NoError(t, queue.Enqueue(textJob("job", "never")))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- queue.Run(ctx) }()
+
+	result := nextWriteResult(t, queue.Results())
+	require.ErrorIs(t, result.Err, context.DeadlineExceeded)
+	assert.Nil(t, result.Tag)
+	assert.Zero(t, result.Attempts)
+	require.ErrorIs(t, <-done, context.DeadlineExceeded)
+}
@@ -83,6 +83,22 @@ type Tag interface {
 	Summary() string
 }
 
+// ReadOnlyMaker is implemented by tags whose NDEF data can be write
+// protected: NTAGTag, MIFARETag and FeliCaTag. It is separate from Tag so
+// Tag implementations outside this package don't have to provide it.
+type ReadOnlyMaker interface {
+	// MakeReadOnly permanently write protects the tag's NDEF data. With
+	// dryRun nothing is written and the changes describe what would be.
+	MakeReadOnly(dryRun bool) ([]LockChange, error)
+}
+
+// LockChange is a write MakeReadOnly performs, or would perform in a dry run
+type LockChange struct {
+	Description string // What the write locks
+	Data        []byte // New content of the page or block
+	Address     uint16 // Page or block number
+}
+
 // BaseTag provides common tag functionality
 type BaseTag struct {
 	device  *Device
@@ -22,15 +22,91 @@ package tagops
 
 import (
 	"bytes"
-	"fmt"
+
+	"github.com/ZaparooProject/go-pn532"
 )
 
 const (
 	unknownTagName    = "Unknown"
 	ntagTypeName      = "NTAG"
 	mifareClassicName = "MIFARE Classic"
+	feliCaName        = "FeliCa"
+)
+
+// SAK values of MIFARE Classic variants
+const (
+	sakMIFARE1K   = 0x08
+	sakMIFARE4K   = 0x18
+	sakMIFAREMini = 0x09
+	// Infineon 1K and SmartMX emulating 1K/4K
+	sakMIFARE1KInfineon = 0x88
+	sakSmartMX1K        = 0x28
+	sakSmartMX4K        = 0x38
+)
+
+// MIFARE Classic memory layouts
+const (
+	mifare1KBlocks    = 64
+	mifare1KSectors   = 16
+	mifare4KBlocks    = 256
+	mifare4KSectors   = 40
+	mifareMiniBlocks  = 20
+	mifareMiniSectors = 5
+	mifareBlockBytes  = 16
+	ntagPageBytes     = 4
+	ntagReservedPages = 4
 )
 
+// ntagPages is the total page count of each NTAG variant
+var ntagPages = map[pn532.NTAGType]int{
+	pn532.NTAGType213: 45,
+	pn532.NTAGType215: 135,
+	pn532.NTAGType216: 231,
+}
+
+// ntagNames is the product name of each NTAG variant
+var ntagNames = map[pn532.NTAGType]string{
+	pn532.NTAGType213: "NTAG213",
+	pn532.NTAGType215: "NTAG215",
+	pn532.NTAGType216: "NTAG216",
+}
+
+// ntagCCSizes is the capability container size byte (data area / 8) of each
+// NTAG variant
+var ntagCCSizes = map[pn532.NTAGType]byte{
+	pn532.NTAGType213: 0x12,
+	pn532.NTAGType215: 0x3E,
+	pn532.NTAGType216: 0x6D,
+}
+
+// ntagDynLockPages is the page holding the dynamic lock bytes of each NTAG
+// variant
+var ntagDynLockPages = map[pn532.NTAGType]byte{
+	pn532.NTAGType213: 0x28,
+	pn532.NTAGType215: 0x82,
+	pn532.NTAGType216: 0xE2,
+}
+
+// ntagTypeForPages returns the NTAG variant with the given page count
+func ntagTypeForPages(pages int) pn532.NTAGType {
+	for ntagType, total := range ntagPages {
+		if total == pages {
+			return ntagType
+		}
+	}
+	return pn532.NTAGTypeUnknown
+}
+
+// isMIFAREClassicSAK reports whether a SAK belongs to a MIFARE Classic tag
+func isMIFAREClassicSAK(sak byte) bool {
+	switch sak {
+	case sakMIFARE1K, sakMIFARE4K, sakMIFAREMini, sakMIFARE1KInfineon, sakSmartMX1K, sakSmartMX4K:
+		return true
+	default:
+		return false
+	}
+}
+
 // TagInfo contains detailed information about a detected tag
 type TagInfo struct {
 	// String fields (24 bytes each on 64-bit)
@@ -38,8 +114,10 @@ type TagInfo struct {
 	NTAGType   string
 	MIFAREType string
 
-	// Slice field (24 bytes on 64-bit)
+	// Slice fields (24 bytes each on 64-bit)
 	UID []byte
+	IDm []byte
+	PMm []byte
 
 	// Integer fields (8 bytes each on 64-bit for TagType as int, 4 bytes for int)
 	Type        TagType
@@ -47,6 +125,7 @@ type TagInfo struct {
 	UserMemory  int
 	Sectors     int
 	TotalMemory int
+	SystemCode  uint16
 }
 
 // GetTagInfo returns detailed information about the currently detected tag
@@ -63,36 +142,33 @@ func (t *TagOperations) GetTagInfo() (*TagInfo, error) {
 	switch t.tagType {
 	case TagTypeNTAG:
 		info.TypeName = ntagTypeName
+		info.NTAGType = ntagNames[t.ntagType]
 		info.TotalPages = t.totalPages
-		info.UserMemory = (t.totalPages - 4) * 4 // Exclude first 4 pages
-
-		// Determine specific NTAG type based on total pages
-		switch t.totalPages {
-		case 45:
-			info.NTAGType = "NTAG213"
-		case 135:
-			info.NTAGType = "NTAG215"
-		case 231:
-			info.NTAGType = "NTAG216"
-		default:
-			info.NTAGType = fmt.Sprintf("NTAG (unknown, %d pages)", t.totalPages)
-		}
+		info.TotalMemory = t.totalPages * ntagPageBytes
+		info.UserMemory = (t.totalPages - ntagReservedPages) * ntagPageBytes
 
 	case TagTypeMIFARE:
 		info.TypeName = mifareClassicName
-		// Try to determine if it's 1K or 4K
-		// This is a simplified check - real detection would need SAK analysis
-		if len(t.tag.UIDBytes) == 4 {
+		info.Sectors = t.sectors
+		info.TotalMemory = t.mifareBlocks * mifareBlockBytes
+		info.UserMemory = (t.mifareBlocks - t.sectors) * mifareBlockBytes
+		switch t.mifareBlocks {
+		case mifare4KBlocks:
+			info.MIFAREType = "MIFARE Classic 4K"
+		case mifareMiniBlocks:
+			info.MIFAREType = "MIFARE Mini"
+		default:
 			info.MIFAREType = "MIFARE Classic 1K"
-			info.Sectors = 16
-			info.TotalMemory = 1024
-		} else {
-			// Could be 4K, but need more checks
-			info.MIFAREType = "MIFARE Classic (unknown size)"
-			info.Sectors = 16 // Default to 1K
-			info.TotalMemory = 1024
 		}
 
+	case TagTypeFeliCa:
+		info.TypeName = feliCaName
+		info.IDm = t.feliCaInstance.GetIDm()
+		info.PMm = t.feliCaInstance.GetPMm()
+		info.SystemCode = t.feliCaInstance.GetSystemCode()
+		info.TotalMemory = (t.feliCaBlocks + 1) * feliCaBlockBytes
+		info.UserMemory = t.feliCaBlocks * feliCaBlockBytes
+
 	case TagTypeUnknown:
 		info.TypeName = unknownTagName
 	default:
@@ -111,6 +187,8 @@ func (t TagType) String() string {
 		return ntagTypeName
 	case TagTypeMIFARE:
 		return mifareClassicName
+	case TagTypeFeliCa:
+		return feliCaName
 	default:
 		return unknownTagName
 	}
@@ -141,10 +219,12 @@ func (t *TagOperations) IsNDEFCapable() bool {
 	case TagTypeNTAG:
 		return true // All NTAG variants support NDEF
 	case TagTypeMIFARE:
-		// MIFARE Classic can support NDEF if formatted properly
-		// Try to read block 4 (sector 1) to test NDEF capability
-		_, err := t.mifareInstance.ReadBlockAuto(4)
-		return err == nil
+		// MIFARE Classic supports NDEF if sector 1 opens with the NDEF key,
+		// which DetectTag checked
+		return t.mifareNDEF
+	case TagTypeFeliCa:
+		// Type 3 tags are NDEF capable once block 0 holds a valid AIB
+		return t.feliCaInfo != nil
 	case TagTypeUnknown:
 		return false
 	default:
@@ -36,7 +36,7 @@ func Example_readNDEF() {
 	_, _ = fmt.Println("Detecting tag...")
 	_, _ = fmt.Printf("Detected %s tag with UID: %s\n", "NTAG215", "04:12:34:56:78:9A:BC")
 
-	// Read NDEF - works transparently for both NTAG and MIFARE
+	// Read NDEF - works transparently for NTAG, MIFARE and FeliCa
 	_, _ = fmt.Println("Reading NDEF message...")
 
 	// Process NDEF records
@@ -73,7 +73,7 @@ func Example_writeNDEF() {
 	_, _ = fmt.Println("Text record: Hello from go-pn532!")
 	_, _ = fmt.Println("URI record: https://github.com/ZaparooProject/go-pn532")
 
-	// Write NDEF - works transparently for both NTAG and MIFARE
+	// Write NDEF - works transparently for NTAG, MIFARE and FeliCa
 	// For MIFARE, authentication is handled automatically
 	_, _ = fmt.Println("Writing NDEF message...")
 	_, _ = fmt.Println("Authenticating with MIFARE key...")
@@ -0,0 +1,133 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package tagops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ZaparooProject/go-pn532"
+)
+
+// FeliCa NDEF services and memory layout
+const (
+	feliCaServiceNDEFWrite = 0x0009
+	feliCaBlockBytes       = 16
+	// feliCaMaxProbeBlocks bounds the block count probe of unformatted tags
+	feliCaMaxProbeBlocks = 255
+	// Blocks per read/write assumed when a tag has no AIB to tell
+	feliCaDefaultBlocksPerOp = 1
+)
+
+// feliCaTargets are the InAutoPoll targets DetectTag falls back to when no
+// ISO14443A tag answers
+var feliCaTargets = []pn532.AutoPollTarget{pn532.AutoPollFeliCa212, pn532.AutoPollFeliCa424}
+
+// detectFeliCa polls for a FeliCa tag, InListPassiveTarget at 106 kbps
+// doesn't see them
+func (t *TagOperations) detectFeliCa(ctx context.Context) (*pn532.DetectedTag, error) {
+	tags, err := t.device.DetectTagsAutoPollContext(ctx, 1, 1, 1, feliCaTargets)
+	if err != nil {
+		return nil, fmt.Errorf("FeliCa polling failed: %w", err)
+	}
+	if len(tags) == 0 {
+		return nil, pn532.ErrNoTagDetected
+	}
+	return tags[0], nil
+}
+
+// initFeliCa sets up the FeliCa handler and reads the block count from the
+// attribute information block, tags without one are probed
+func (t *TagOperations) initFeliCa() error {
+	felica, err := pn532.NewFeliCaTag(t.device, t.tag.TargetData)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUnsupportedTag, err)
+	}
+
+	t.tagType = TagTypeFeliCa
+	t.feliCaInstance = felica
+	if info, err := felica.ReadAttributeInfo(); err == nil {
+		t.feliCaInfo = info
+		t.feliCaBlocks = int(info.MaxBlocks)
+		return nil
+	}
+	t.feliCaBlocks = t.probeFeliCaBlocks()
+	return nil
+}
+
+// probeFeliCaBlocks counts the NDEF data blocks after block 0 by reading
+// until the tag rejects a block number
+func (t *TagOperations) probeFeliCaBlocks() int {
+	blocks := 0
+	for block := uint16(1); block <= feliCaMaxProbeBlocks; block++ {
+		if _, err := t.feliCaInstance.ReadBlockExtended(block); err != nil {
+			break
+		}
+		blocks++
+	}
+	return blocks
+}
+
+// readFeliCaBlocks reads blocks through the NDEF read service. Block 0 is
+// the attribute information block.
+func (t *TagOperations) readFeliCaBlocks(_ context.Context, startBlock, endBlock byte) ([]byte, error) {
+	last := min(int(endBlock), t.feliCaBlocks)
+	result := make([]byte, 0, max(last-int(startBlock)+1, 0)*feliCaBlockBytes)
+	for block := int(startBlock); block <= last; block++ {
+		data, err := t.feliCaInstance.ReadBlockExtended(uint16(block))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read block %d: %w", block, err)
+		}
+		result = append(result, data...)
+	}
+	return result, nil
+}
+
+// writeFeliCaBlocks writes blocks through the NDEF read/write service
+func (t *TagOperations) writeFeliCaBlocks(_ context.Context, startBlock byte, data []byte) error {
+	// Validate we're not writing to the attribute information block
+	if startBlock == 0 {
+		return errors.New("cannot write to attribute information block (0)")
+	}
+
+	numBlocks := (len(data) + feliCaBlockBytes - 1) / feliCaBlockBytes
+	if int(startBlock)+numBlocks-1 > t.feliCaBlocks {
+		return errors.New("write would exceed tag capacity")
+	}
+
+	originalService := t.feliCaInstance.GetServiceCode()
+	t.feliCaInstance.SetServiceCode(feliCaServiceNDEFWrite)
+	defer t.feliCaInstance.SetServiceCode(originalService)
+
+	for i := range numBlocks {
+		block := uint16(startBlock) + uint16(i)
+
+		// Get 16 bytes for this block (pad with zeros if necessary)
+		blockData := make([]byte, feliCaBlockBytes)
+		copy(blockData, data[i*feliCaBlockBytes:min((i+1)*feliCaBlockBytes, len(data))])
+
+		if err := t.feliCaInstance.WriteBlockExtended(block, blockData); err != nil {
+			return fmt.Errorf("failed to write block %d: %w", block, err)
+		}
+	}
+	return nil
+}
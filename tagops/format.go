@@ -0,0 +1,333 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package tagops
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ZaparooProject/go-pn532"
+)
+
+// FormatStep is a step Format ran on the tag
+type FormatStep string
+
+const (
+	// FormatStepCapabilityContainer wrote the NTAG capability container (page 3)
+	FormatStepCapabilityContainer FormatStep = "capability container"
+	// FormatStepMAD wrote the MIFARE Application Directory
+	FormatStepMAD FormatStep = "MIFARE application directory"
+	// FormatStepSectorTrailers gave the MIFARE Classic sectors the NDEF key
+	FormatStepSectorTrailers FormatStep = "NDEF sector trailers"
+	// FormatStepAttributeInfo wrote a fresh FeliCa attribute information block
+	FormatStepAttributeInfo FormatStep = "attribute information block"
+	// FormatStepEmptyNDEF wrote an empty NDEF message
+	FormatStepEmptyNDEF FormatStep = "empty NDEF message"
+)
+
+// FormatResult reports the steps Format ran, in order
+type FormatResult struct {
+	Steps []FormatStep
+}
+
+// Ran reports whether Format ran the step
+func (r *FormatResult) Ran(step FormatStep) bool {
+	for _, s := range r.Steps {
+		if s == step {
+			return true
+		}
+	}
+	return false
+}
+
+// NDEF mapping constants
+const (
+	ndefMagic   = 0xE1
+	ndefVersion = 0x10
+	// ndefNoWriteAccess is the CC write access value of a read-only NTAG
+	ndefNoWriteAccess = 0x0F
+	// madNDEFAID is the MAD application ID of NDEF sectors, stored little endian
+	madNDEFAID = 0xE103
+	// madCRCPreset and madCRCPoly are the MAD CRC-8 parameters from AN10787
+	madCRCPreset = 0xC7
+	madCRCPoly   = 0x1D
+	// madInfoByte points at sector 1 as the card publisher sector
+	madInfoByte = 0x01
+	// madSectors is the number of sectors covered by MAD1
+	madSectors = 16
+	// mad2Sector holds the MAD2 of a MIFARE Classic 4K
+	mad2Sector = 16
+	// General purpose bytes: MAD1/MAD2 available, NDEF mapping 1.0
+	gpbMAD1        = 0xC1
+	gpbMAD2        = 0xC2
+	gpbNDEF        = 0x40
+	gpbWriteAccess = 0x03
+	// gpbReadOnly is the write access value of a read-only NDEF sector
+	gpbReadOnly = 0x03
+)
+
+// emptyNDEFTLV is an NDEF TLV holding an empty message followed by a terminator
+var emptyNDEFTLV = []byte{0x03, 0x00, 0xFE}
+
+// MIFARE Classic keys and access bits used for NDEF formatting
+var (
+	mifareTransportKey = []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}
+	mifareMADKey       = []byte{0xA0, 0xA1, 0xA2, 0xA3, 0xA4, 0xA5}
+	mifareNDEFKey      = []byte{0xD3, 0xF7, 0xD3, 0xF7, 0xD3, 0xF7}
+	// MAD sectors: data written with key B only, NDEF sectors: data read and
+	// written with either key, trailers managed with key B
+	madAccessBits  = []byte{0x78, 0x77, 0x88}
+	ndefAccessBits = []byte{0x7F, 0x07, 0x88}
+)
+
+// Format prepares the tag for NDEF use and leaves an empty NDEF message.
+// Blank NTAGs get a capability container sized for the variant, blank
+// MIFARE Classic tags get a MAD and NDEF sector trailers, FeliCa tags a
+// fresh attribute information block. Locked tags are refused with
+// ErrTagLocked. The result lists the steps that ran, also on error.
+func (t *TagOperations) Format(ctx context.Context) (*FormatResult, error) {
+	if t.tag == nil {
+		return nil, ErrNoTag
+	}
+
+	result := &FormatResult{}
+	var err error
+	switch t.tagType {
+	case TagTypeNTAG:
+		err = t.formatNTAG(ctx, result)
+	case TagTypeMIFARE:
+		err = t.formatMIFARE(ctx, result)
+	case TagTypeFeliCa:
+		err = t.formatFeliCa(result)
+	case TagTypeUnknown:
+		err = ErrUnsupportedTag
+	default:
+		err = ErrUnsupportedTag
+	}
+	return result, err
+}
+
+// formatNTAG writes the capability container of a blank NTAG and an empty
+// NDEF message. The CC is one-time programmable, an existing NDEF CC is kept.
+func (t *TagOperations) formatNTAG(ctx context.Context, result *FormatResult) error {
+	staticLock, err := t.ntagInstance.ReadBlock(2)
+	if err != nil {
+		return fmt.Errorf("failed to read static lock bytes: %w", err)
+	}
+	cc, err := t.ntagInstance.ReadBlock(3)
+	if err != nil {
+		return fmt.Errorf("failed to read capability container: %w", err)
+	}
+	dynLock, err := t.ntagInstance.ReadBlock(ntagDynLockPages[t.ntagType])
+	if err != nil {
+		return fmt.Errorf("failed to read dynamic lock bytes: %w", err)
+	}
+	if ntagLocked(staticLock[2], staticLock[3], dynLock, cc) {
+		return ErrTagLocked
+	}
+
+	want := []byte{ndefMagic, ndefVersion, ntagCCSizes[t.ntagType], 0x00}
+	switch {
+	case bytes.Equal(cc, want), cc[0] == ndefMagic:
+		// Already NDEF formatted
+	case bytes.Equal(cc, make([]byte, ntagPageBytes)):
+		if err := t.ntagInstance.WriteBlock(3, want); err != nil {
+			return fmt.Errorf("failed to write capability container: %w", err)
+		}
+		result.Steps = append(result.Steps, FormatStepCapabilityContainer)
+		// The NTAG handler reads its variant from the CC
+		_ = t.ntagInstance.DetectType()
+	default:
+		return fmt.Errorf("capability container % X is not NDEF and can't be rewritten", cc)
+	}
+
+	if err := t.writeNTAGBlocks(ctx, ntagReservedPages, emptyNDEFTLV); err != nil {
+		return fmt.Errorf("failed to write empty NDEF message: %w", err)
+	}
+	result.Steps = append(result.Steps, FormatStepEmptyNDEF)
+	return nil
+}
+
+// ntagLocked reports whether lock bits protect the CC or the data area, or
+// the CC denies write access
+func ntagLocked(lock0, lock1 byte, dynLock, cc []byte) bool {
+	// Bits 0-2 of lock byte 0 freeze the lock bits themselves
+	return lock0&0xF8 != 0 || lock1 != 0 || dynLock[0] != 0 || dynLock[1] != 0 ||
+		(cc[0] == ndefMagic && cc[3] == ndefNoWriteAccess)
+}
+
+// formatMIFARE writes the MAD and NDEF sector trailers of a blank MIFARE
+// Classic tag and an empty NDEF message. Tags already opening with the NDEF
+// key keep their trailers.
+func (t *TagOperations) formatMIFARE(ctx context.Context, result *FormatResult) error {
+	if t.mifareNDEF {
+		trailer, err := t.mifareInstance.ReadBlockAuto(byte(pn532.MIFARETrailerBlock(1)))
+		if err != nil {
+			return fmt.Errorf("failed to read sector 1 trailer: %w", err)
+		}
+		if trailer[9]&gpbWriteAccess == gpbReadOnly {
+			return ErrTagLocked
+		}
+	} else {
+		if err := t.writeMIFAREMAD(); err != nil {
+			return err
+		}
+		result.Steps = append(result.Steps, FormatStepMAD)
+
+		if err := t.writeMIFARENDEFTrailers(); err != nil {
+			return err
+		}
+		result.Steps = append(result.Steps, FormatStepSectorTrailers)
+		t.mifareNDEF = true
+	}
+
+	if err := t.writeMIFAREBlocks(ctx, 4, emptyNDEFTLV); err != nil {
+		return fmt.Errorf("failed to write empty NDEF message: %w", err)
+	}
+	result.Steps = append(result.Steps, FormatStepEmptyNDEF)
+	return nil
+}
+
+// writeMIFAREMAD writes MAD1 to sector 0 and on a 4K MAD2 to sector 16,
+// marking every other sector as NDEF
+func (t *TagOperations) writeMIFAREMAD() error {
+	gpb := byte(gpbMAD1)
+	if t.sectors > madSectors {
+		gpb = gpbMAD2
+	}
+	trailer := mifareTrailer(mifareMADKey, madAccessBits, gpb, mifareTransportKey)
+
+	// Block 0 holds the manufacturer data, MAD1 starts at block 1
+	mad1 := buildMAD(madSectors-1, min(t.sectors, madSectors)-1)
+	if err := t.writeBlankSector(0, 1, mad1, trailer); err != nil {
+		return fmt.Errorf("failed to write MAD: %w", err)
+	}
+	if t.sectors <= madSectors {
+		return nil
+	}
+
+	mad2 := buildMAD(t.sectors-madSectors-1, t.sectors-madSectors-1)
+	if err := t.writeBlankSector(mad2Sector, mad2Sector*4, mad2, trailer); err != nil {
+		return fmt.Errorf("failed to write MAD2: %w", err)
+	}
+	return nil
+}
+
+// writeMIFARENDEFTrailers gives every non-MAD sector the NDEF key
+func (t *TagOperations) writeMIFARENDEFTrailers() error {
+	trailer := mifareTrailer(mifareNDEFKey, ndefAccessBits, gpbNDEF, mifareNDEFKey)
+	for sector := 1; sector < t.sectors; sector++ {
+		if sector == mad2Sector {
+			continue
+		}
+		if err := t.writeBlankSector(sector, 0, nil, trailer); err != nil {
+			return fmt.Errorf("failed to write NDEF trailer: %w", err)
+		}
+	}
+	return nil
+}
+
+// writeBlankSector authenticates a sector with the transport key and writes
+// data from firstBlock followed by the trailer
+func (t *TagOperations) writeBlankSector(sector, firstBlock int, data, trailer []byte) error {
+	trailerBlock := pn532.MIFARETrailerBlock(sector)
+	// The MIFARE handler addresses sectors as block / 4
+	if err := t.mifareInstance.AuthenticateRobust(
+		byte(trailerBlock/4), pn532.MIFAREKeyA, mifareTransportKey); err != nil {
+		return fmt.Errorf("%w: sector %d: %w", ErrAuthFailed, sector, err)
+	}
+
+	for offset := 0; offset < len(data); offset += mifareBlockBytes {
+		block := firstBlock + offset/mifareBlockBytes
+		if err := t.mifareInstance.WriteBlock(byte(block), data[offset:offset+mifareBlockBytes]); err != nil {
+			return fmt.Errorf("failed to write block %d: %w", block, err)
+		}
+	}
+	if err := t.mifareInstance.WriteBlock(byte(trailerBlock), trailer); err != nil {
+		return fmt.Errorf("failed to write sector %d trailer: %w", sector, err)
+	}
+	return nil
+}
+
+// mifareTrailer builds a sector trailer
+func mifareTrailer(keyA, accessBits []byte, gpb byte, keyB []byte) []byte {
+	trailer := make([]byte, 0, mifareBlockBytes)
+	trailer = append(trailer, keyA...)
+	trailer = append(trailer, accessBits...)
+	trailer = append(trailer, gpb)
+	return append(trailer, keyB...)
+}
+
+// buildMAD builds a MAD sector: CRC, info byte and one AID per sector,
+// the first ndefSectors of them NDEF
+func buildMAD(sectors, ndefSectors int) []byte {
+	mad := make([]byte, 2+2*sectors)
+	mad[1] = madInfoByte
+	for i := range ndefSectors {
+		mad[2+2*i] = byte(madNDEFAID & 0xFF)
+		mad[3+2*i] = byte(madNDEFAID >> 8)
+	}
+	mad[0] = madCRC(mad[1:])
+	return mad
+}
+
+// madCRC is the CRC-8 of a MAD sector (x^8 + x^4 + x^3 + x^2 + 1)
+func madCRC(data []byte) byte {
+	crc := byte(madCRCPreset)
+	for _, b := range data {
+		crc ^= b
+		for range 8 {
+			if crc&0x80 != 0 {
+				crc = crc<<1 ^ madCRCPoly
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// formatFeliCa writes a fresh attribute information block for an empty NDEF
+// message. The block counts of an existing AIB are kept.
+func (t *TagOperations) formatFeliCa(result *FormatResult) error {
+	if t.feliCaInfo != nil && t.feliCaInfo.ReadOnly {
+		return ErrTagLocked
+	}
+
+	info := pn532.NewFeliCaAttributeInfo(
+		uint16(t.feliCaBlocks), feliCaDefaultBlocksPerOp, feliCaDefaultBlocksPerOp)
+	if t.feliCaInfo != nil {
+		info.BlocksPerRead = t.feliCaInfo.BlocksPerRead
+		info.BlocksPerWrite = t.feliCaInfo.BlocksPerWrite
+	}
+	if info.MaxBlocks == 0 {
+		return errors.New("FeliCa tag has no NDEF data blocks")
+	}
+
+	if err := t.feliCaInstance.WriteAttributeInfo(info); err != nil {
+		return fmt.Errorf("failed to write attribute information block: %w", err)
+	}
+	t.feliCaInfo = info
+	result.Steps = append(result.Steps, FormatStepAttributeInfo)
+	return nil
+}
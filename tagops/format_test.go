@@ -0,0 +1,261 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package tagops
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ZaparooProject/go-pn532"
+	"github.com/ZaparooProject/go-pn532/pn532sim"
+	"github.com/hsanjuan/go-ndef"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blankNTAG clears the capability container and the empty NDEF TLV
+func blankNTAG(t *testing.T, tag *pn532sim.NTAG) *pn532sim.NTAG {
+	t.Helper()
+	require.NoError(t, tag.SetPage(3, make([]byte, 4)))
+	require.NoError(t, tag.SetPage(4, make([]byte, 4)))
+	return tag
+}
+
+// requireNDEFRoundTrip writes a text record through ops and reads it back
+func requireNDEFRoundTrip(t *testing.T, ops *TagOperations) {
+	t.Helper()
+
+	require.NoError(t, ops.WriteNDEF(context.Background(), ndef.NewTextMessage("formatted", "en")))
+	requireText(t, ops, "formatted")
+}
+
+// requireText reads the NDEF message through ops and checks its text record
+func requireText(t *testing.T, ops *TagOperations, text string) {
+	t.Helper()
+
+	msg, err := ops.ReadNDEF(context.Background())
+	require.NoError(t, err)
+	require.Len(t, msg.Records, 1)
+	payload, err := msg.Records[0].Payload()
+	require.NoError(t, err)
+	assert.Contains(t, string(payload.Marshal()), text)
+}
+
+func TestFormat_NTAG(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		tag    *pn532sim.NTAG
+		name   string
+		want   []FormatStep
+		ccSize byte
+	}{
+		{
+			name: "Blank_NTAG213", tag: blankNTAG(t, pn532sim.NewNTAG213(testUID7)), ccSize: 0x12,
+			want: []FormatStep{FormatStepCapabilityContainer, FormatStepEmptyNDEF},
+		},
+		{
+			name: "Blank_NTAG215", tag: blankNTAG(t, pn532sim.NewNTAG215(testUID7)), ccSize: 0x3E,
+			want: []FormatStep{FormatStepCapabilityContainer, FormatStepEmptyNDEF},
+		},
+		{
+			name: "Blank_NTAG216", tag: blankNTAG(t, pn532sim.NewNTAG216(testUID7)), ccSize: 0x6D,
+			want: []FormatStep{FormatStepCapabilityContainer, FormatStepEmptyNDEF},
+		},
+		{
+			name: "Formatted_NTAG213", tag: pn532sim.NewNTAG213(testUID7), ccSize: 0x12,
+			want: []FormatStep{FormatStepEmptyNDEF},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ops, _ := newSimOps(t, tt.tag)
+			ctx := context.Background()
+			require.NoError(t, ops.DetectTag(ctx))
+			require.NoError(t, ops.WriteBlocks(ctx, 4, []byte{0xAA, 0xBB, 0xCC, 0xDD}))
+
+			result, err := ops.Format(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, result.Steps)
+			assert.Equal(t, tt.want[0] == FormatStepCapabilityContainer, result.Ran(FormatStepCapabilityContainer))
+
+			memory := tt.tag.Memory()
+			assert.Equal(t, []byte{0xE1, 0x10, tt.ccSize, 0x00}, memory[12:16])
+			assert.Equal(t, []byte{0x03, 0x00, 0xFE, 0x00}, memory[16:20])
+			requireNDEFRoundTrip(t, ops)
+		})
+	}
+}
+
+func TestFormat_NTAGRefused(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		wantErr error
+		name    string
+		data    []byte
+		page    int
+	}{
+		{name: "Static_Lock", page: 2, data: []byte{0x00, 0x00, 0x00, 0x01}, wantErr: ErrTagLocked},
+		{name: "CC_Lock", page: 2, data: []byte{0x00, 0x00, 0x08, 0x00}, wantErr: ErrTagLocked},
+		{name: "Dynamic_Lock", page: 0x28, data: []byte{0x01, 0x00, 0x00, 0xBD}, wantErr: ErrTagLocked},
+		{name: "Read_Only_CC", page: 3, data: []byte{0xE1, 0x10, 0x12, 0x0F}, wantErr: ErrTagLocked},
+		{name: "Foreign_CC", page: 3, data: []byte{0x01, 0x02, 0x03, 0x04}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tag := pn532sim.NewNTAG213(testUID7)
+			require.NoError(t, tag.SetPage(tt.page, tt.data))
+			ops, _ := newSimOps(t, tag)
+			ctx := context.Background()
+			require.NoError(t, ops.DetectTag(ctx))
+			before := tag.Memory()
+
+			result, err := ops.Format(ctx)
+			require.Error(t, err)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.Empty(t, result.Steps)
+			assert.Equal(t, before, tag.Memory(), "nothing written")
+		})
+	}
+}
+
+func TestFormat_BlankClassic(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		tag     *pn532sim.Classic
+		name    string
+		blocks  int
+		sectors int
+		gpb     byte
+	}{
+		{name: "1K", tag: pn532sim.NewClassic1K(testUID4), blocks: mifare1KBlocks, sectors: 16, gpb: 0xC1},
+		{name: "4K", tag: pn532sim.NewClassic4K(testUID4), blocks: mifare4KBlocks, sectors: 40, gpb: 0xC2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ops, _ := newSimOps(t, tt.tag)
+			ctx := context.Background()
+			require.NoError(t, ops.DetectTag(ctx))
+			require.False(t, ops.IsNDEFCapable())
+
+			result, err := ops.Format(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []FormatStep{FormatStepMAD, FormatStepSectorTrailers, FormatStepEmptyNDEF}, result.Steps)
+			assert.True(t, ops.IsNDEFCapable())
+
+			mad := append(tt.tag.Block(1), tt.tag.Block(2)...)
+			assert.Equal(t, buildMAD(15, 15), mad)
+			assert.Equal(t, mifareTrailer(mifareMADKey, madAccessBits, tt.gpb, mifareTransportKey), tt.tag.Block(3))
+			ndefTrailer := mifareTrailer(mifareNDEFKey, ndefAccessBits, gpbNDEF, mifareNDEFKey)
+			for sector := 1; sector < tt.sectors; sector++ {
+				if sector == mad2Sector {
+					mad2 := append(append(tt.tag.Block(64), tt.tag.Block(65)...), tt.tag.Block(66)...)
+					assert.Equal(t, buildMAD(23, 23), mad2)
+					continue
+				}
+				assert.Equal(t, ndefTrailer, tt.tag.Block(pn532.MIFARETrailerBlock(sector)), "sector %d", sector)
+			}
+			assert.Equal(t, tt.blocks-1, pn532.MIFARETrailerBlock(tt.sectors-1))
+			assert.Equal(t, []byte{0x03, 0x00, 0xFE}, tt.tag.Block(4)[:3])
+
+			requireNDEFRoundTrip(t, ops)
+
+			// Formatting again keeps the trailers
+			result, err = ops.Format(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []FormatStep{FormatStepEmptyNDEF}, result.Steps)
+		})
+	}
+}
+
+func TestFormat_ClassicRefused(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Read_Only_NDEF", func(t *testing.T) {
+		t.Parallel()
+
+		tag := pn532sim.NewClassic1K(testUID4)
+		formatClassicNDEF(t, tag, mifare1KBlocks)
+		require.NoError(t, tag.SetBlock(7, mifareTrailer(ndefKey, ndefAccessBits, 0x43, ndefKey)))
+		ops, _ := newSimOps(t, tag)
+		require.NoError(t, ops.DetectTag(context.Background()))
+
+		_, err := ops.Format(context.Background())
+		require.ErrorIs(t, err, ErrTagLocked)
+	})
+
+	t.Run("Unknown_Keys", func(t *testing.T) {
+		t.Parallel()
+
+		tag := pn532sim.NewClassic1K(testUID4)
+		require.NoError(t, tag.SetSectorKeys(0, []byte{1, 2, 3, 4, 5, 6}, []byte{1, 2, 3, 4, 5, 6}))
+		ops, _ := newSimOps(t, tag)
+		require.NoError(t, ops.DetectTag(context.Background()))
+
+		result, err := ops.Format(context.Background())
+		require.ErrorIs(t, err, ErrAuthFailed)
+		assert.Empty(t, result.Steps)
+		assert.Equal(t, make([]byte, 16), tag.Block(1), "MAD not written")
+	})
+}
+
+func TestFormat_FeliCaReadOnly(t *testing.T) {
+	t.Parallel()
+
+	tag := pn532sim.NewFeliCa(testIDm)
+	aib := tag.Block(0)
+	aib[10] = 0x00
+	aib[15]--
+	require.NoError(t, tag.SetBlock(0, aib))
+	ops, _ := newSimOps(t, tag)
+	require.NoError(t, ops.DetectTag(context.Background()))
+
+	_, err := ops.Format(context.Background())
+	require.ErrorIs(t, err, ErrTagLocked)
+}
+
+func TestBuildMAD(t *testing.T) {
+	t.Parallel()
+
+	// Fully NDEF MAD1 from the NFC Forum MIFARE Classic mapping
+	mad := buildMAD(15, 15)
+	require.Len(t, mad, 32)
+	assert.Equal(t, []byte{0x14, 0x01, 0x03, 0xE1, 0x03, 0xE1}, mad[:6])
+
+	// MIFARE Mini: sectors 5-15 are free
+	mad = buildMAD(15, 4)
+	assert.Equal(t, []byte{0x03, 0xE1}, mad[8:10])
+	assert.Equal(t, []byte{0x00, 0x00}, mad[10:12])
+	assert.Equal(t, madCRC(mad[1:]), mad[0])
+}
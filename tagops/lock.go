@@ -0,0 +1,56 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package tagops
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ZaparooProject/go-pn532"
+)
+
+// MakeReadOnly permanently write protects the tag's NDEF data. NTAGs get
+// their CC write access byte and lock bits set, MIFARE Classic NDEF sectors
+// read-only trailers and FeliCa tags a read-only AIB. Locking NTAG and
+// MIFARE Classic tags can't be undone.
+//
+// With dryRun nothing is written and the returned changes describe what
+// would be locked. Changes already on the tag are left out, so an empty
+// result means the tag is read-only.
+func (t *TagOperations) MakeReadOnly(_ context.Context, dryRun bool) ([]pn532.LockChange, error) {
+	if t.tag == nil {
+		return nil, ErrNoTag
+	}
+
+	tag, ok := t.Tag().(pn532.ReadOnlyMaker)
+	if !ok {
+		return nil, ErrUnsupportedTag
+	}
+
+	changes, err := tag.MakeReadOnly(dryRun)
+	if err != nil {
+		return changes, fmt.Errorf("failed to make %s tag read-only: %w", t.tagType, err)
+	}
+	if !dryRun && t.feliCaInfo != nil {
+		t.feliCaInfo.ReadOnly = true
+	}
+	return changes, nil
+}
@@ -0,0 +1,184 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package tagops
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ZaparooProject/go-pn532"
+	"github.com/ZaparooProject/go-pn532/pn532sim"
+	"github.com/hsanjuan/go-ndef"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMakeReadOnly_NTAG(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		tag         *pn532sim.NTAG
+		name        string
+		dynLock     []byte
+		dynLockPage int
+	}{
+		{name: "NTAG213", tag: pn532sim.NewNTAG213(testUID7), dynLockPage: 0x28, dynLock: []byte{0xFF, 0x0F, 0x00}},
+		{name: "NTAG215", tag: pn532sim.NewNTAG215(testUID7), dynLockPage: 0x82, dynLock: []byte{0xFF, 0x00, 0x00}},
+		{name: "NTAG216", tag: pn532sim.NewNTAG216(testUID7), dynLockPage: 0xE2, dynLock: []byte{0xFF, 0x3F, 0x00}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ops, _ := newSimOps(t, tt.tag)
+			ctx := context.Background()
+			require.NoError(t, ops.DetectTag(ctx))
+			before := tt.tag.Memory()
+
+			planned, err := ops.MakeReadOnly(ctx, true)
+			require.NoError(t, err)
+			require.Len(t, planned, 3)
+			assert.Equal(t, []uint16{3, uint16(tt.dynLockPage), 2},
+				[]uint16{planned[0].Address, planned[1].Address, planned[2].Address}, "CC before its lock bit")
+			assert.Equal(t, before, tt.tag.Memory(), "dry run writes nothing")
+
+			changes, err := ops.MakeReadOnly(ctx, false)
+			require.NoError(t, err)
+			assert.Equal(t, planned, changes)
+
+			memory := tt.tag.Memory()
+			assert.Equal(t, byte(0x0F), memory[15], "CC write access")
+			assert.Equal(t, []byte{0xFF, 0xFF}, memory[10:12], "static lock bytes")
+			assert.Equal(t, tt.dynLock, memory[tt.dynLockPage*4:tt.dynLockPage*4+3])
+
+			require.Error(t, ops.WriteBlocks(ctx, 4, []byte{1, 2, 3, 4}))
+			// A NAK halts the tag
+			require.NoError(t, ops.DetectTag(ctx))
+			require.Error(t, ops.WriteBlocks(ctx, byte(tt.dynLockPage-1), []byte{1, 2, 3, 4}))
+			require.NoError(t, ops.DetectTag(ctx))
+			_, err = ops.Format(ctx)
+			require.ErrorIs(t, err, ErrTagLocked)
+
+			changes, err = ops.MakeReadOnly(ctx, false)
+			require.NoError(t, err)
+			assert.Empty(t, changes, "already read-only")
+		})
+	}
+}
+
+func TestMakeReadOnly_Classic(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		tag     *pn532sim.Classic
+		name    string
+		sectors int
+	}{
+		{name: "1K", tag: pn532sim.NewClassic1K(testUID4), sectors: 16},
+		{name: "4K", tag: pn532sim.NewClassic4K(testUID4), sectors: 40},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ops, _ := newSimOps(t, tt.tag)
+			ctx := context.Background()
+			require.NoError(t, ops.DetectTag(ctx))
+			_, err := ops.MakeReadOnly(ctx, true)
+			require.Error(t, err, "blank tag has no NDEF sectors")
+
+			_, err = ops.Format(ctx)
+			require.NoError(t, err)
+			require.NoError(t, ops.WriteNDEF(ctx, ndef.NewTextMessage("locked", "en")))
+			lastTrailer := tt.tag.Block(pn532.MIFARETrailerBlock(tt.sectors - 1))
+
+			// Every sector except sector 0 and on a 4K sector 16 (MAD2)
+			ndefSectors := tt.sectors - 1
+			if tt.sectors > madSectors {
+				ndefSectors--
+			}
+			planned, err := ops.MakeReadOnly(ctx, true)
+			require.NoError(t, err)
+			require.Len(t, planned, ndefSectors)
+			assert.Equal(t, lastTrailer, tt.tag.Block(pn532.MIFARETrailerBlock(tt.sectors-1)), "dry run writes nothing")
+
+			changes, err := ops.MakeReadOnly(ctx, false)
+			require.NoError(t, err)
+			assert.Equal(t, planned, changes)
+			for sector := 1; sector < tt.sectors; sector++ {
+				if sector == mad2Sector {
+					continue
+				}
+				assert.Equal(t, []byte{0x07, 0x8F, 0x0F, 0x43}, tt.tag.Block(pn532.MIFARETrailerBlock(sector))[6:10],
+					"sector %d", sector)
+			}
+
+			require.Error(t, ops.WriteBlocks(ctx, 4, make([]byte, 16)))
+			// A refused write halts the tag
+			require.NoError(t, ops.DetectTag(ctx))
+			_, err = ops.Format(ctx)
+			require.ErrorIs(t, err, ErrTagLocked)
+			requireText(t, ops, "locked")
+
+			changes, err = ops.MakeReadOnly(ctx, false)
+			require.NoError(t, err)
+			assert.Empty(t, changes, "already read-only")
+		})
+	}
+}
+
+func TestMakeReadOnly_FeliCa(t *testing.T) {
+	t.Parallel()
+
+	tag := pn532sim.NewFeliCa(testIDm)
+	ops, _ := newSimOps(t, tag)
+	ctx := context.Background()
+	require.NoError(t, ops.DetectTag(ctx))
+	require.NoError(t, ops.WriteNDEF(ctx, ndef.NewTextMessage("locked", "en")))
+	aib := tag.Block(0)
+
+	planned, err := ops.MakeReadOnly(ctx, true)
+	require.NoError(t, err)
+	require.Len(t, planned, 1)
+	assert.Equal(t, uint16(0), planned[0].Address)
+	assert.Equal(t, aib, tag.Block(0), "dry run writes nothing")
+
+	changes, err := ops.MakeReadOnly(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, planned, changes)
+	assert.Equal(t, changes[0].Data, tag.Block(0))
+	assert.Equal(t, byte(0x00), tag.Block(0)[10], "RWFlag")
+
+	require.Error(t, ops.WriteNDEF(ctx, ndef.NewTextMessage("rejected", "en")))
+	_, err = ops.Format(ctx)
+	require.ErrorIs(t, err, ErrTagLocked)
+	requireText(t, ops, "locked")
+}
+
+func TestMakeReadOnly_NoTag(t *testing.T) {
+	t.Parallel()
+
+	ops, _ := newSimOps(t)
+	_, err := ops.MakeReadOnly(context.Background(), true)
+	require.ErrorIs(t, err, ErrNoTag)
+}
@@ -42,6 +42,8 @@ func (t *TagOperations) ReadBlocks(ctx context.Context, startBlock, endBlock byt
 		return t.readNTAGBlocks(ctx, startBlock, endBlock)
 	case TagTypeMIFARE:
 		return t.readMIFAREBlocks(ctx, startBlock, endBlock)
+	case TagTypeFeliCa:
+		return t.readFeliCaBlocks(ctx, startBlock, endBlock)
 	case TagTypeUnknown:
 		return nil, ErrUnsupportedTag
 	default:
@@ -60,12 +62,10 @@ func (t *TagOperations) ReadAll(ctx context.Context) ([]byte, error) {
 		// Read from page 0 to last page
 		return t.readNTAGBlocks(ctx, 0, byte(t.totalPages-1))
 	case TagTypeMIFARE:
-		if t.mifareInstance.IsMIFARE4K() {
-			// MIFARE Classic 4K has 255 blocks (0-254)
-			return t.readMIFAREBlocks(ctx, 0, 254)
-		}
-		// MIFARE Classic 1K has 64 blocks (0-63)
-		return t.readMIFAREBlocks(ctx, 0, 63)
+		return t.readMIFAREBlocks(ctx, 0, byte(t.mifareBlocks-1))
+	case TagTypeFeliCa:
+		// Block 0 is the AIB, the NDEF data blocks follow
+		return t.readFeliCaBlocks(ctx, 0, byte(min(t.feliCaBlocks, 0xFF)))
 	case TagTypeUnknown:
 		return nil, ErrUnsupportedTag
 	default:
@@ -94,6 +94,13 @@ func (t *TagOperations) ReadNDEF(_ context.Context) (*ndef.Message, error) {
 		}
 		// Convert from pn532.NDEFMessage to ndef.Message
 		return convertNDEFMessage(ndefMsg), nil
+	case TagTypeFeliCa:
+		ndefMsg, err := t.feliCaInstance.ReadNDEF()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read NDEF from FeliCa: %w", err)
+		}
+		// Convert from pn532.NDEFMessage to ndef.Message
+		return convertNDEFMessage(ndefMsg), nil
 	case TagTypeUnknown:
 		return nil, ErrUnsupportedTag
 	default:
@@ -149,8 +156,7 @@ func (t *TagOperations) tryFastRead(currentPage, chunkEnd byte) (data []byte, ne
 		return nil, 0
 	}
 
-	cmd := []byte{0x3A, currentPage, chunkEnd}
-	if data, err := t.device.SendRawCommand(cmd); err == nil {
+	if data, err := t.ntagInstance.FastRead(currentPage, chunkEnd); err == nil {
 		return data, chunkEnd + 1
 	}
 
@@ -159,16 +165,11 @@ func (t *TagOperations) tryFastRead(currentPage, chunkEnd byte) (data []byte, ne
 
 func (t *TagOperations) readPagesIndividually(currentPage, chunkEnd byte) (result []byte, nextPage byte, err error) {
 	for page := currentPage; page <= chunkEnd; page++ {
-		pageData, err := t.device.SendDataExchange([]byte{0x30, page})
+		pageData, err := t.ntagInstance.ReadBlock(page)
 		if err != nil {
 			return nil, 0, fmt.Errorf("failed to read page %d: %w", page, err)
 		}
-
-		if len(pageData) >= 4 {
-			result = append(result, pageData[:4]...)
-		} else {
-			result = append(result, pageData...)
-		}
+		result = append(result, pageData...)
 	}
 
 	return result, chunkEnd + 1, nil
@@ -190,14 +191,12 @@ func (t *TagOperations) readMIFAREBlocks(ctx context.Context, startBlock, endBlo
 
 	var result []byte
 
-	for block := startBlock; block <= endBlock; block++ {
-		// Skip trailer blocks (every 4th block in each sector)
-		if (block+1)%4 == 0 && block != 0 {
+	for block := int(startBlock); block <= int(endBlock) && block < t.mifareBlocks; block++ {
+		if pn532.IsMIFARETrailerBlock(block) {
 			continue
 		}
 
-		// ReadBlockAuto handles authentication automatically
-		data, err := t.mifareInstance.ReadBlockAuto(block)
+		data, err := t.readMIFAREBlock(block)
 		if err != nil {
 			return nil, fmt.Errorf("failed to read block %d: %w", block, err)
 		}
@@ -208,6 +207,28 @@ func (t *TagOperations) readMIFAREBlocks(ctx context.Context, startBlock, endBlo
 	return result, nil
 }
 
+// readMIFAREBlock reads a block with the NDEF key, falling back to the public
+// keys of sectors outside the NDEF mapping: the MAD key of sector 0 and the
+// transport key of blank sectors
+func (t *TagOperations) readMIFAREBlock(block int) ([]byte, error) {
+	// ReadBlockAuto handles authentication automatically
+	data, err := t.mifareInstance.ReadBlockAuto(byte(block))
+	if err == nil {
+		return data, nil
+	}
+
+	for _, key := range [][]byte{mifareMADKey, mifareTransportKey} {
+		// The MIFARE handler addresses sectors as block / 4
+		if t.mifareInstance.AuthenticateRobust(byte(block/4), pn532.MIFAREKeyA, key) != nil {
+			continue
+		}
+		if data, readErr := t.mifareInstance.ReadBlock(byte(block)); readErr == nil {
+			return data, nil
+		}
+	}
+	return nil, err
+}
+
 // GetCapacityInfo returns information about the tag's storage capacity
 func (t *TagOperations) GetCapacityInfo() (totalBytes, usableBytes int, err error) {
 	if t.tag == nil {
@@ -216,26 +237,21 @@ func (t *TagOperations) GetCapacityInfo() (totalBytes, usableBytes int, err erro
 
 	switch t.tagType {
 	case TagTypeNTAG:
-		totalBytes = t.totalPages * 4 // 4 bytes per page
+		totalBytes = t.totalPages * ntagPageBytes
 		// Usable bytes exclude UID, lock bytes, etc. (typically first 4 pages)
-		usableBytes = (t.totalPages - 4) * 4
+		usableBytes = (t.totalPages - ntagReservedPages) * ntagPageBytes
 		return totalBytes, usableBytes, nil
 
 	case TagTypeMIFARE:
-		if t.mifareInstance.IsMIFARE4K() {
-			// MIFARE Classic 4K: 40 sectors, 255 blocks total
-			totalBytes = 4096
-			// First 32 sectors: 3 data blocks each = 32 * 3 * 16 = 1536 bytes
-			// Last 8 sectors: 15 data blocks each = 8 * 15 * 16 = 1920 bytes
-			// Total usable: 1536 + 1920 = 3456 bytes
-			usableBytes = 3456
-		} else {
-			// MIFARE Classic 1K: 16 sectors, 64 blocks total
-			totalBytes = 1024
-			// Each sector has 3 data blocks (16 bytes each) + 1 trailer
-			// 16 sectors * 3 blocks * 16 bytes = 768 usable bytes
-			usableBytes = 768
-		}
+		totalBytes = t.mifareBlocks * mifareBlockBytes
+		// Every sector has a trailer holding keys and access bits
+		usableBytes = (t.mifareBlocks - t.sectors) * mifareBlockBytes
+		return totalBytes, usableBytes, nil
+
+	case TagTypeFeliCa:
+		// Nmaxb data blocks follow the attribute information block
+		totalBytes = (t.feliCaBlocks + 1) * feliCaBlockBytes
+		usableBytes = t.feliCaBlocks * feliCaBlockBytes
 		return totalBytes, usableBytes, nil
 
 	case TagTypeUnknown:
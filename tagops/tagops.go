@@ -35,6 +35,8 @@ var (
 	ErrUnsupportedTag = errors.New("unsupported tag type")
 	// ErrAuthFailed indicates all authentication attempts failed
 	ErrAuthFailed = errors.New("authentication failed with all known keys")
+	// ErrTagLocked indicates the tag is permanently write protected
+	ErrTagLocked = errors.New("tag is locked")
 )
 
 // TagType represents the type of NFC tag
@@ -47,6 +49,8 @@ const (
 	TagTypeNTAG
 	// TagTypeMIFARE represents a MIFARE Classic tag
 	TagTypeMIFARE
+	// TagTypeFeliCa represents a FeliCa tag formatted as an NFC Forum Type 3 tag
+	TagTypeFeliCa
 )
 
 // TagOperations provides unified high-level tag operations
@@ -56,10 +60,18 @@ type TagOperations struct {
 	tag            *pn532.DetectedTag
 	ntagInstance   *pn532.NTAGTag
 	mifareInstance *pn532.MIFARETag
+	feliCaInstance *pn532.FeliCaTag
+	feliCaInfo     *pn532.FeliCaAttributeInfo
+	mifareConfig   *pn532.MIFAREConfig
 
 	// Enum and int fields - group together for better alignment
-	tagType    TagType
-	totalPages int
+	tagType      TagType
+	totalPages   int
+	mifareBlocks int
+	sectors      int
+	feliCaBlocks int
+	ntagType     pn532.NTAGType
+	mifareNDEF   bool
 }
 
 // New creates a new TagOperations instance
@@ -72,8 +84,11 @@ func New(device *pn532.Device) *TagOperations {
 // DetectTag detects and initializes a tag for operations.
 // This must be called before any read/write operations.
 func (t *TagOperations) DetectTag(ctx context.Context) error {
-	// Detect tag
+	// Detect tag, FeliCa only answers polling at 212/424 kbps
 	tag, err := t.device.DetectTagContext(ctx)
+	if errors.Is(err, pn532.ErrNoTagDetected) {
+		tag, err = t.detectFeliCa(ctx)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to detect tag: %w", err)
 	}
@@ -102,31 +117,123 @@ func (t *TagOperations) GetUID() []byte {
 	return t.tag.UIDBytes
 }
 
+// Tag returns the handler of the detected tag for operations beyond the
+// unified API, such as rich NDEF records or NTAG password protection.
+// It is nil until DetectTag succeeds.
+func (t *TagOperations) Tag() pn532.Tag {
+	switch t.tagType {
+	case TagTypeNTAG:
+		return t.ntagInstance
+	case TagTypeMIFARE:
+		return t.mifareInstance
+	case TagTypeFeliCa:
+		return t.feliCaInstance
+	case TagTypeUnknown:
+		return nil
+	default:
+		return nil
+	}
+}
+
+// SetMIFAREConfig sets the retry and timing configuration for MIFARE Classic
+// tags found by the next DetectTag
+func (t *TagOperations) SetMIFAREConfig(config *pn532.MIFAREConfig) {
+	t.mifareConfig = config
+}
+
 // detectAndInitializeTag determines the tag type and sets up the appropriate handler
 func (t *TagOperations) detectAndInitializeTag() error {
+	t.resetHandlers()
 	if t.tag == nil {
 		return ErrNoTag
 	}
 
-	// Try NTAG detection first
+	switch t.tag.Type {
+	case pn532.TagTypeNTAG:
+		return t.initNTAG()
+	case pn532.TagTypeMIFARE:
+		return t.initMIFARE()
+	case pn532.TagTypeFeliCa:
+		return t.initFeliCa()
+	case pn532.TagTypeUnknown, pn532.TagTypeAny:
+	}
+
+	// The device couldn't classify the tag from ATQ/SAK, probe it instead
+	if err := t.initNTAG(); err == nil {
+		return nil
+	}
+	return t.initMIFARE()
+}
+
+// resetHandlers forgets the handlers of a previously detected tag
+func (t *TagOperations) resetHandlers() {
+	t.tagType = TagTypeUnknown
+	t.ntagInstance = nil
+	t.mifareInstance = nil
+	t.feliCaInstance = nil
+	t.feliCaInfo = nil
+	t.ntagType = pn532.NTAGTypeUnknown
+	t.totalPages = 0
+	t.mifareBlocks = 0
+	t.sectors = 0
+	t.feliCaBlocks = 0
+	t.mifareNDEF = false
+}
+
+// initNTAG identifies the NTAG variant and sets up the NTAG handler
+func (t *TagOperations) initNTAG() error {
 	ntag := pn532.NewNTAGTag(t.device, t.tag.UIDBytes, t.tag.SAK)
+	ntagType := pn532.NTAGTypeUnknown
 	if err := ntag.DetectType(); err == nil {
-		t.tagType = TagTypeNTAG
-		t.ntagInstance = ntag
-		t.totalPages = int(ntag.GetTotalPages())
-		return nil
+		ntagType = ntagTypeForPages(int(ntag.GetTotalPages()))
+	} else {
+		// DetectType needs a capability container, GET_VERSION alone
+		// identifies blank tags
+		version, versionErr := ntag.GetVersion()
+		if versionErr != nil {
+			return fmt.Errorf("%w: NTAG detection failed: %w", ErrUnsupportedTag, err)
+		}
+		ntagType = version.GetNTAGType()
+	}
+	if ntagType == pn532.NTAGTypeUnknown {
+		return fmt.Errorf("%w: unknown NTAG variant", ErrUnsupportedTag)
 	}
 
-	// Try MIFARE detection
+	t.tagType = TagTypeNTAG
+	t.ntagInstance = ntag
+	t.ntagType = ntagType
+	t.totalPages = ntagPages[ntagType]
+	return nil
+}
+
+// initMIFARE sets up the MIFARE Classic handler. A Classic SAK is enough to
+// classify the tag, other tags must pass tryMIFAREAuth.
+func (t *TagOperations) initMIFARE() error {
 	mifare := pn532.NewMIFARETag(t.device, t.tag.UIDBytes, t.tag.SAK)
-	// Try to authenticate with common keys to verify it's MIFARE
-	if t.tryMIFAREAuth(mifare) {
-		t.tagType = TagTypeMIFARE
-		t.mifareInstance = mifare
-		return nil
+	if t.mifareConfig != nil {
+		mifare.SetConfig(t.mifareConfig)
 	}
 
-	return ErrUnsupportedTag
+	ndefReadable := t.tryMIFAREAuth(mifare)
+	if !isMIFAREClassicSAK(t.tag.SAK) && !ndefReadable {
+		return ErrUnsupportedTag
+	}
+
+	t.tagType = TagTypeMIFARE
+	t.mifareInstance = mifare
+	t.mifareNDEF = ndefReadable
+	switch t.tag.SAK {
+	case sakMIFARE4K, sakSmartMX4K:
+		t.mifareBlocks, t.sectors = mifare4KBlocks, mifare4KSectors
+	case sakMIFAREMini:
+		t.mifareBlocks, t.sectors = mifareMiniBlocks, mifareMiniSectors
+	case sakMIFARE1K, sakMIFARE1KInfineon, sakSmartMX1K:
+		t.mifareBlocks, t.sectors = mifare1KBlocks, mifare1KSectors
+	default:
+		// Not a Classic SAK but NDEF readable, assume the smallest common layout
+		t.mifareBlocks, t.sectors = mifare1KBlocks, mifare1KSectors
+	}
+	return nil
 }
 
 // tryMIFAREAuth attempts to read a block to verify MIFARE functionality
@@ -0,0 +1,387 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package tagops
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ZaparooProject/go-pn532"
+	"github.com/ZaparooProject/go-pn532/pn532sim"
+	"github.com/hsanjuan/go-ndef"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testUID7 = []byte{0x04, 0xA1, 0xA2, 0xA3, 0xA4, 0xA5, 0xA6}
+	testUID4 = []byte{0xC1, 0xC2, 0xC3, 0xC4}
+	testIDm  = []byte{0x01, 0x2E, 0x4C, 0x7A, 0x12, 0x34, 0x56, 0x78}
+	ndefKey  = []byte{0xD3, 0xF7, 0xD3, 0xF7, 0xD3, 0xF7}
+)
+
+// newSimOps returns TagOperations on a simulated reader holding the tags
+func newSimOps(t *testing.T, tags ...pn532sim.Tag) (*TagOperations, *pn532sim.Simulator) {
+	t.Helper()
+
+	sim, err := pn532sim.New(pn532sim.WithTags(tags...))
+	require.NoError(t, err)
+	device, err := pn532.New(sim)
+	require.NoError(t, err)
+	require.NoError(t, device.Init())
+	t.Cleanup(func() { _ = device.Close() })
+
+	ops := New(device)
+	// Three quick attempts, the third re-selects a tag halted by a wrong key
+	ops.SetMIFAREConfig(&pn532.MIFAREConfig{
+		RetryConfig: &pn532.RetryConfig{
+			MaxAttempts:       3,
+			InitialBackoff:    time.Microsecond,
+			MaxBackoff:        10 * time.Microsecond,
+			BackoffMultiplier: 2.0,
+			RetryTimeout:      time.Second,
+		},
+	})
+	return ops, sim
+}
+
+// formatClassicNDEF gives every sector the NDEF key with key A reading and
+// key B reading and writing
+func formatClassicNDEF(t *testing.T, tag *pn532sim.Classic, blocks int) {
+	t.Helper()
+
+	trailer := append(append(append([]byte(nil), ndefKey...), 0x7F, 0x07, 0x88, 0x40), ndefKey...)
+	for block := range blocks {
+		if pn532.IsMIFARETrailerBlock(block) {
+			require.NoError(t, tag.SetBlock(block, trailer))
+		}
+	}
+}
+
+func TestDetectTag_Classification(t *testing.T) {
+	t.Parallel()
+
+	formatted1K := pn532sim.NewClassic1K(testUID4)
+	formatClassicNDEF(t, formatted1K, mifare1KBlocks)
+	blankNTAG := pn532sim.NewNTAG215(testUID7)
+	require.NoError(t, blankNTAG.SetPage(3, make([]byte, 4)))
+	smartMX1K := pn532sim.NewClassic1K(testUID4)
+	smartMX1K.SetSAK(sakSmartMX1K)
+	smartMX4K := pn532sim.NewClassic4K(testUID4)
+	smartMX4K.SetSAK(sakSmartMX4K)
+
+	tests := []struct {
+		tag         pn532sim.Tag
+		name        string
+		want        TagInfo
+		usableBytes int
+		ndefCapable bool
+	}{
+		{
+			name: "NTAG213", tag: pn532sim.NewNTAG213(testUID7), ndefCapable: true, usableBytes: 164,
+			want: TagInfo{
+				Type: TagTypeNTAG, TypeName: "NTAG", NTAGType: "NTAG213",
+				TotalPages: 45, UserMemory: 164, TotalMemory: 180,
+			},
+		},
+		{
+			name: "NTAG216", tag: pn532sim.NewNTAG216(testUID7), ndefCapable: true, usableBytes: 908,
+			want: TagInfo{
+				Type: TagTypeNTAG, TypeName: "NTAG", NTAGType: "NTAG216",
+				TotalPages: 231, UserMemory: 908, TotalMemory: 924,
+			},
+		},
+		{
+			name: "Blank_NTAG215", tag: blankNTAG, ndefCapable: true, usableBytes: 524,
+			want: TagInfo{
+				Type: TagTypeNTAG, TypeName: "NTAG", NTAGType: "NTAG215",
+				TotalPages: 135, UserMemory: 524, TotalMemory: 540,
+			},
+		},
+		{
+			name: "Blank_Classic1K", tag: pn532sim.NewClassic1K(testUID4), usableBytes: 768,
+			want: TagInfo{
+				Type: TagTypeMIFARE, TypeName: "MIFARE Classic", MIFAREType: "MIFARE Classic 1K",
+				Sectors: 16, UserMemory: 768, TotalMemory: 1024,
+			},
+		},
+		{
+			name: "NDEF_Classic1K", tag: formatted1K, ndefCapable: true, usableBytes: 768,
+			want: TagInfo{
+				Type: TagTypeMIFARE, TypeName: "MIFARE Classic", MIFAREType: "MIFARE Classic 1K",
+				Sectors: 16, UserMemory: 768, TotalMemory: 1024,
+			},
+		},
+		{
+			name: "Classic4K", tag: pn532sim.NewClassic4K(testUID4), usableBytes: 3456,
+			want: TagInfo{
+				Type: TagTypeMIFARE, TypeName: "MIFARE Classic", MIFAREType: "MIFARE Classic 4K",
+				Sectors: 40, UserMemory: 3456, TotalMemory: 4096,
+			},
+		},
+		{
+			name: "SmartMX1K", tag: smartMX1K, usableBytes: 768,
+			want: TagInfo{
+				Type: TagTypeMIFARE, TypeName: "MIFARE Classic", MIFAREType: "MIFARE Classic 1K",
+				Sectors: 16, UserMemory: 768, TotalMemory: 1024,
+			},
+		},
+		{
+			name: "SmartMX4K", tag: smartMX4K, usableBytes: 3456,
+			want: TagInfo{
+				Type: TagTypeMIFARE, TypeName: "MIFARE Classic", MIFAREType: "MIFARE Classic 4K",
+				Sectors: 40, UserMemory: 3456, TotalMemory: 4096,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ops, _ := newSimOps(t, tt.tag)
+			require.NoError(t, ops.DetectTag(context.Background()))
+			assert.Equal(t, tt.want.Type, ops.TagType())
+			assert.Equal(t, tt.ndefCapable, ops.IsNDEFCapable())
+
+			info, err := ops.GetTagInfo()
+			require.NoError(t, err)
+			want := tt.want
+			want.UID = ops.GetUID()
+			assert.Equal(t, &want, info)
+
+			total, usable, err := ops.GetCapacityInfo()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want.TotalMemory, total)
+			assert.Equal(t, tt.usableBytes, usable)
+		})
+	}
+}
+
+func TestDetectTag_FeliCa(t *testing.T) {
+	t.Parallel()
+
+	ops, _ := newSimOps(t, pn532sim.NewFeliCa(testIDm))
+	require.NoError(t, ops.DetectTag(context.Background()))
+	assert.Equal(t, TagTypeFeliCa, ops.TagType())
+	assert.Equal(t, "FeliCa", ops.TagType().String())
+	assert.True(t, ops.IsNDEFCapable())
+	assert.IsType(t, &pn532.FeliCaTag{}, ops.Tag())
+
+	info, err := ops.GetTagInfo()
+	require.NoError(t, err)
+	assert.Equal(t, "FeliCa", info.TypeName)
+	assert.Equal(t, testIDm, info.IDm)
+	assert.Len(t, info.PMm, 8)
+	assert.Equal(t, uint16(0x12FC), info.SystemCode)
+	assert.Equal(t, 14*16, info.TotalMemory)
+
+	total, usable, err := ops.GetCapacityInfo()
+	require.NoError(t, err)
+	assert.Equal(t, 14*16, total)
+	assert.Equal(t, 13*16, usable)
+}
+
+func TestDetectTag_NoTag(t *testing.T) {
+	t.Parallel()
+
+	ops, _ := newSimOps(t)
+	require.ErrorIs(t, ops.DetectTag(context.Background()), pn532.ErrNoTagDetected)
+	assert.Equal(t, TagTypeUnknown, ops.TagType())
+	assert.Nil(t, ops.Tag())
+}
+
+func TestDetectTag_ResetsPreviousTag(t *testing.T) {
+	t.Parallel()
+
+	ntag := pn532sim.NewNTAG213(testUID7)
+	ops, sim := newSimOps(t, ntag)
+	require.NoError(t, ops.DetectTag(context.Background()))
+	require.NotNil(t, ops.ntagInstance)
+
+	sim.RemoveTag(ntag)
+	sim.PlaceTag(pn532sim.NewClassic1K(testUID4))
+	require.NoError(t, ops.DetectTag(context.Background()))
+	assert.Equal(t, TagTypeMIFARE, ops.TagType())
+	assert.IsType(t, &pn532.MIFARETag{}, ops.Tag())
+	assert.Nil(t, ops.ntagInstance)
+	assert.Zero(t, ops.totalPages)
+}
+
+func TestNTAGBlocks_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tag := pn532sim.NewNTAG213(testUID7)
+	ops, _ := newSimOps(t, tag)
+	ctx := context.Background()
+	require.NoError(t, ops.DetectTag(ctx))
+
+	data := []byte("tagops writes pages")
+	require.NoError(t, ops.WriteBlocks(ctx, 10, data))
+	read, err := ops.ReadBlocks(ctx, 10, 14)
+	require.NoError(t, err)
+	assert.Equal(t, append(data, 0), read)
+	assert.Equal(t, data[:4], tag.Memory()[40:44])
+
+	require.Error(t, ops.WriteBlocks(ctx, 2, data), "pages 0-3 are reserved")
+	require.Error(t, ops.WriteBlocks(ctx, 44, data), "beyond the last page")
+
+	all, err := ops.ReadAll(ctx)
+	require.NoError(t, err)
+	assert.Len(t, all, 45*4)
+
+	require.NoError(t, ops.EraseBlocks(ctx, 10, 14))
+	read, err = ops.ReadBlocks(ctx, 10, 14)
+	require.NoError(t, err)
+	assert.Equal(t, make([]byte, 20), read)
+}
+
+func TestMIFAREBlocks_SkipTrailers(t *testing.T) {
+	t.Parallel()
+
+	tag := pn532sim.NewClassic1K(testUID4)
+	formatClassicNDEF(t, tag, mifare1KBlocks)
+	ops, _ := newSimOps(t, tag)
+	ctx := context.Background()
+	require.NoError(t, ops.DetectTag(ctx))
+
+	// Three blocks from block 5 land in 5, 6 and 8, 7 is the trailer
+	data := make([]byte, 3*mifareBlockBytes)
+	for i := range data {
+		data[i] = byte(i + 1)
+	}
+	require.NoError(t, ops.WriteBlocks(ctx, 5, data))
+	assert.Equal(t, data[32:], tag.Block(8))
+
+	read, err := ops.ReadBlocks(ctx, 5, 8)
+	require.NoError(t, err)
+	assert.Equal(t, data, read)
+
+	require.NoError(t, ops.EraseBlocks(ctx, 5, 8))
+	read, err = ops.ReadBlocks(ctx, 5, 8)
+	require.NoError(t, err)
+	assert.Equal(t, make([]byte, len(data)), read)
+	assert.Equal(t, ndefKey, tag.Block(7)[:6], "trailer left alone")
+
+	require.Error(t, ops.WriteBlocks(ctx, 0, data), "manufacturer block")
+	require.Error(t, ops.WriteBlocks(ctx, 62, data), "beyond the last block")
+}
+
+func TestMIFAREReadAll_4KLargeSectors(t *testing.T) {
+	t.Parallel()
+
+	tag := pn532sim.NewClassic4K(testUID4)
+	formatClassicNDEF(t, tag, mifare4KBlocks)
+	want := []byte("large sector 200")
+	require.NoError(t, tag.SetBlock(200, want))
+	ops, _ := newSimOps(t, tag)
+	ctx := context.Background()
+	require.NoError(t, ops.DetectTag(ctx))
+
+	all, err := ops.ReadAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, (mifare4KBlocks-mifare4KSectors)*mifareBlockBytes)
+	assert.Contains(t, string(all), string(want))
+}
+
+func TestMIFAREReadAll_PublicKeys(t *testing.T) {
+	t.Parallel()
+
+	tag := pn532sim.NewClassic1K(testUID4)
+	ops, _ := newSimOps(t, tag)
+	ctx := context.Background()
+	require.NoError(t, ops.DetectTag(ctx))
+
+	// Blank sectors open with the transport key
+	all, err := ops.ReadAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, (mifare1KBlocks-mifare1KSectors)*mifareBlockBytes)
+	assert.Equal(t, tag.Block(0), all[:mifareBlockBytes])
+
+	// Sector 0 of a formatted tag opens with the MAD key
+	_, err = ops.Format(ctx)
+	require.NoError(t, err)
+	all, err = ops.ReadAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, buildMAD(15, 15), all[mifareBlockBytes:3*mifareBlockBytes])
+	assert.Equal(t, []byte{0x03, 0x00, 0xFE}, all[3*mifareBlockBytes:3*mifareBlockBytes+3])
+}
+
+func TestFeliCaBlocks_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tag := pn532sim.NewFeliCa(testIDm)
+	ops, _ := newSimOps(t, tag)
+	ctx := context.Background()
+	require.NoError(t, ops.DetectTag(ctx))
+
+	data := []byte("felica blocks are sixteen bytes wide")
+	require.NoError(t, ops.WriteBlocks(ctx, 2, data))
+	assert.Equal(t, data[:16], tag.Block(2))
+	read, err := ops.ReadBlocks(ctx, 2, 4)
+	require.NoError(t, err)
+	assert.Equal(t, append(data, make([]byte, 48-len(data))...), read)
+
+	require.Error(t, ops.WriteBlocks(ctx, 0, data), "attribute information block")
+	require.Error(t, ops.WriteBlocks(ctx, 12, data), "beyond the last block")
+
+	all, err := ops.ReadAll(ctx)
+	require.NoError(t, err)
+	assert.Len(t, all, 14*16)
+
+	require.NoError(t, ops.EraseBlocks(ctx, 2, 4))
+	read, err = ops.ReadBlocks(ctx, 2, 4)
+	require.NoError(t, err)
+	assert.Equal(t, make([]byte, 48), read)
+}
+
+func TestFeliCaNDEF_FormatAndRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tag := pn532sim.NewFeliCa(testIDm)
+	// Wipe the AIB so the tag looks unformatted
+	require.NoError(t, tag.SetBlock(0, make([]byte, 16)))
+	ops, _ := newSimOps(t, tag)
+	ctx := context.Background()
+	require.NoError(t, ops.DetectTag(ctx))
+	assert.False(t, ops.IsNDEFCapable())
+
+	_, usable, err := ops.GetCapacityInfo()
+	require.NoError(t, err)
+	assert.Equal(t, 13*16, usable, "block count probed")
+
+	result, err := ops.Format(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []FormatStep{FormatStepAttributeInfo}, result.Steps)
+	assert.True(t, ops.IsNDEFCapable())
+	aib := tag.Block(0)
+	assert.Equal(t, []byte{0x10, 0x01, 0x01, 0x00, 0x0D}, aib[:5])
+	assert.Equal(t, byte(0x01), aib[10], "RWFlag")
+
+	require.NoError(t, ops.WriteNDEF(ctx, ndef.NewTextMessage("hello", "en")))
+	msg, err := ops.ReadNDEF(ctx)
+	require.NoError(t, err)
+	require.Len(t, msg.Records, 1)
+	payload, err := msg.Records[0].Payload()
+	require.NoError(t, err)
+	assert.Contains(t, string(payload.Marshal()), "hello")
+}
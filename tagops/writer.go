@@ -43,6 +43,8 @@ func (t *TagOperations) WriteBlocks(ctx context.Context, startBlock byte, data [
 		return t.writeNTAGBlocks(ctx, startBlock, data)
 	case TagTypeMIFARE:
 		return t.writeMIFAREBlocks(ctx, startBlock, data)
+	case TagTypeFeliCa:
+		return t.writeFeliCaBlocks(ctx, startBlock, data)
 	case TagTypeUnknown:
 		return ErrUnsupportedTag
 	default:
@@ -69,6 +71,12 @@ func (t *TagOperations) WriteNDEF(_ context.Context, msg *ndef.Message) error {
 			return fmt.Errorf("failed to write NDEF to MIFARE: %w", err)
 		}
 		return nil
+	case TagTypeFeliCa:
+		pn532Msg := convertToPN532Message(msg)
+		if err := t.feliCaInstance.WriteNDEF(pn532Msg); err != nil {
+			return fmt.Errorf("failed to write NDEF to FeliCa: %w", err)
+		}
+		return nil
 	case TagTypeUnknown:
 		return ErrUnsupportedTag
 	default:
@@ -107,10 +115,7 @@ func (t *TagOperations) writeNTAGBlocks(_ context.Context, startBlock byte, data
 		}
 		copy(pageData, data[dataStart:dataEnd])
 
-		// Write command: 0xA2 page data[4]
-		cmd := append([]byte{0xA2, page}, pageData...)
-		_, err := t.device.SendDataExchange(cmd)
-		if err != nil {
+		if err := t.ntagInstance.WriteBlock(page, pageData); err != nil {
 			return fmt.Errorf("failed to write page %d: %w", page, err)
 		}
 	}
@@ -129,29 +134,25 @@ func (t *TagOperations) writeMIFAREBlocks(_ context.Context, startBlock byte, da
 		return errors.New("cannot write to manufacturer block (0)")
 	}
 
-	// Calculate how many blocks we need to write
-	numBlocks := (len(data) + 15) / 16 // Round up to nearest block
-
-	// Write block by block
-	for i := 0; i < numBlocks; i++ {
-		block := startBlock + byte(i)
+	// Write block by block, data continues after a skipped trailer
+	block := int(startBlock)
+	for offset := 0; offset < len(data); block++ {
+		if block >= t.mifareBlocks {
+			return errors.New("write would exceed tag capacity")
+		}
 
-		// Skip trailer blocks (every 4th block in each sector)
-		if (block+1)%4 == 0 && block != 0 {
+		// Skip trailer blocks, they hold the keys and access bits
+		if pn532.IsMIFARETrailerBlock(block) {
 			continue
 		}
 
 		// Get 16 bytes for this block (pad with zeros if necessary)
-		blockData := make([]byte, 16)
-		dataStart := i * 16
-		dataEnd := dataStart + 16
-		if dataEnd > len(data) {
-			dataEnd = len(data)
-		}
-		copy(blockData, data[dataStart:dataEnd])
+		blockData := make([]byte, mifareBlockBytes)
+		copy(blockData, data[offset:min(offset+mifareBlockBytes, len(data))])
+		offset += mifareBlockBytes
 
 		// WriteBlockAuto handles authentication automatically
-		err := t.mifareInstance.WriteBlockAuto(block, blockData)
+		err := t.mifareInstance.WriteBlockAuto(byte(block), blockData)
 		if err != nil {
 			return fmt.Errorf("failed to write block %d: %w", block, err)
 		}
@@ -162,42 +163,33 @@ func (t *TagOperations) writeMIFAREBlocks(_ context.Context, startBlock byte, da
 
 // EraseBlocks writes zeros to the specified block range
 func (t *TagOperations) EraseBlocks(ctx context.Context, startBlock, endBlock byte) error {
-	numBlocks := int(endBlock - startBlock + 1)
-	blockSize := 16 // MIFARE block size, NTAG pages are smaller but we'll use max
-
-	if t.tagType == TagTypeNTAG {
-		blockSize = 4
-	}
-
-	zeros := make([]byte, numBlocks*blockSize)
-	return t.WriteBlocks(ctx, startBlock, zeros)
-}
-
-// Format prepares the tag for NDEF use
-func (t *TagOperations) Format(ctx context.Context) error {
 	if t.tag == nil {
 		return ErrNoTag
 	}
 
+	if endBlock < startBlock {
+		return errors.New("invalid block range")
+	}
+
+	numBlocks := int(endBlock) - int(startBlock) + 1
+	blockSize := mifareBlockBytes
 	switch t.tagType {
 	case TagTypeNTAG:
-		// NTAG tags don't need explicit formatting
-		// Just write an empty NDEF message
-		emptyNDEF := ndef.NewMessage(ndef.Empty, "", "", nil)
-		return t.WriteNDEF(ctx, emptyNDEF)
-
+		blockSize = ntagPageBytes
 	case TagTypeMIFARE:
-		// For MIFARE, formatting means writing an empty NDEF message
-		// The WriteNDEF method handles formatting if needed
-		emptyNDEF := ndef.NewMessage(ndef.Empty, "", "", nil)
-		return t.WriteNDEF(ctx, emptyNDEF)
-
+		// Trailers in the range are skipped by the write
+		for block := int(startBlock); block <= int(endBlock); block++ {
+			if pn532.IsMIFARETrailerBlock(block) {
+				numBlocks--
+			}
+		}
+	case TagTypeFeliCa:
+		blockSize = feliCaBlockBytes
 	case TagTypeUnknown:
-		return ErrUnsupportedTag
-
-	default:
-		return ErrUnsupportedTag
 	}
+
+	zeros := make([]byte, numBlocks*blockSize)
+	return t.WriteBlocks(ctx, startBlock, zeros)
 }
 
 // convertToPN532Message converts from ndef.Message to pn532.NDEFMessage
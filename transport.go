@@ -83,6 +83,21 @@ type TransportCapabilityChecker interface {
 	HasCapability(capability TransportCapability) bool
 }
 
+// HardResetter is implemented by transports that can hard-reset the PN532,
+// e.g. through a GPIO wired to its RSTPDN line
+type HardResetter interface {
+	// HardReset resets the PN532; the device must be re-initialized afterwards
+	HardReset() error
+}
+
+// Reopener is implemented by transports that can close and reopen their
+// connection, e.g. a serial port whose USB adapter wedged
+type Reopener interface {
+	// Reopen closes and reopens the connection; the device must be
+	// re-initialized afterwards
+	Reopen() error
+}
+
 // TransportWithRetry wraps a Transport with retry capabilities
 type TransportWithRetry struct {
 	transport Transport
@@ -179,6 +194,25 @@ func (t *TransportWithRetry) SetRetryConfig(config *RetryConfig) {
 	t.config = config
 }
 
+// Unwrap returns the underlying transport
+func (t *TransportWithRetry) Unwrap() Transport {
+	return t.transport
+}
+
+// transportWrapper is implemented by middleware transports wrapping another one
+type transportWrapper interface {
+	Unwrap() Transport
+}
+
+// unwrapTransport returns the transport wrapped by a middleware transport,
+// or nil if the transport is not a wrapper
+func unwrapTransport(transport Transport) Transport {
+	if wrapper, ok := transport.(transportWrapper); ok {
+		return wrapper.Unwrap()
+	}
+	return nil
+}
+
 // MockTransport provides a mock implementation of Transport for testing
 type MockTransport struct {
 	responses map[byte][]byte
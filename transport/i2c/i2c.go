@@ -29,6 +29,7 @@ import (
 
 	pn532 "github.com/ZaparooProject/go-pn532"
 	"github.com/ZaparooProject/go-pn532/internal/frame"
+	"github.com/ZaparooProject/go-pn532/internal/pins"
 	"periph.io/x/conn/v3/i2c"
 	"periph.io/x/conn/v3/i2c/i2creg"
 	"periph.io/x/conn/v3/physic"
@@ -54,15 +55,72 @@ var (
 	nackFrame = []byte{0x00, 0x00, 0xFF, 0xFF, 0x00, 0x00}
 )
 
+// IRQPin is a GPIO input wired to the PN532 IRQ line.
+// periph.io gpio.PinIn satisfies this interface.
+type IRQPin = pins.IRQPin
+
+// ResetPin is a GPIO output wired to the PN532 RSTPDN line.
+// periph.io gpio.PinOut satisfies this interface.
+type ResetPin = pins.ResetPin
+
 // Transport implements the pn532.Transport interface for I2C communication
 type Transport struct {
-	dev     *i2c.Dev
-	busName string
-	timeout time.Duration
+	dev      *i2c.Dev
+	irqPin   IRQPin
+	resetPin ResetPin
+	busName  string
+	timeout  time.Duration
+}
+
+// Option is a functional option for configuring an I2C transport
+type Option func(*Transport) error
+
+// WithIRQPin waits on the PN532 IRQ line instead of polling the ready byte
+func WithIRQPin(pin IRQPin) Option {
+	return func(t *Transport) error {
+		if err := pins.ConfigureIRQ(pin); err != nil {
+			return fmt.Errorf("I2C IRQ setup failed: %w", err)
+		}
+		t.irqPin = pin
+		return nil
+	}
+}
+
+// WithIRQPinName is like WithIRQPin but looks the pin up by name (e.g. "GPIO4")
+func WithIRQPinName(name string) Option {
+	return func(t *Transport) error {
+		pin, err := pins.ByName(name)
+		if err != nil {
+			return fmt.Errorf("I2C IRQ setup failed: %w", err)
+		}
+		return WithIRQPin(pin)(t)
+	}
+}
+
+// WithResetPin enables HardReset through the PN532 RSTPDN line
+func WithResetPin(pin ResetPin) Option {
+	return func(t *Transport) error {
+		if err := pins.ConfigureReset(pin); err != nil {
+			return fmt.Errorf("I2C reset setup failed: %w", err)
+		}
+		t.resetPin = pin
+		return nil
+	}
+}
+
+// WithResetPinName is like WithResetPin but looks the pin up by name (e.g. "GPIO17")
+func WithResetPinName(name string) Option {
+	return func(t *Transport) error {
+		pin, err := pins.ByName(name)
+		if err != nil {
+			return fmt.Errorf("I2C reset setup failed: %w", err)
+		}
+		return WithResetPin(pin)(t)
+	}
 }
 
 // New creates a new I2C transport
-func New(busName string) (*Transport, error) {
+func New(busName string, opts ...Option) (*Transport, error) {
 	// Initialize host
 	if _, err := host.Init(); err != nil {
 		return nil, fmt.Errorf("failed to initialize periph host: %w", err)
@@ -86,9 +144,37 @@ func New(busName string) (*Transport, error) {
 		timeout: 50 * time.Millisecond,
 	}
 
+	for _, opt := range opts {
+		if err := opt(transport); err != nil {
+			_ = bus.Close()
+			return nil, err
+		}
+	}
+
 	return transport, nil
 }
 
+// HardReset pulses the RSTPDN line to reset a wedged PN532.
+// The device must be re-initialized with InitContext afterwards.
+func (t *Transport) HardReset() error {
+	if t.resetPin == nil {
+		return fmt.Errorf("%w: no reset pin configured for %s", pn532.ErrDeviceNotSupported, t.busName)
+	}
+	if err := pins.HardReset(t.resetPin); err != nil {
+		return fmt.Errorf("I2C hard reset failed: %w", err)
+	}
+	return nil
+}
+
+// waitIRQ blocks until the IRQ line signals ready or the deadline passes.
+// Without an IRQ pin it returns true immediately and callers fall back to polling.
+func (t *Transport) waitIRQ(deadline time.Time) bool {
+	if t.irqPin == nil {
+		return true
+	}
+	return pins.WaitIRQ(t.irqPin, deadline)
+}
+
 // SendCommand sends a command to the PN532 and waits for response
 func (t *Transport) SendCommand(cmd byte, args []byte) ([]byte, error) {
 	if err := t.sendFrame(cmd, args); err != nil {
@@ -211,6 +297,11 @@ func (t *Transport) waitAck() error {
 	defer frame.PutBuffer(ackBuf)
 
 	for time.Now().Before(deadline) {
+		// Block on the IRQ line when available instead of busy-polling
+		if !t.waitIRQ(deadline) {
+			break
+		}
+
 		// Check if PN532 is ready
 		if err := t.checkReady(); err != nil {
 			time.Sleep(time.Millisecond)
@@ -263,7 +354,7 @@ func (t *Transport) receiveFrame() ([]byte, error) {
 			}
 		}
 
-		data, shouldRetry, err := t.receiveFrameAttempt()
+		data, shouldRetry, err := t.receiveFrameAttempt(deadline)
 		if err != nil {
 			return nil, err
 		}
@@ -287,7 +378,12 @@ func (t *Transport) receiveFrame() ([]byte, error) {
 }
 
 // receiveFrameAttempt performs a single frame receive attempt
-func (t *Transport) receiveFrameAttempt() (data []byte, shouldRetry bool, err error) {
+func (t *Transport) receiveFrameAttempt(deadline time.Time) (data []byte, shouldRetry bool, err error) {
+	// Block on the IRQ line when available instead of busy-polling
+	if !t.waitIRQ(deadline) {
+		return nil, true, nil
+	}
+
 	// Check if PN532 is ready
 	if readyErr := t.checkReady(); readyErr != nil {
 		time.Sleep(time.Millisecond)
@@ -386,5 +482,8 @@ func (t *Transport) extractI2CFrameData(buf []byte, off, frameLen int) (data []b
 	return data, false, nil
 }
 
-// Ensure Transport implements pn532.Transport
-var _ pn532.Transport = (*Transport)(nil)
+// Ensure Transport implements pn532.Transport and pn532.HardResetter
+var (
+	_ pn532.Transport    = (*Transport)(nil)
+	_ pn532.HardResetter = (*Transport)(nil)
+)
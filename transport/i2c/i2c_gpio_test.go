@@ -0,0 +1,92 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package i2c
+
+import (
+	"testing"
+	"time"
+
+	pn532 "github.com/ZaparooProject/go-pn532"
+	testutil "github.com/ZaparooProject/go-pn532/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"periph.io/x/conn/v3/gpio"
+)
+
+func TestWithIRQPin(t *testing.T) {
+	t.Parallel()
+
+	pin := testutil.NewFakeIRQPin()
+	transport := &Transport{}
+	require.NoError(t, WithIRQPin(pin)(transport))
+
+	pull, edge := pin.Config()
+	assert.Equal(t, gpio.PullUp, pull)
+	assert.Equal(t, gpio.FallingEdge, edge)
+}
+
+func TestTransport_WaitIRQ(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Without_Pin_Falls_Back_To_Polling", func(t *testing.T) {
+		t.Parallel()
+		transport := &Transport{}
+		assert.True(t, transport.waitIRQ(time.Now()))
+	})
+
+	t.Run("Blocks_Until_Asserted", func(t *testing.T) {
+		t.Parallel()
+		pin := testutil.NewFakeIRQPin()
+		transport := &Transport{}
+		require.NoError(t, WithIRQPin(pin)(transport))
+
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			pin.Assert()
+		}()
+		assert.True(t, transport.waitIRQ(time.Now().Add(time.Second)))
+	})
+
+	t.Run("Times_Out", func(t *testing.T) {
+		t.Parallel()
+		transport := &Transport{}
+		require.NoError(t, WithIRQPin(testutil.NewFakeIRQPin())(transport))
+		assert.False(t, transport.waitIRQ(time.Now().Add(10*time.Millisecond)))
+	})
+}
+
+func TestTransport_HardReset(t *testing.T) {
+	t.Parallel()
+
+	pin := &testutil.FakeResetPin{}
+	transport := &Transport{busName: "/dev/i2c-1"}
+	require.NoError(t, WithResetPin(pin)(transport))
+	require.NoError(t, transport.HardReset())
+
+	assert.Equal(t, []gpio.Level{gpio.High, gpio.Low, gpio.High}, pin.Levels())
+}
+
+func TestTransport_HardReset_NoPin(t *testing.T) {
+	t.Parallel()
+
+	transport := &Transport{busName: "/dev/i2c-1"}
+	require.ErrorIs(t, transport.HardReset(), pn532.ErrDeviceNotSupported)
+}
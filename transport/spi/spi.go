@@ -29,6 +29,7 @@ import (
 
 	"github.com/ZaparooProject/go-pn532"
 	"github.com/ZaparooProject/go-pn532/internal/frame"
+	"github.com/ZaparooProject/go-pn532/internal/pins"
 	"periph.io/x/conn/v3/physic"
 	"periph.io/x/conn/v3/spi"
 	"periph.io/x/conn/v3/spi/spireg"
@@ -56,16 +57,73 @@ var (
 	nackFrame = []byte{0x00, 0x00, 0xFF, 0xFF, 0x00, 0x00}
 )
 
+// IRQPin is a GPIO input wired to the PN532 IRQ line.
+// periph.io gpio.PinIn satisfies this interface.
+type IRQPin = pins.IRQPin
+
+// ResetPin is a GPIO output wired to the PN532 RSTPDN line.
+// periph.io gpio.PinOut satisfies this interface.
+type ResetPin = pins.ResetPin
+
 // Transport implements the pn532.Transport interface for SPI communication
 type Transport struct {
 	port     spi.PortCloser
 	conn     spi.Conn
+	irqPin   IRQPin
+	resetPin ResetPin
 	portName string
 	timeout  time.Duration
 }
 
+// Option is a functional option for configuring an SPI transport
+type Option func(*Transport) error
+
+// WithIRQPin waits on the PN532 IRQ line instead of polling the status byte
+func WithIRQPin(pin IRQPin) Option {
+	return func(t *Transport) error {
+		if err := pins.ConfigureIRQ(pin); err != nil {
+			return fmt.Errorf("SPI IRQ setup failed: %w", err)
+		}
+		t.irqPin = pin
+		return nil
+	}
+}
+
+// WithIRQPinName is like WithIRQPin but looks the pin up by name (e.g. "GPIO25")
+func WithIRQPinName(name string) Option {
+	return func(t *Transport) error {
+		pin, err := pins.ByName(name)
+		if err != nil {
+			return fmt.Errorf("SPI IRQ setup failed: %w", err)
+		}
+		return WithIRQPin(pin)(t)
+	}
+}
+
+// WithResetPin enables HardReset through the PN532 RSTPDN line
+func WithResetPin(pin ResetPin) Option {
+	return func(t *Transport) error {
+		if err := pins.ConfigureReset(pin); err != nil {
+			return fmt.Errorf("SPI reset setup failed: %w", err)
+		}
+		t.resetPin = pin
+		return nil
+	}
+}
+
+// WithResetPinName is like WithResetPin but looks the pin up by name (e.g. "GPIO17")
+func WithResetPinName(name string) Option {
+	return func(t *Transport) error {
+		pin, err := pins.ByName(name)
+		if err != nil {
+			return fmt.Errorf("SPI reset setup failed: %w", err)
+		}
+		return WithResetPin(pin)(t)
+	}
+}
+
 // New creates a new SPI transport
-func New(portName string) (*Transport, error) {
+func New(portName string, opts ...Option) (*Transport, error) {
 	// Initialize host
 	if _, err := host.Init(); err != nil {
 		return nil, fmt.Errorf("failed to initialize periph host: %w", err)
@@ -91,12 +149,34 @@ func New(portName string) (*Transport, error) {
 		timeout:  50 * time.Millisecond,
 	}
 
+	for _, opt := range opts {
+		if err := opt(transport); err != nil {
+			_ = port.Close()
+			return nil, err
+		}
+	}
+
 	// Wake up the PN532
 	transport.wakeup()
 
 	return transport, nil
 }
 
+// HardReset pulses the RSTPDN line to reset a wedged PN532.
+// The device must be re-initialized with InitContext afterwards.
+func (t *Transport) HardReset() error {
+	if t.resetPin == nil {
+		return fmt.Errorf("%w: no reset pin configured for %s", pn532.ErrDeviceNotSupported, t.portName)
+	}
+	if err := pins.HardReset(t.resetPin); err != nil {
+		return fmt.Errorf("SPI hard reset failed: %w", err)
+	}
+
+	// The chip comes back up in power-down and needs a wake-up over SPI
+	t.wakeup()
+	return nil
+}
+
 // wakeup sends the wake up sequence to PN532
 func (t *Transport) wakeup() {
 	// Send a dummy byte to wake up the PN532
@@ -128,9 +208,18 @@ func reverseBytes(data []byte) []byte {
 	return reversed[:len(data)] // Slice to exact size needed
 }
 
-// waitReady polls the PN532 status until it's ready
+// waitReady waits for the PN532 to be ready, blocking on the IRQ line
+// when one is configured and polling the status byte otherwise
 func (t *Transport) waitReady() error {
 	deadline := time.Now().Add(t.timeout)
+
+	if t.irqPin != nil {
+		if pins.WaitIRQ(t.irqPin, deadline) {
+			return nil
+		}
+		return pn532.NewTransportNotReadyError("waitReady", t.portName)
+	}
+
 	statusCmd := []byte{reverseBit(spiStatRead), 0}
 
 	// Use buffer pool for status response
@@ -383,3 +472,9 @@ func (t *Transport) IsConnected() bool {
 func (*Transport) Type() pn532.TransportType {
 	return pn532.TransportSPI
 }
+
+// Ensure Transport implements pn532.Transport and pn532.HardResetter
+var (
+	_ pn532.Transport    = (*Transport)(nil)
+	_ pn532.HardResetter = (*Transport)(nil)
+)
@@ -0,0 +1,77 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package spi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ZaparooProject/go-pn532"
+	testutil "github.com/ZaparooProject/go-pn532/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"periph.io/x/conn/v3/gpio"
+)
+
+func TestWithIRQPin(t *testing.T) {
+	t.Parallel()
+
+	pin := testutil.NewFakeIRQPin()
+	transport := &Transport{}
+	require.NoError(t, WithIRQPin(pin)(transport))
+
+	pull, edge := pin.Config()
+	assert.Equal(t, gpio.PullUp, pull)
+	assert.Equal(t, gpio.FallingEdge, edge)
+}
+
+func TestTransport_WaitReady_IRQ(t *testing.T) {
+	t.Parallel()
+
+	pin := testutil.NewFakeIRQPin()
+	transport := &Transport{timeout: time.Second, portName: "/dev/spidev0.0"}
+	require.NoError(t, WithIRQPin(pin)(transport))
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		pin.Assert()
+	}()
+
+	// No SPI connection is configured, so this only succeeds via the IRQ line
+	require.NoError(t, transport.waitReady())
+}
+
+func TestTransport_WaitReady_IRQTimeout(t *testing.T) {
+	t.Parallel()
+
+	transport := &Transport{timeout: 10 * time.Millisecond, portName: "/dev/spidev0.0"}
+	require.NoError(t, WithIRQPin(testutil.NewFakeIRQPin())(transport))
+
+	err := transport.waitReady()
+	require.ErrorIs(t, err, pn532.ErrTransportNotReady)
+}
+
+func TestTransport_HardReset_NoPin(t *testing.T) {
+	t.Parallel()
+
+	transport := &Transport{portName: "/dev/spidev0.0"}
+	require.ErrorIs(t, transport.HardReset(), pn532.ErrDeviceNotSupported)
+}
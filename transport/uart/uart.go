@@ -57,33 +57,133 @@ var (
 type Transport struct {
 	port        serial.Port
 	portName    string
+	baudRate    int
+	readTimeout time.Duration
 	mu          sync.Mutex
 	lastCommand byte // Track last command for special handling
 }
 
+// Option is a functional option for configuring a UART transport
+type Option func(*Transport) error
+
+// WithBaudRate opens the port at the given baud rate instead of the PN532
+// power-on default of 115200. The PN532 must already be running at this rate,
+// e.g. after a previous Device.SetSerialBaudRate call without a reset. Reopen
+// always goes back to 115200.
+func WithBaudRate(baudRate int) Option {
+	return func(t *Transport) error {
+		if !pn532.IsValidSerialBaudRate(baudRate) {
+			return fmt.Errorf("%w: unsupported UART baud rate %d", pn532.ErrInvalidParameter, baudRate)
+		}
+		t.baudRate = baudRate
+		return nil
+	}
+}
+
 // New creates a new UART transport.
-func New(portName string) (*Transport, error) {
-	port, err := serial.Open(portName, &serial.Mode{
-		BaudRate: 115200,
-		DataBits: 8,
-		Parity:   serial.NoParity,
-		StopBits: serial.OneStopBit,
-	})
+func New(portName string, opts ...Option) (*Transport, error) {
+	transport := &Transport{
+		portName: portName,
+		baudRate: pn532.DefaultSerialBaudRate,
+		// 50ms matches the working reference implementation and has been
+		// proven to work with InListPassiveTarget
+		readTimeout: 50 * time.Millisecond,
+	}
+
+	for _, opt := range opts {
+		if err := opt(transport); err != nil {
+			return nil, err
+		}
+	}
+
+	port, err := openPort(portName, transport.baudRate, transport.readTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	transport.port = port
+	return transport, nil
+}
+
+// openPort opens the serial port at the given baud rate and read timeout
+func openPort(portName string, baudRate int, readTimeout time.Duration) (serial.Port, error) {
+	port, err := serial.Open(portName, serialMode(baudRate))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open UART port %s: %w", portName, err)
 	}
 
-	// Set default timeout - 50ms to match working reference implementation
-	// This timeout has been proven to work with InListPassiveTarget
-	if err := port.SetReadTimeout(50 * time.Millisecond); err != nil {
+	if err := port.SetReadTimeout(readTimeout); err != nil {
 		_ = port.Close()
 		return nil, fmt.Errorf("failed to set UART read timeout: %w", err)
 	}
+	return port, nil
+}
 
-	return &Transport{
-		port:     port,
-		portName: portName,
-	}, nil
+// Reopen closes and reopens the serial port, e.g. after the USB serial
+// adapter stopped responding. Reopen is used to recover after a USB or PN532
+// reset, which puts the PN532 back at 115200, so the port is reopened at the
+// default rate rather than any rate negotiated before. The PN532 must be
+// initialized again afterwards, and a higher rate negotiated again with
+// Device.SetSerialBaudRate if needed.
+func (t *Transport) Reopen() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.port != nil {
+		_ = t.port.Close()
+		t.port = nil
+	}
+	t.baudRate = pn532.DefaultSerialBaudRate
+
+	port, err := openPort(t.portName, t.baudRate, t.readTimeout)
+	if err != nil {
+		return err
+	}
+	t.port = port
+	return nil
+}
+
+// serialMode returns the 8N1 serial mode used by the PN532 HSU at the given rate
+func serialMode(baudRate int) *serial.Mode {
+	return &serial.Mode{
+		BaudRate: baudRate,
+		DataBits: 8,
+		Parity:   serial.NoParity,
+		StopBits: serial.OneStopBit,
+	}
+}
+
+// BaudRate returns the current host-side baud rate
+func (t *Transport) BaudRate() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.baudRate
+}
+
+// SetBaudRate switches the host-side port to the given baud rate.
+// This only changes the host side; use Device.SetSerialBaudRate to
+// negotiate the change with the PN532 first.
+func (t *Transport) SetBaudRate(baudRate int) error {
+	if !pn532.IsValidSerialBaudRate(baudRate) {
+		return fmt.Errorf("%w: unsupported UART baud rate %d", pn532.ErrInvalidParameter, baudRate)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.port == nil {
+		return pn532.NewTransportError("SetBaudRate", t.portName, pn532.ErrTransportClosed, pn532.ErrorTypePermanent)
+	}
+
+	if err := t.port.SetMode(serialMode(baudRate)); err != nil {
+		return fmt.Errorf("UART set baud rate failed: %w", err)
+	}
+
+	// Discard anything received at the old rate
+	_ = t.port.ResetInputBuffer()
+
+	t.baudRate = baudRate
+	return nil
 }
 
 // SendCommand sends a command to the PN532 and waits for response.
@@ -91,6 +191,10 @@ func (t *Transport) SendCommand(cmd byte, args []byte) ([]byte, error) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
+	if t.port == nil {
+		return nil, pn532.NewTransportError("SendCommand", t.portName, pn532.ErrTransportClosed, pn532.ErrorTypePermanent)
+	}
+
 	// Track the command for special handling
 	t.lastCommand = cmd
 
@@ -162,6 +266,7 @@ func (t *Transport) SetTimeout(timeout time.Duration) error {
 	if err != nil {
 		return fmt.Errorf("UART set timeout failed: %w", err)
 	}
+	t.readTimeout = timeout
 	return nil
 }
 
@@ -676,5 +781,10 @@ func (*Transport) HasCapability(capability pn532.TransportCapability) bool {
 	}
 }
 
-// Ensure Transport implements pn532.Transport
-var _ pn532.Transport = (*Transport)(nil)
+// Ensure Transport implements pn532.Transport, pn532.BaudRateConfigurer and
+// pn532.Reopener
+var (
+	_ pn532.Transport          = (*Transport)(nil)
+	_ pn532.BaudRateConfigurer = (*Transport)(nil)
+	_ pn532.Reopener           = (*Transport)(nil)
+)
@@ -0,0 +1,181 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package uart
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ZaparooProject/go-pn532"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.bug.st/serial"
+)
+
+// modeRecordingPort is a serial.Port stub that records mode changes
+type modeRecordingPort struct {
+	setModeErr  error
+	modes       []serial.Mode
+	inputResets int
+	closed      bool
+}
+
+func (p *modeRecordingPort) SetMode(mode *serial.Mode) error {
+	if p.setModeErr != nil {
+		return p.setModeErr
+	}
+	p.modes = append(p.modes, *mode)
+	return nil
+}
+
+func (*modeRecordingPort) Read(_ []byte) (int, error)  { return 0, nil }
+func (*modeRecordingPort) Write(p []byte) (int, error) { return len(p), nil }
+func (*modeRecordingPort) Drain() error                { return nil }
+
+func (p *modeRecordingPort) ResetInputBuffer() error {
+	p.inputResets++
+	return nil
+}
+
+func (*modeRecordingPort) ResetOutputBuffer() error { return nil }
+func (*modeRecordingPort) SetDTR(_ bool) error      { return nil }
+func (*modeRecordingPort) SetRTS(_ bool) error      { return nil }
+
+func (*modeRecordingPort) GetModemStatusBits() (*serial.ModemStatusBits, error) {
+	return &serial.ModemStatusBits{}, nil
+}
+
+func (*modeRecordingPort) SetReadTimeout(_ time.Duration) error { return nil }
+func (*modeRecordingPort) Break(_ time.Duration) error          { return nil }
+
+func (p *modeRecordingPort) Close() error {
+	p.closed = true
+	return nil
+}
+
+func TestWithBaudRate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		baudRate int
+		wantErr  bool
+	}{
+		{name: "Default_Rate", baudRate: 115200},
+		{name: "Highest_Rate", baudRate: 1288000},
+		{name: "Lowest_Rate", baudRate: 9600},
+		{name: "Unsupported_Rate", baudRate: 12345, wantErr: true},
+		{name: "Zero_Rate", baudRate: 0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			transport := &Transport{baudRate: pn532.DefaultSerialBaudRate}
+			err := WithBaudRate(tt.baudRate)(transport)
+
+			if tt.wantErr {
+				require.ErrorIs(t, err, pn532.ErrInvalidParameter)
+				assert.Equal(t, pn532.DefaultSerialBaudRate, transport.BaudRate())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.baudRate, transport.BaudRate())
+		})
+	}
+}
+
+func TestNew_InvalidBaudRateDoesNotOpenPort(t *testing.T) {
+	t.Parallel()
+
+	transport, err := New("/dev/does-not-exist", WithBaudRate(1234))
+	require.ErrorIs(t, err, pn532.ErrInvalidParameter)
+	assert.Nil(t, transport)
+}
+
+func TestTransport_SetBaudRate(t *testing.T) {
+	t.Parallel()
+
+	port := &modeRecordingPort{}
+	transport := &Transport{port: port, baudRate: pn532.DefaultSerialBaudRate}
+
+	require.NoError(t, transport.SetBaudRate(921600))
+
+	assert.Equal(t, 921600, transport.BaudRate())
+	require.Len(t, port.modes, 1)
+	assert.Equal(t, 921600, port.modes[0].BaudRate)
+	assert.Equal(t, 8, port.modes[0].DataBits)
+	assert.Equal(t, serial.NoParity, port.modes[0].Parity)
+	assert.Equal(t, serial.OneStopBit, port.modes[0].StopBits)
+	assert.Equal(t, 1, port.inputResets)
+}
+
+func TestTransport_SetBaudRate_Errors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Unsupported_Rate", func(t *testing.T) {
+		t.Parallel()
+		port := &modeRecordingPort{}
+		transport := &Transport{port: port, baudRate: pn532.DefaultSerialBaudRate}
+
+		err := transport.SetBaudRate(100)
+		require.ErrorIs(t, err, pn532.ErrInvalidParameter)
+		assert.Empty(t, port.modes)
+	})
+
+	t.Run("No_Port", func(t *testing.T) {
+		t.Parallel()
+		transport := &Transport{baudRate: pn532.DefaultSerialBaudRate}
+
+		err := transport.SetBaudRate(230400)
+		require.ErrorIs(t, err, pn532.ErrTransportClosed)
+		assert.Equal(t, pn532.DefaultSerialBaudRate, transport.BaudRate())
+	})
+
+	t.Run("SetMode_Fails", func(t *testing.T) {
+		t.Parallel()
+		port := &modeRecordingPort{setModeErr: errors.New("ioctl failed")}
+		transport := &Transport{port: port, baudRate: pn532.DefaultSerialBaudRate}
+
+		err := transport.SetBaudRate(230400)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "ioctl failed")
+		assert.Equal(t, pn532.DefaultSerialBaudRate, transport.BaudRate())
+	})
+}
+
+func TestTransport_Reopen_Fails(t *testing.T) {
+	t.Parallel()
+
+	port := &modeRecordingPort{}
+	transport := &Transport{port: port, portName: "/dev/nonexistent-pn532", baudRate: 921600}
+
+	err := transport.Reopen()
+	require.ErrorContains(t, err, "/dev/nonexistent-pn532")
+	assert.True(t, port.closed, "the old port is closed first")
+	assert.False(t, transport.IsConnected())
+	assert.Equal(t, pn532.DefaultSerialBaudRate, transport.BaudRate(), "a reset PN532 is back at 115200")
+
+	_, err = transport.SendCommand(0x02, nil)
+	require.ErrorIs(t, err, pn532.ErrTransportClosed)
+}
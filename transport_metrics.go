@@ -0,0 +1,377 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package pn532
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Span attribute keys set by InstrumentedTransport
+const (
+	AttrCommand        = "pn532.command"
+	AttrCommandName    = "pn532.command.name"
+	AttrTransport      = "pn532.transport"
+	AttrArgsLength     = "pn532.args.length"
+	AttrResponseLength = "pn532.response.length"
+	AttrErrorType      = "pn532.error.type"
+	AttrErrorKind      = "pn532.error.kind"
+)
+
+// commandNames maps command codes to their names in the PN532 user manual
+var commandNames = map[byte]string{
+	cmdDiagnose:            "Diagnose",
+	cmdGetFirmwareVersion:  "GetFirmwareVersion",
+	cmdGetGeneralStatus:    "GetGeneralStatus",
+	cmdSetSerialBaudRate:   "SetSerialBaudRate",
+	cmdSamConfiguration:    "SAMConfiguration",
+	cmdPowerDown:           "PowerDown",
+	cmdRFConfiguration:     "RFConfiguration",
+	cmdInDataExchange:      "InDataExchange",
+	cmdInCommunicateThru:   "InCommunicateThru",
+	cmdInListPassiveTarget: "InListPassiveTarget",
+	cmdInRelease:           "InRelease",
+	cmdInSelect:            "InSelect",
+	cmdInAutoPoll:          "InAutoPoll",
+}
+
+// CommandName returns the PN532 name of a command code, or its hex value
+// for commands this package does not use
+func CommandName(cmd byte) string {
+	if name, ok := commandNames[cmd]; ok {
+		return name
+	}
+	return fmt.Sprintf("0x%02X", cmd)
+}
+
+// CommandObservation describes a single command exchange seen by an
+// InstrumentedTransport
+type CommandObservation struct {
+	Err       error
+	Transport TransportType
+	// ErrorKind names the sentinel error matched by Err, such as
+	// "nack_received" or "checksum_mismatch"; empty if none matched
+	ErrorKind string
+	Duration  time.Duration
+	// ErrorType is the GetErrorType category of Err; only set when Err is not nil
+	ErrorType ErrorType
+	Command   byte
+}
+
+// MetricsSink receives one observation per command exchange. Implementations
+// must be safe for concurrent use. MetricsCollector is a ready-made sink;
+// adapters for Prometheus or OpenTelemetry metrics can implement it directly.
+type MetricsSink interface {
+	ObserveCommand(obs CommandObservation)
+}
+
+// SpanAttribute is a key/value pair attached to a span. Values are strings,
+// ints or bools so they map directly onto OpenTelemetry attributes.
+type SpanAttribute struct {
+	Value any
+	Key   string
+}
+
+// Span is the subset of an OpenTelemetry span used by InstrumentedTransport
+type Span interface {
+	SetAttributes(attrs ...SpanAttribute)
+	RecordError(err error)
+	End()
+}
+
+// Tracer starts spans. It mirrors the OpenTelemetry trace.Tracer Start
+// method, so adapting an OpenTelemetry tracer takes a few lines without this
+// package depending on the OpenTelemetry SDK.
+type Tracer interface {
+	Start(ctx context.Context, spanName string) (context.Context, Span)
+}
+
+// InstrumentedTransport wraps a Transport and reports every command exchange
+// to a MetricsSink and a Tracer. Either may be nil. Wrap the raw transport
+// before NewTransportWithRetry to observe every retry attempt; wrap the retry
+// layer to observe only the final outcome of each command.
+type InstrumentedTransport struct {
+	transport Transport
+	metrics   MetricsSink
+	tracer    Tracer
+}
+
+// NewInstrumentedTransport creates an instrumentation wrapper
+func NewInstrumentedTransport(transport Transport, metrics MetricsSink, tracer Tracer) *InstrumentedTransport {
+	return &InstrumentedTransport{
+		transport: transport,
+		metrics:   metrics,
+		tracer:    tracer,
+	}
+}
+
+// SendCommand sends a command and reports the exchange
+func (t *InstrumentedTransport) SendCommand(cmd byte, args []byte) ([]byte, error) {
+	return t.instrument(context.Background(), cmd, args, func(context.Context) ([]byte, error) {
+		return t.transport.SendCommand(cmd, args) //nolint:wrapcheck // instrumentation must be transparent
+	})
+}
+
+// SendCommandWithContext sends a command with context support and reports the
+// exchange. The span context is passed on to the underlying transport.
+func (t *InstrumentedTransport) SendCommandWithContext(ctx context.Context, cmd byte, args []byte) ([]byte, error) {
+	return t.instrument(ctx, cmd, args, func(spanCtx context.Context) ([]byte, error) {
+		return t.transport.SendCommandWithContext(spanCtx, cmd, args) //nolint:wrapcheck // must be transparent
+	})
+}
+
+// instrument runs a single exchange inside a span and records its metrics
+func (t *InstrumentedTransport) instrument(
+	ctx context.Context, cmd byte, args []byte, send func(context.Context) ([]byte, error),
+) ([]byte, error) {
+	var span Span
+	if t.tracer != nil {
+		ctx, span = t.tracer.Start(ctx, "pn532."+CommandName(cmd))
+		span.SetAttributes(
+			SpanAttribute{Key: AttrCommand, Value: int(cmd)},
+			SpanAttribute{Key: AttrCommandName, Value: CommandName(cmd)},
+			SpanAttribute{Key: AttrTransport, Value: string(t.transport.Type())},
+			SpanAttribute{Key: AttrArgsLength, Value: len(args)},
+		)
+	}
+
+	start := time.Now()
+	res, err := send(ctx)
+	obs := CommandObservation{
+		Command:   cmd,
+		Transport: t.transport.Type(),
+		Duration:  time.Since(start),
+		Err:       err,
+	}
+	if err != nil {
+		obs.ErrorType = GetErrorType(err)
+		obs.ErrorKind = errorKind(err)
+	}
+
+	if t.metrics != nil {
+		t.metrics.ObserveCommand(obs)
+	}
+	if span != nil {
+		endSpan(span, &obs, len(res))
+	}
+
+	return res, err
+}
+
+// endSpan records the outcome of an exchange on its span and ends it
+func endSpan(span Span, obs *CommandObservation, responseLength int) {
+	defer span.End()
+
+	if obs.Err == nil {
+		span.SetAttributes(SpanAttribute{Key: AttrResponseLength, Value: responseLength})
+		return
+	}
+
+	attrs := []SpanAttribute{{Key: AttrErrorType, Value: obs.ErrorType.String()}}
+	if obs.ErrorKind != "" {
+		attrs = append(attrs, SpanAttribute{Key: AttrErrorKind, Value: obs.ErrorKind})
+	}
+	span.SetAttributes(attrs...)
+	span.RecordError(obs.Err)
+}
+
+// Close closes the underlying transport
+func (t *InstrumentedTransport) Close() error {
+	if err := t.transport.Close(); err != nil {
+		return fmt.Errorf("failed to close underlying transport: %w", err)
+	}
+	return nil
+}
+
+// SetTimeout sets the read timeout for the transport
+func (t *InstrumentedTransport) SetTimeout(timeout time.Duration) error {
+	if err := t.transport.SetTimeout(timeout); err != nil {
+		return fmt.Errorf("failed to set timeout on underlying transport: %w", err)
+	}
+	return nil
+}
+
+// IsConnected returns true if the transport is connected
+func (t *InstrumentedTransport) IsConnected() bool {
+	return t.transport.IsConnected()
+}
+
+// Type returns the transport type
+func (t *InstrumentedTransport) Type() TransportType {
+	return t.transport.Type()
+}
+
+// HasCapability forwards capability checking to the underlying transport
+func (t *InstrumentedTransport) HasCapability(capability TransportCapability) bool {
+	if capChecker, ok := t.transport.(TransportCapabilityChecker); ok {
+		return capChecker.HasCapability(capability)
+	}
+	return false
+}
+
+// Unwrap returns the underlying transport
+func (t *InstrumentedTransport) Unwrap() Transport {
+	return t.transport
+}
+
+// DefaultLatencyBuckets are the histogram upper bounds used by
+// NewMetricsCollector when none are given. They span a fast I2C status
+// read up to a multi-second InAutoPoll.
+var DefaultLatencyBuckets = []time.Duration{
+	time.Millisecond,
+	2 * time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+}
+
+// CommandStats aggregates the observations of one command code
+type CommandStats struct {
+	// ErrorsByType counts failures per GetErrorType category
+	ErrorsByType map[ErrorType]uint64
+	// ErrorsByKind counts failures per sentinel error name, e.g. "no_ack"
+	ErrorsByKind map[string]uint64
+	// Buckets are the histogram upper bounds
+	Buckets []time.Duration
+	// BucketCounts has one count per bucket plus a final overflow count.
+	// Counts are not cumulative.
+	BucketCounts  []uint64
+	TotalDuration time.Duration
+	MaxDuration   time.Duration
+	Count         uint64
+	Errors        uint64
+	Command       byte
+}
+
+// MeanDuration returns the average command latency
+func (s *CommandStats) MeanDuration() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.TotalDuration / time.Duration(s.Count)
+}
+
+// ErrorRate returns the fraction of commands that failed
+func (s *CommandStats) ErrorRate() float64 {
+	if s.Count == 0 {
+		return 0
+	}
+	return float64(s.Errors) / float64(s.Count)
+}
+
+// MetricsCollector is an in-memory MetricsSink keeping per-command counts,
+// latency histograms and error breakdowns
+type MetricsCollector struct {
+	stats   map[byte]*CommandStats
+	buckets []time.Duration
+	mu      sync.Mutex
+}
+
+// NewMetricsCollector creates a collector with the given histogram upper
+// bounds, or DefaultLatencyBuckets if none are given
+func NewMetricsCollector(buckets ...time.Duration) *MetricsCollector {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+	sorted := append([]time.Duration(nil), buckets...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	return &MetricsCollector{
+		stats:   make(map[byte]*CommandStats),
+		buckets: sorted,
+	}
+}
+
+// ObserveCommand implements MetricsSink
+func (c *MetricsCollector) ObserveCommand(obs CommandObservation) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats, ok := c.stats[obs.Command]
+	if !ok {
+		stats = &CommandStats{
+			Command:      obs.Command,
+			Buckets:      c.buckets,
+			BucketCounts: make([]uint64, len(c.buckets)+1),
+			ErrorsByType: make(map[ErrorType]uint64),
+			ErrorsByKind: make(map[string]uint64),
+		}
+		c.stats[obs.Command] = stats
+	}
+
+	stats.Count++
+	stats.TotalDuration += obs.Duration
+	if obs.Duration > stats.MaxDuration {
+		stats.MaxDuration = obs.Duration
+	}
+	stats.BucketCounts[sort.Search(len(c.buckets), func(i int) bool {
+		return obs.Duration <= c.buckets[i]
+	})]++
+
+	if obs.Err != nil {
+		stats.Errors++
+		stats.ErrorsByType[obs.ErrorType]++
+		if obs.ErrorKind != "" {
+			stats.ErrorsByKind[obs.ErrorKind]++
+		}
+	}
+}
+
+// Snapshot returns a copy of the statistics of every observed command,
+// ordered by command code
+func (c *MetricsCollector) Snapshot() []CommandStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	snapshot := make([]CommandStats, 0, len(c.stats))
+	for _, stats := range c.stats {
+		copied := *stats
+		copied.BucketCounts = append([]uint64(nil), stats.BucketCounts...)
+		copied.ErrorsByType = make(map[ErrorType]uint64, len(stats.ErrorsByType))
+		for k, v := range stats.ErrorsByType {
+			copied.ErrorsByType[k] = v
+		}
+		copied.ErrorsByKind = make(map[string]uint64, len(stats.ErrorsByKind))
+		for k, v := range stats.ErrorsByKind {
+			copied.ErrorsByKind[k] = v
+		}
+		snapshot = append(snapshot, copied)
+	}
+	sort.Slice(snapshot, func(i, j int) bool { return snapshot[i].Command < snapshot[j].Command })
+
+	return snapshot
+}
+
+// Reset discards all collected statistics
+func (c *MetricsCollector) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats = make(map[byte]*CommandStats)
+}
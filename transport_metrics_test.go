@@ -0,0 +1,222 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package pn532
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	testutil "github.com/ZaparooProject/go-pn532/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type spanContextKey struct{}
+
+// recordedSpan captures what InstrumentedTransport reports on a span
+type recordedSpan struct {
+	attrs map[string]any
+	name  string
+	errs  []error
+	mu    sync.Mutex
+	ended bool
+}
+
+func (s *recordedSpan) SetAttributes(attrs ...SpanAttribute) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, attr := range attrs {
+		s.attrs[attr.Key] = attr.Value
+	}
+}
+
+func (s *recordedSpan) RecordError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errs = append(s.errs, err)
+}
+
+func (s *recordedSpan) End() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ended = true
+}
+
+// recordingTracer keeps every span it starts
+type recordingTracer struct {
+	spans []*recordedSpan
+	mu    sync.Mutex
+}
+
+func (t *recordingTracer) Start(ctx context.Context, spanName string) (context.Context, Span) {
+	span := &recordedSpan{
+		name:  spanName,
+		attrs: make(map[string]any),
+	}
+	t.mu.Lock()
+	t.spans = append(t.spans, span)
+	t.mu.Unlock()
+	return context.WithValue(ctx, spanContextKey{}, span), span
+}
+
+// contextCheckingTransport records whether commands carry a span context
+type contextCheckingTransport struct {
+	*MockTransport
+	sawSpan bool
+}
+
+func (t *contextCheckingTransport) SendCommandWithContext(ctx context.Context, cmd byte, args []byte) ([]byte, error) {
+	t.sawSpan = ctx.Value(spanContextKey{}) != nil
+	return t.MockTransport.SendCommandWithContext(ctx, cmd, args)
+}
+
+func TestCommandName(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "InDataExchange", CommandName(cmdInDataExchange))
+	assert.Equal(t, "SAMConfiguration", CommandName(cmdSamConfiguration))
+	assert.Equal(t, "0x7F", CommandName(0x7F))
+}
+
+func TestInstrumentedTransport_Metrics(t *testing.T) {
+	t.Parallel()
+
+	mock := NewMockTransport()
+	mock.SetResponse(testutil.CmdGetFirmwareVersion, testutil.BuildFirmwareVersionResponse())
+	mock.SetError(testutil.CmdInDataExchange, NewNACKReceivedError("SendCommand", "mock"))
+
+	collector := NewMetricsCollector()
+	transport := NewInstrumentedTransport(mock, collector, nil)
+
+	for i := 0; i < 3; i++ {
+		_, err := transport.SendCommand(testutil.CmdGetFirmwareVersion, nil)
+		require.NoError(t, err)
+	}
+	_, err := transport.SendCommand(testutil.CmdInDataExchange, []byte{0x01})
+	require.ErrorIs(t, err, ErrNACKReceived)
+
+	stats := collector.Snapshot()
+	require.Len(t, stats, 2)
+
+	assert.Equal(t, byte(testutil.CmdGetFirmwareVersion), stats[0].Command)
+	assert.Equal(t, uint64(3), stats[0].Count)
+	assert.Zero(t, stats[0].Errors)
+	assert.Len(t, stats[0].BucketCounts, len(DefaultLatencyBuckets)+1)
+	var histogramTotal uint64
+	for _, count := range stats[0].BucketCounts {
+		histogramTotal += count
+	}
+	assert.Equal(t, uint64(3), histogramTotal)
+
+	assert.Equal(t, byte(testutil.CmdInDataExchange), stats[1].Command)
+	assert.Equal(t, uint64(1), stats[1].Errors)
+	assert.InDelta(t, 1.0, stats[1].ErrorRate(), 0.001)
+	assert.Equal(t, uint64(1), stats[1].ErrorsByType[ErrorTypeTransient])
+	assert.Equal(t, uint64(1), stats[1].ErrorsByKind["nack_received"])
+
+	collector.Reset()
+	assert.Empty(t, collector.Snapshot())
+}
+
+func TestMetricsCollector_Histogram(t *testing.T) {
+	t.Parallel()
+
+	collector := NewMetricsCollector(10*time.Millisecond, time.Millisecond)
+	for _, d := range []time.Duration{500 * time.Microsecond, time.Millisecond, 5 * time.Millisecond, time.Second} {
+		collector.ObserveCommand(CommandObservation{Command: cmdInAutoPoll, Duration: d})
+	}
+
+	stats := collector.Snapshot()
+	require.Len(t, stats, 1)
+	assert.Equal(t, []time.Duration{time.Millisecond, 10 * time.Millisecond}, stats[0].Buckets)
+	assert.Equal(t, []uint64{2, 1, 1}, stats[0].BucketCounts)
+	assert.Equal(t, time.Second, stats[0].MaxDuration)
+	assert.Equal(t, (1006500*time.Microsecond)/4, stats[0].MeanDuration())
+}
+
+func TestInstrumentedTransport_Tracing(t *testing.T) {
+	t.Parallel()
+
+	mock := &contextCheckingTransport{MockTransport: NewMockTransport()}
+	mock.SetResponse(testutil.CmdGetFirmwareVersion, testutil.BuildFirmwareVersionResponse())
+	mock.SetError(testutil.CmdInListPassiveTarget, NewTimeoutError("SendCommand", "mock"))
+
+	tracer := &recordingTracer{}
+	transport := NewInstrumentedTransport(mock, nil, tracer)
+
+	_, err := transport.SendCommandWithContext(context.Background(), testutil.CmdGetFirmwareVersion, nil)
+	require.NoError(t, err)
+	assert.True(t, mock.sawSpan, "span context passed to the wrapped transport")
+
+	_, err = transport.SendCommand(testutil.CmdInListPassiveTarget, []byte{0x01, 0x00})
+	require.Error(t, err)
+
+	require.Len(t, tracer.spans, 2)
+	ok := tracer.spans[0]
+	assert.Equal(t, "pn532.GetFirmwareVersion", ok.name)
+	assert.True(t, ok.ended)
+	assert.Equal(t, "GetFirmwareVersion", ok.attrs[AttrCommandName])
+	assert.Equal(t, "mock", ok.attrs[AttrTransport])
+	assert.Equal(t, len(testutil.BuildFirmwareVersionResponse()), ok.attrs[AttrResponseLength])
+	assert.Empty(t, ok.errs)
+
+	failed := tracer.spans[1]
+	assert.Equal(t, "pn532.InListPassiveTarget", failed.name)
+	assert.True(t, failed.ended)
+	assert.Equal(t, 2, failed.attrs[AttrArgsLength])
+	assert.Equal(t, "timeout", failed.attrs[AttrErrorType])
+	assert.Equal(t, "transport_timeout", failed.attrs[AttrErrorKind])
+	require.Len(t, failed.errs, 1)
+}
+
+func TestWithInstrumentation(t *testing.T) {
+	t.Parallel()
+
+	mock := NewMockTransport()
+	mock.SetResponse(testutil.CmdGetFirmwareVersion, testutil.BuildFirmwareVersionResponse())
+	mock.SetResponse(testutil.CmdSAMConfiguration, testutil.BuildSAMConfigurationResponse())
+
+	collector := NewMetricsCollector()
+	retry := NewTransportWithRetry(mock, nil)
+	device, err := New(retry, WithInstrumentation(collector, nil), WithMaxRetries(7))
+	require.NoError(t, err)
+	require.NoError(t, device.InitContext(context.Background()))
+
+	assert.Equal(t, 7, retry.config.MaxAttempts, "retry options see through instrumentation")
+
+	counts := make(map[byte]uint64)
+	for _, stats := range collector.Snapshot() {
+		counts[stats.Command] = stats.Count
+	}
+	assert.Equal(t, uint64(2), counts[testutil.CmdGetFirmwareVersion])
+	assert.Equal(t, uint64(1), counts[testutil.CmdSAMConfiguration])
+}
+
+func TestErrorType_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "transient", ErrorTypeTransient.String())
+	assert.Equal(t, "permanent", ErrorTypePermanent.String())
+	assert.Equal(t, "timeout", ErrorTypeTimeout.String())
+	assert.Equal(t, "ErrorType(9)", ErrorType(9).String())
+}
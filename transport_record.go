@@ -0,0 +1,493 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package pn532
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Record and replay errors
+var (
+	ErrReplayExhausted = errors.New("replay recording exhausted")
+	ErrReplayMismatch  = errors.New("command does not match recording")
+)
+
+// maxRecordLineSize bounds a single JSON line when reading recordings
+const maxRecordLineSize = 1 << 20
+
+// recordableErrors lists sentinel errors preserved across record and replay,
+// in the order they are matched with errors.Is
+var recordableErrors = []struct {
+	err  error
+	name string
+}{
+	{name: "context_canceled", err: context.Canceled},
+	{name: "context_deadline_exceeded", err: context.DeadlineExceeded},
+	{name: "transport_timeout", err: ErrTransportTimeout},
+	{name: "transport_write", err: ErrTransportWrite},
+	{name: "transport_read", err: ErrTransportRead},
+	{name: "transport_closed", err: ErrTransportClosed},
+	{name: "transport_not_ready", err: ErrTransportNotReady},
+	{name: "communication_failed", err: ErrCommunicationFailed},
+	{name: "no_ack", err: ErrNoACK},
+	{name: "nack_received", err: ErrNACKReceived},
+	{name: "frame_corrupted", err: ErrFrameCorrupted},
+	{name: "checksum_mismatch", err: ErrChecksumMismatch},
+	{name: "device_not_found", err: ErrDeviceNotFound},
+	{name: "command_failed", err: ErrCommandFailed},
+	{name: "invalid_response", err: ErrInvalidResponse},
+	{name: "data_too_large", err: ErrDataTooLarge},
+}
+
+// TransportRecord is a single recorded SendCommand exchange.
+// Recordings are stored as JSON lines, one record per command.
+type TransportRecord struct {
+	Time         time.Time             `json:"time"`
+	Err          *RecordedError        `json:"err,omitempty"`
+	Transport    TransportType         `json:"transport"`
+	Args         string                `json:"args"`
+	Response     string                `json:"response,omitempty"`
+	Capabilities []TransportCapability `json:"capabilities,omitempty"`
+	DurationNS   int64                 `json:"duration_ns"`
+	Seq          int                   `json:"seq"`
+	Command      byte                  `json:"cmd"`
+}
+
+// RecordedError captures enough of an error to reproduce its message and
+// retry classification during replay
+type RecordedError struct {
+	Message   string    `json:"message"`
+	Kind      string    `json:"kind,omitempty"`
+	Op        string    `json:"op,omitempty"`
+	Port      string    `json:"port,omitempty"`
+	Type      ErrorType `json:"type"`
+	Retryable bool      `json:"retryable"`
+	Transport bool      `json:"transport_error"`
+}
+
+// Duration returns how long the recorded command took
+func (r *TransportRecord) Duration() time.Duration {
+	return time.Duration(r.DurationNS)
+}
+
+// newRecordedError converts an error into its recorded form
+func newRecordedError(err error) *RecordedError {
+	recorded := &RecordedError{
+		Message:   err.Error(),
+		Type:      GetErrorType(err),
+		Retryable: IsRetryable(err),
+	}
+
+	var te *TransportError
+	if errors.As(err, &te) {
+		recorded.Transport = true
+		recorded.Op = te.Op
+		recorded.Port = te.Port
+		if te.Err != nil {
+			recorded.Message = te.Err.Error()
+		}
+	}
+
+	recorded.Kind = errorKind(err)
+
+	return recorded
+}
+
+// errorKind returns the name of the first known sentinel error matched by
+// err, or an empty string
+func errorKind(err error) string {
+	for _, known := range recordableErrors {
+		if errors.Is(err, known.err) {
+			return known.name
+		}
+	}
+	return ""
+}
+
+// replayedError reproduces a recorded error message while still matching
+// the original sentinel with errors.Is
+type replayedError struct {
+	cause   error
+	message string
+}
+
+func (e *replayedError) Error() string {
+	return e.message
+}
+
+func (e *replayedError) Unwrap() error {
+	return e.cause
+}
+
+// toError rebuilds an error equivalent to the recorded one
+func (r *RecordedError) toError() error {
+	var cause error
+	for _, known := range recordableErrors {
+		if known.name == r.Kind {
+			cause = known.err
+			break
+		}
+	}
+
+	var err error = &replayedError{message: r.Message, cause: cause}
+	if !r.Transport {
+		return err
+	}
+
+	return &TransportError{
+		Op:        r.Op,
+		Port:      r.Port,
+		Err:       err,
+		Type:      r.Type,
+		Retryable: r.Retryable,
+	}
+}
+
+// RecordingTransport wraps a Transport and writes every command exchange to a
+// JSON-lines stream. It can wrap or be wrapped by TransportWithRetry: wrapping
+// the raw transport records every attempt, wrapping the retry layer records
+// only the final outcome of each command.
+type RecordingTransport struct {
+	transport Transport
+	encoder   *json.Encoder
+	writeErr  error
+	seq       int
+	mu        sync.Mutex
+}
+
+// NewRecordingTransport creates a recording wrapper writing to w
+func NewRecordingTransport(transport Transport, w io.Writer) *RecordingTransport {
+	return &RecordingTransport{
+		transport: transport,
+		encoder:   json.NewEncoder(w),
+	}
+}
+
+// SendCommand sends a command and records the exchange
+func (t *RecordingTransport) SendCommand(cmd byte, args []byte) ([]byte, error) {
+	start := time.Now()
+	res, err := t.transport.SendCommand(cmd, args)
+	t.record(start, cmd, args, res, err)
+	return res, err //nolint:wrapcheck // recorder must be transparent
+}
+
+// SendCommandWithContext sends a command with context support and records the exchange
+func (t *RecordingTransport) SendCommandWithContext(ctx context.Context, cmd byte, args []byte) ([]byte, error) {
+	start := time.Now()
+	res, err := t.transport.SendCommandWithContext(ctx, cmd, args)
+	t.record(start, cmd, args, res, err)
+	return res, err //nolint:wrapcheck // recorder must be transparent
+}
+
+// record writes a single exchange, remembering the first write failure
+func (t *RecordingTransport) record(start time.Time, cmd byte, args, res []byte, err error) {
+	rec := TransportRecord{
+		Time:       start,
+		Transport:  t.transport.Type(),
+		Command:    cmd,
+		Args:       hex.EncodeToString(args),
+		Response:   hex.EncodeToString(res),
+		DurationNS: int64(time.Since(start)),
+	}
+	if err != nil {
+		rec.Err = newRecordedError(err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	rec.Seq = t.seq
+	if t.seq == 0 {
+		rec.Capabilities = t.capabilities()
+	}
+	t.seq++
+
+	if encErr := t.encoder.Encode(&rec); encErr != nil && t.writeErr == nil {
+		t.writeErr = encErr
+		debugf("Failed to write transport record: %v", encErr)
+	}
+}
+
+// capabilities lists the known capabilities of the wrapped transport
+func (t *RecordingTransport) capabilities() []TransportCapability {
+	checker, ok := t.transport.(TransportCapabilityChecker)
+	if !ok {
+		return nil
+	}
+
+	var caps []TransportCapability
+	for _, capability := range []TransportCapability{CapabilityRequiresInSelect, CapabilityAutoPollNative} {
+		if checker.HasCapability(capability) {
+			caps = append(caps, capability)
+		}
+	}
+	return caps
+}
+
+// Err returns the first error encountered while writing records
+func (t *RecordingTransport) Err() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.writeErr
+}
+
+// Close closes the underlying transport. The record writer is owned by the caller.
+func (t *RecordingTransport) Close() error {
+	if err := t.transport.Close(); err != nil {
+		return fmt.Errorf("failed to close underlying transport: %w", err)
+	}
+	return nil
+}
+
+// SetTimeout sets the read timeout for the transport
+func (t *RecordingTransport) SetTimeout(timeout time.Duration) error {
+	if err := t.transport.SetTimeout(timeout); err != nil {
+		return fmt.Errorf("failed to set timeout on underlying transport: %w", err)
+	}
+	return nil
+}
+
+// Unwrap returns the underlying transport
+func (t *RecordingTransport) Unwrap() Transport {
+	return t.transport
+}
+
+// IsConnected returns true if the transport is connected
+func (t *RecordingTransport) IsConnected() bool {
+	return t.transport.IsConnected()
+}
+
+// Type returns the transport type
+func (t *RecordingTransport) Type() TransportType {
+	return t.transport.Type()
+}
+
+// HasCapability forwards capability checking to the underlying transport
+func (t *RecordingTransport) HasCapability(capability TransportCapability) bool {
+	if capChecker, ok := t.transport.(TransportCapabilityChecker); ok {
+		return capChecker.HasCapability(capability)
+	}
+	return false
+}
+
+// ReadTransportRecords parses a JSON-lines recording
+func ReadTransportRecords(r io.Reader) ([]TransportRecord, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), maxRecordLineSize)
+
+	var records []TransportRecord
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var rec TransportRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("invalid transport record on line %d: %w", line, err)
+		}
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read transport records: %w", err)
+	}
+
+	return records, nil
+}
+
+// ReplayTransport serves recorded exchanges back in order, so captured field
+// traces can drive a Device deterministically in unit tests. By default each
+// command must match the recorded command byte and arguments.
+type ReplayTransport struct {
+	transportType TransportType
+	records       []TransportRecord
+	capabilities  []TransportCapability
+	next          int
+	mu            sync.Mutex
+	connected     bool
+	ignoreArgs    bool
+	timing        bool
+}
+
+// NewReplayTransport creates a replay transport from parsed records
+func NewReplayTransport(records []TransportRecord) *ReplayTransport {
+	replay := &ReplayTransport{
+		records:       records,
+		transportType: TransportMock,
+		connected:     true,
+	}
+
+	if len(records) > 0 && records[0].Transport != "" {
+		replay.transportType = records[0].Transport
+	}
+	for i := range records {
+		if len(records[i].Capabilities) > 0 {
+			replay.capabilities = records[i].Capabilities
+			break
+		}
+	}
+
+	return replay
+}
+
+// LoadReplayTransport creates a replay transport from a JSON-lines recording file
+func LoadReplayTransport(path string) (*ReplayTransport, error) {
+	file, err := os.Open(path) //nolint:gosec // path is supplied by the caller
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	records, err := ReadTransportRecords(file)
+	if err != nil {
+		return nil, err
+	}
+	return NewReplayTransport(records), nil
+}
+
+// SetIgnoreArgs makes replay match on the command byte only
+func (t *ReplayTransport) SetIgnoreArgs(ignore bool) {
+	t.mu.Lock()
+	t.ignoreArgs = ignore
+	t.mu.Unlock()
+}
+
+// SetTimingEnabled makes replay wait for each recorded command duration
+func (t *ReplayTransport) SetTimingEnabled(enabled bool) {
+	t.mu.Lock()
+	t.timing = enabled
+	t.mu.Unlock()
+}
+
+// Remaining returns the number of records not yet replayed
+func (t *ReplayTransport) Remaining() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.records) - t.next
+}
+
+// SendCommand replays the next recorded exchange
+func (t *ReplayTransport) SendCommand(cmd byte, args []byte) ([]byte, error) {
+	return t.SendCommandWithContext(context.Background(), cmd, args)
+}
+
+// SendCommandWithContext replays the next recorded exchange with context support
+func (t *ReplayTransport) SendCommandWithContext(ctx context.Context, cmd byte, args []byte) ([]byte, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	rec, timing, err := t.nextRecord(cmd, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if timing && rec.DurationNS > 0 {
+		select {
+		case <-time.After(rec.Duration()):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	if rec.Err != nil {
+		return nil, rec.Err.toError()
+	}
+
+	res, err := hex.DecodeString(rec.Response)
+	if err != nil {
+		return nil, fmt.Errorf("%w: record %d has invalid response hex: %w", ErrInvalidFormat, rec.Seq, err)
+	}
+	return res, nil
+}
+
+// nextRecord consumes the next record if it matches the command
+func (t *ReplayTransport) nextRecord(cmd byte, args []byte) (rec *TransportRecord, timing bool, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.connected {
+		return nil, false, NewTransportError("replay", "", ErrTransportClosed, ErrorTypePermanent)
+	}
+	if t.next >= len(t.records) {
+		return nil, false, fmt.Errorf("%w: command 0x%02X after %d records", ErrReplayExhausted, cmd, len(t.records))
+	}
+
+	rec = &t.records[t.next]
+	if rec.Command != cmd {
+		return nil, false, fmt.Errorf("%w: record %d expected command 0x%02X, got 0x%02X",
+			ErrReplayMismatch, rec.Seq, rec.Command, cmd)
+	}
+	if !t.ignoreArgs && rec.Args != hex.EncodeToString(args) {
+		return nil, false, fmt.Errorf("%w: record %d expected args %s, got %x",
+			ErrReplayMismatch, rec.Seq, rec.Args, args)
+	}
+
+	t.next++
+	return rec, t.timing, nil
+}
+
+// Close implements Transport interface
+func (t *ReplayTransport) Close() error {
+	t.mu.Lock()
+	t.connected = false
+	t.mu.Unlock()
+	return nil
+}
+
+// SetTimeout implements Transport interface
+func (*ReplayTransport) SetTimeout(_ time.Duration) error {
+	return nil
+}
+
+// IsConnected implements Transport interface
+func (t *ReplayTransport) IsConnected() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.connected
+}
+
+// Type returns the transport type of the recording
+func (t *ReplayTransport) Type() TransportType {
+	return t.transportType
+}
+
+// HasCapability reports the capabilities captured in the recording
+func (t *ReplayTransport) HasCapability(capability TransportCapability) bool {
+	for _, c := range t.capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
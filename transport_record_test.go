@@ -0,0 +1,265 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package pn532
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	testutil "github.com/ZaparooProject/go-pn532/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fastRetryConfig keeps retry tests quick
+func fastRetryConfig() *RetryConfig {
+	return &RetryConfig{
+		MaxAttempts:       3,
+		InitialBackoff:    1 * time.Microsecond,
+		MaxBackoff:        10 * time.Microsecond,
+		BackoffMultiplier: 2.0,
+		RetryTimeout:      time.Second,
+	}
+}
+
+// capabilityMockTransport is a MockTransport that supports native InAutoPoll
+type capabilityMockTransport struct {
+	*MockTransport
+}
+
+func (*capabilityMockTransport) HasCapability(capability TransportCapability) bool {
+	return capability == CapabilityAutoPollNative
+}
+
+// recordInit runs device initialization against a mock and returns the recording
+func recordInit(t *testing.T) []byte {
+	t.Helper()
+
+	mock := NewMockTransport()
+	mock.SetResponse(testutil.CmdGetFirmwareVersion, testutil.BuildFirmwareVersionResponse())
+	mock.SetResponse(testutil.CmdSAMConfiguration, testutil.BuildSAMConfigurationResponse())
+
+	var buf bytes.Buffer
+	recorder := NewRecordingTransport(mock, &buf)
+	device, err := New(recorder)
+	require.NoError(t, err)
+	require.NoError(t, device.InitContext(context.Background()))
+	require.NoError(t, recorder.Err())
+
+	return buf.Bytes()
+}
+
+func TestRecordingTransport_WritesJSONLines(t *testing.T) {
+	t.Parallel()
+
+	data := recordInit(t)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 3) // firmware check, SAM configuration, firmware version
+
+	records, err := ReadTransportRecords(bytes.NewReader(data))
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+
+	assert.Equal(t, byte(cmdGetFirmwareVersion), records[0].Command)
+	assert.Equal(t, byte(cmdSamConfiguration), records[1].Command)
+	assert.Equal(t, "010000", records[1].Args)
+	assert.Equal(t, "15", records[1].Response)
+	for i, rec := range records {
+		assert.Equal(t, i, rec.Seq)
+		assert.Equal(t, TransportMock, rec.Transport)
+		assert.Nil(t, rec.Err)
+		assert.False(t, rec.Time.IsZero())
+	}
+}
+
+func TestReplayTransport_ReproducesDeviceBehaviour(t *testing.T) {
+	t.Parallel()
+
+	records, err := ReadTransportRecords(bytes.NewReader(recordInit(t)))
+	require.NoError(t, err)
+
+	replay := NewReplayTransport(records)
+	device, err := New(replay)
+	require.NoError(t, err)
+
+	require.NoError(t, device.InitContext(context.Background()))
+	assert.Equal(t, 0, replay.Remaining())
+	assert.Equal(t, TransportMock, replay.Type())
+}
+
+func TestReplayTransport_PreservesErrorClassification(t *testing.T) {
+	t.Parallel()
+
+	mock := NewMockTransport()
+	mock.SetError(cmdGetFirmwareVersion, NewNoACKError("waitAck", "/dev/ttyUSB0"))
+
+	var buf bytes.Buffer
+	recorder := NewRecordingTransport(mock, &buf)
+	_, origErr := recorder.SendCommand(cmdGetFirmwareVersion, nil)
+	require.Error(t, origErr)
+
+	records, err := ReadTransportRecords(&buf)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.NotNil(t, records[0].Err)
+	assert.Equal(t, "no_ack", records[0].Err.Kind)
+
+	replay := NewReplayTransport(records)
+	_, replayErr := replay.SendCommand(cmdGetFirmwareVersion, nil)
+	require.Error(t, replayErr)
+
+	assert.Equal(t, origErr.Error(), replayErr.Error())
+	require.ErrorIs(t, replayErr, ErrNoACK)
+	assert.Equal(t, IsRetryable(origErr), IsRetryable(replayErr))
+	assert.Equal(t, GetErrorType(origErr), GetErrorType(replayErr))
+}
+
+func TestReplayTransport_ComposesWithRetry(t *testing.T) {
+	t.Parallel()
+
+	// A field trace where the first attempt lost its ACK and the retry succeeded
+	records := []TransportRecord{
+		{Seq: 0, Command: cmdGetFirmwareVersion, Err: newRecordedError(NewNoACKError("waitAck", "uart"))},
+		{Seq: 1, Command: cmdGetFirmwareVersion, Response: "0332010607"},
+	}
+
+	device, err := New(NewTransportWithRetry(NewReplayTransport(records), fastRetryConfig()))
+	require.NoError(t, err)
+
+	fw, err := device.GetFirmwareVersion()
+	require.NoError(t, err)
+	assert.Equal(t, "1.6", fw.Version)
+}
+
+func TestRecordingTransport_InsideRetryRecordsEveryAttempt(t *testing.T) {
+	t.Parallel()
+
+	mock := NewMockTransport()
+	mock.SetError(cmdGetFirmwareVersion, NewTimeoutError("receiveFrame", "mock"))
+
+	var buf bytes.Buffer
+	transport := NewTransportWithRetry(NewRecordingTransport(mock, &buf), fastRetryConfig())
+	_, err := transport.SendCommandWithContext(context.Background(), cmdGetFirmwareVersion, nil)
+	require.Error(t, err)
+
+	records, err := ReadTransportRecords(&buf)
+	require.NoError(t, err)
+	assert.Len(t, records, 3)
+}
+
+func TestReplayTransport_Mismatch(t *testing.T) {
+	t.Parallel()
+
+	records := []TransportRecord{{Command: cmdSamConfiguration, Args: "010000", Response: "15"}}
+
+	t.Run("Command", func(t *testing.T) {
+		t.Parallel()
+		replay := NewReplayTransport(records)
+		_, err := replay.SendCommand(cmdGetFirmwareVersion, nil)
+		require.ErrorIs(t, err, ErrReplayMismatch)
+		assert.Equal(t, 1, replay.Remaining())
+	})
+
+	t.Run("Args", func(t *testing.T) {
+		t.Parallel()
+		replay := NewReplayTransport(records)
+		_, err := replay.SendCommand(cmdSamConfiguration, []byte{0x02, 0x00, 0x00})
+		require.ErrorIs(t, err, ErrReplayMismatch)
+	})
+
+	t.Run("Args_Ignored", func(t *testing.T) {
+		t.Parallel()
+		replay := NewReplayTransport(records)
+		replay.SetIgnoreArgs(true)
+		res, err := replay.SendCommand(cmdSamConfiguration, []byte{0x02, 0x00, 0x00})
+		require.NoError(t, err)
+		assert.Equal(t, []byte{0x15}, res)
+	})
+}
+
+func TestReplayTransport_Exhausted(t *testing.T) {
+	t.Parallel()
+
+	replay := NewReplayTransport(nil)
+	_, err := replay.SendCommand(cmdGetFirmwareVersion, nil)
+	require.ErrorIs(t, err, ErrReplayExhausted)
+}
+
+func TestReplayTransport_Timing(t *testing.T) {
+	t.Parallel()
+
+	records := []TransportRecord{
+		{Command: cmdGetFirmwareVersion, Response: "0332010607", DurationNS: int64(50 * time.Millisecond)},
+	}
+
+	replay := NewReplayTransport(records)
+	replay.SetTimingEnabled(true)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := replay.SendCommandWithContext(ctx, cmdGetFirmwareVersion, nil)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestReplayTransport_CapabilitiesFromRecording(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	inner := NewTransportWithRetry(&capabilityMockTransport{MockTransport: NewMockTransport()}, nil)
+	recorder := NewRecordingTransport(inner, &buf)
+	_, err := recorder.SendCommand(cmdGetFirmwareVersion, nil)
+	require.NoError(t, err)
+
+	records, err := ReadTransportRecords(&buf)
+	require.NoError(t, err)
+
+	replay := NewReplayTransport(records)
+	assert.True(t, replay.HasCapability(CapabilityAutoPollNative))
+	assert.False(t, replay.HasCapability(CapabilityRequiresInSelect))
+}
+
+func TestLoadReplayTransport(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "trace.jsonl")
+	require.NoError(t, os.WriteFile(path, recordInit(t), 0o600))
+
+	replay, err := LoadReplayTransport(path)
+	require.NoError(t, err)
+	assert.Equal(t, 3, replay.Remaining())
+
+	_, err = LoadReplayTransport(filepath.Join(t.TempDir(), "missing.jsonl"))
+	require.Error(t, err)
+}
+
+func TestReadTransportRecords_InvalidLine(t *testing.T) {
+	t.Parallel()
+
+	_, err := ReadTransportRecords(strings.NewReader("{\"cmd\":2}\n\nnot json\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 3")
+}
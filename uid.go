@@ -0,0 +1,127 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package pn532
+
+import "fmt"
+
+// UID lengths of ISO14443A anti-collision cascade levels
+const (
+	uidSingleSize = 4
+	uidDoubleSize = 7
+	uidTripleSize = 10
+)
+
+// randomUIDPrefix marks a 4-byte UID as random (RID), as sent by most
+// ISO14443-4 cards and phones emulating a card. Such a UID changes on every
+// activation.
+const randomUIDPrefix = 0x08
+
+// manufacturers maps ISO/IEC 7816-6 IC manufacturer codes to names
+var manufacturers = map[byte]string{
+	0x01: "Motorola",
+	0x02: "STMicroelectronics",
+	0x03: "Hitachi",
+	0x04: "NXP Semiconductors",
+	0x05: "Infineon Technologies",
+	0x06: "Cylink",
+	0x07: "Texas Instruments",
+	0x08: "Fujitsu",
+	0x09: "Matsushita Electronics",
+	0x0A: "NEC",
+	0x0B: "Oki Electric",
+	0x0C: "Toshiba",
+	0x0D: "Mitsubishi Electric",
+	0x0E: "Samsung Electronics",
+	0x0F: "Hynix",
+	0x10: "LG Semiconductors",
+	0x16: "EM Microelectronic-Marin",
+	0x1F: "Melexis",
+	0x28: "Atmel",
+	0x2B: "Maxim Integrated",
+	0x33: "AMIC",
+	0x39: "Silicon Craft Technology",
+	0x49: "Fudan Microelectronics",
+}
+
+// UIDInfo describes a tag UID as used in ISO14443A anti-collision
+type UIDInfo struct {
+	// Manufacturer is the name for ManufacturerCode, empty if unknown
+	Manufacturer string
+	// Length is the UID length in bytes
+	Length int
+	// CascadeLevel is the number of anti-collision cascade levels needed to
+	// select the tag: 1 for 4-byte, 2 for 7-byte and 3 for 10-byte UIDs.
+	// It is 0 for UIDs of other lengths, e.g. FeliCa IDm.
+	CascadeLevel int
+	// ManufacturerCode is the first byte of a 7 or 10-byte UID, which is the
+	// ISO/IEC 7816-6 IC manufacturer code. 4-byte UIDs have none.
+	ManufacturerCode byte
+	// Random is set for 4-byte UIDs starting with 0x08, which the tag picks
+	// at random on every activation
+	Random bool
+}
+
+// ParseUID returns the UID metadata of a tag UID
+func ParseUID(uid []byte) UIDInfo {
+	info := UIDInfo{Length: len(uid)}
+	switch len(uid) {
+	case uidSingleSize:
+		info.CascadeLevel = 1
+		info.Random = uid[0] == randomUIDPrefix
+		return info
+	case uidDoubleSize:
+		info.CascadeLevel = 2
+	case uidTripleSize:
+		info.CascadeLevel = 3
+	default:
+		return info
+	}
+	info.ManufacturerCode = uid[0]
+	info.Manufacturer = manufacturers[uid[0]]
+	return info
+}
+
+// String returns a short description of the UID, e.g. "7 bytes, cascade
+// level 2, NXP Semiconductors"
+func (u UIDInfo) String() string {
+	s := fmt.Sprintf("%d bytes", u.Length)
+	if u.CascadeLevel > 0 {
+		s += fmt.Sprintf(", cascade level %d", u.CascadeLevel)
+	}
+	switch {
+	case u.Random:
+		s += ", random"
+	case u.Manufacturer != "":
+		s += ", " + u.Manufacturer
+	case u.CascadeLevel > 1:
+		s += fmt.Sprintf(", manufacturer 0x%02X", u.ManufacturerCode)
+	}
+	return s
+}
+
+// UIDInfo returns the metadata of the tag's UID
+func (t *DetectedTag) UIDInfo() UIDInfo {
+	// FeliCa IDm isn't an ISO14443A UID
+	if t.Type == TagTypeFeliCa {
+		return UIDInfo{Length: len(t.UIDBytes)}
+	}
+	return ParseUID(t.UIDBytes)
+}
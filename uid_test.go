@@ -0,0 +1,104 @@
+// go-pn532
+// Copyright (c) 2025 The Zaparoo Project Contributors.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+//
+// This file is part of go-pn532.
+//
+// go-pn532 is free software; you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// go-pn532 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with go-pn532; if not, write to the Free Software Foundation,
+// Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package pn532
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseUID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		uid  []byte
+		want UIDInfo
+	}{
+		{
+			name: "Single_Size",
+			uid:  []byte{0x1A, 0x2B, 0x3C, 0x4D},
+			want: UIDInfo{Length: 4, CascadeLevel: 1},
+		},
+		{
+			name: "Random",
+			uid:  []byte{0x08, 0x2B, 0x3C, 0x4D},
+			want: UIDInfo{Length: 4, CascadeLevel: 1, Random: true},
+		},
+		{
+			name: "Double_Size_NXP",
+			uid:  []byte{0x04, 0xA1, 0xA2, 0xA3, 0xA4, 0xA5, 0xA6},
+			want: UIDInfo{Length: 7, CascadeLevel: 2, ManufacturerCode: 0x04, Manufacturer: "NXP Semiconductors"},
+		},
+		{
+			name: "Double_Size_Unknown_Manufacturer",
+			uid:  []byte{0xEE, 0xA1, 0xA2, 0xA3, 0xA4, 0xA5, 0xA6},
+			want: UIDInfo{Length: 7, CascadeLevel: 2, ManufacturerCode: 0xEE},
+		},
+		{
+			name: "Triple_Size",
+			uid:  []byte{0x02, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+			want: UIDInfo{Length: 10, CascadeLevel: 3, ManufacturerCode: 0x02, Manufacturer: "STMicroelectronics"},
+		},
+		{name: "Other_Length", uid: []byte{1, 2, 3, 4, 5, 6, 7, 8}, want: UIDInfo{Length: 8}},
+		{name: "Empty", want: UIDInfo{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, ParseUID(tt.uid))
+		})
+	}
+}
+
+func TestUIDInfo_String(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		want string
+		uid  []byte
+	}{
+		{uid: []byte{0x1A, 0x2B, 0x3C, 0x4D}, want: "4 bytes, cascade level 1"},
+		{uid: []byte{0x08, 0x2B, 0x3C, 0x4D}, want: "4 bytes, cascade level 1, random"},
+		{uid: []byte{0x04, 1, 2, 3, 4, 5, 6}, want: "7 bytes, cascade level 2, NXP Semiconductors"},
+		{uid: []byte{0xEE, 1, 2, 3, 4, 5, 6}, want: "7 bytes, cascade level 2, manufacturer 0xEE"},
+		{uid: []byte{1, 2, 3, 4, 5, 6, 7, 8}, want: "8 bytes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, ParseUID(tt.uid).String())
+		})
+	}
+}
+
+func TestDetectedTag_UIDInfo(t *testing.T) {
+	t.Parallel()
+
+	felica := &DetectedTag{Type: TagTypeFeliCa, UIDBytes: []byte{0x04, 1, 2, 3, 4, 5, 6}}
+	assert.Equal(t, UIDInfo{Length: 7}, felica.UIDInfo())
+
+	ntag := &DetectedTag{Type: TagTypeNTAG, UIDBytes: []byte{0x04, 1, 2, 3, 4, 5, 6}}
+	assert.Equal(t, 2, ntag.UIDInfo().CascadeLevel)
+}